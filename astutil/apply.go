@@ -0,0 +1,348 @@
+// Package astutil rewrites ASTs of the parser package, like golang.org/x/tools/go/ast/astutil does for Go.
+package astutil
+
+import (
+	"fmt"
+	"gojo/parser"
+	"reflect"
+)
+
+// An ApplyFunc is invoked by Apply for each node, with a cursor positioned on it. Its result tells whether
+// to keep going, see Apply.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses an AST recursively in the same order as parser.Walk, starting with root, and returns the
+// possibly replaced root.
+//
+// When pre is not nil, it is called for each node before its children are traversed (pre-order). If pre
+// returns false, the children are skipped and post is not called for that node.
+//
+// When post is not nil, it is called for each node after its children are traversed (post-order). If post
+// returns false, the traversal is stopped and Apply returns immediately.
+//
+// Only nodes that are present are visited, e.g., an anonymous function has no name to visit. Nodes replaced or
+// inserted by pre are traversed, nodes replaced or inserted by post are not.
+func Apply(root parser.Node, pre, post ApplyFunc) (result parser.Node) {
+	parent := &struct{ parser.Node }{root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+		result = parent.Node
+	}()
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "Node", nil, root)
+	return
+}
+
+var abort = new(int) // Sentinel panic value to stop the traversal
+
+// A Cursor describes a node encountered during Apply, and can modify the AST around it.
+type Cursor struct {
+	parent parser.Node
+	name   string
+	iter   *iterator // nil unless the node is in a list
+	node   parser.Node
+}
+
+// Node returns the current node.
+func (c *Cursor) Node() parser.Node { return c.node }
+
+// Parent returns the parent of the current node.
+func (c *Cursor) Parent() parser.Node { return c.parent }
+
+// Name returns the name of the field of the parent holding the current node (e.g., "Consequence"). For the
+// root node it is "Node".
+func (c *Cursor) Name() string { return c.name }
+
+// Index returns the index of the current node in the list held by the field of the parent, or -1 when the
+// field is not a list.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+func (c *Cursor) field() reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(c.parent)).FieldByName(c.name)
+}
+
+// Replace replaces the current node with n, or clears an optional field when n is nil. It panics if n does
+// not fit the field (e.g., a statement replacing an expression).
+func (c *Cursor) Replace(n parser.Node) {
+	v := c.field()
+	if idx := c.Index(); idx >= 0 {
+		v = v.Index(idx)
+	}
+	v.Set(nodeValue(n, v.Type()))
+	c.node = n
+}
+
+// Delete removes the current node from the list containing it. It panics if the node is not in a list.
+func (c *Cursor) Delete() {
+	idx := c.listIndex("Delete")
+	v := c.field()
+	length := v.Len()
+	reflect.Copy(v.Slice(idx, length), v.Slice(idx+1, length))
+	v.Index(length - 1).Set(reflect.Zero(v.Type().Elem()))
+	v.SetLen(length - 1)
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current node in the list containing it. The inserted node is not traversed.
+// It panics if the node is not in a list.
+func (c *Cursor) InsertAfter(n parser.Node) {
+	idx := c.listIndex("InsertAfter")
+	v := c.field()
+	length := v.Len()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	reflect.Copy(v.Slice(idx+2, length+1), v.Slice(idx+1, length))
+	v.Index(idx + 1).Set(nodeValue(n, v.Type().Elem()))
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current node in the list containing it. The inserted node is not
+// traversed. It panics if the node is not in a list.
+func (c *Cursor) InsertBefore(n parser.Node) {
+	idx := c.listIndex("InsertBefore")
+	v := c.field()
+	length := v.Len()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	reflect.Copy(v.Slice(idx+1, length+1), v.Slice(idx, length))
+	v.Index(idx).Set(nodeValue(n, v.Type().Elem()))
+	c.iter.index++
+}
+
+func (c *Cursor) listIndex(operation string) int {
+	idx := c.Index()
+	if idx < 0 {
+		panic(fmt.Sprintf("astutil: %s of %s which is not in a list", operation, c.name))
+	}
+	return idx
+}
+
+// nodeValue converts a node to the type of a field, nil being the zero value.
+func nodeValue(n parser.Node, fieldType reflect.Type) reflect.Value {
+	if n == nil {
+		return reflect.Zero(fieldType)
+	}
+	return reflect.ValueOf(n)
+}
+
+// iterator tracks the position in a list while it is modified.
+type iterator struct {
+	index, step int
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent parser.Node, name string, iter *iterator, n parser.Node) {
+	// The cursor is reused, and restored once the node is done
+	saved := a.cursor
+	a.cursor.parent = parent
+	a.cursor.name = name
+	a.cursor.iter = iter
+	a.cursor.node = n
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	switch n := a.cursor.node.(type) {
+	case nil:
+		// Cleared by pre
+	case *parser.Program:
+		a.applyList(n, "Statements")
+
+	// Statements
+	case *parser.VariableDeclaration:
+		a.applyList(n, "Declarations")
+	case *parser.VariableDeclarator:
+		a.apply(n, "Name", nil, n.Name)
+		a.applyOptional(n, "Value", n.Value)
+	case *parser.FunctionDeclaration:
+		a.applyOptional(n, "Name", n.Name)
+		a.applyList(n, "Parameters")
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.BlockStatement:
+		a.applyList(n, "Statements")
+	case *parser.ExpressionStatement:
+		a.apply(n, "Expression", nil, n.Expression)
+	case *parser.IfStatement:
+		a.apply(n, "Condition", nil, n.Condition)
+		a.apply(n, "Consequence", nil, n.Consequence)
+		a.applyOptional(n, "Alternative", n.Alternative)
+	case *parser.WhileStatement:
+		a.apply(n, "Condition", nil, n.Condition)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.ForStatement:
+		a.applyOptional(n, "Init", n.Init)
+		a.applyOptional(n, "Test", n.Test)
+		a.applyOptional(n, "Update", n.Update)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.ForInStatement:
+		a.apply(n, "Left", nil, n.Left)
+		a.apply(n, "Right", nil, n.Right)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.ForOfStatement:
+		a.apply(n, "Left", nil, n.Left)
+		a.apply(n, "Right", nil, n.Right)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.SwitchStatement:
+		a.apply(n, "Expression", nil, n.Expression)
+		a.applyList(n, "Cases")
+	case *parser.CaseClause:
+		a.applyOptional(n, "Condition", n.Condition)
+		a.applyList(n, "Consequent")
+	case *parser.TryStatement:
+		a.apply(n, "Block", nil, n.Block)
+		a.applyOptional(n, "Handler", n.Handler)
+		a.applyOptional(n, "Finalizer", n.Finalizer)
+	case *parser.CatchClause:
+		a.applyOptional(n, "Param", n.Param)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.ThrowStatement:
+		a.apply(n, "Argument", nil, n.Argument)
+	case *parser.ReturnStatement:
+		a.applyOptional(n, "Value", n.Value)
+	case *parser.WithStatement:
+		a.apply(n, "Object", nil, n.Object)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.LabeledStatement:
+		a.apply(n, "Label", nil, n.Label)
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.EmptyStatement:
+		// Leaf
+	case *parser.BreakStatement:
+		a.applyOptional(n, "Label", n.Label)
+	case *parser.ContinueStatement:
+		a.applyOptional(n, "Label", n.Label)
+
+	// Modules
+	case *parser.ImportDeclaration:
+		a.applyList(n, "Specifiers")
+		a.apply(n, "Source", nil, n.Source)
+	case *parser.ImportDefaultSpecifier:
+		a.apply(n, "Local", nil, n.Local)
+	case *parser.ImportNamespaceSpecifier:
+		a.apply(n, "Local", nil, n.Local)
+	case *parser.ImportSpecifier:
+		a.apply(n, "Imported", nil, n.Imported)
+		a.apply(n, "Local", nil, n.Local)
+	case *parser.ExportNamedDeclaration:
+		a.applyOptional(n, "Declaration", n.Declaration)
+		a.applyList(n, "Specifiers")
+		a.applyOptional(n, "Source", n.Source)
+	case *parser.ExportSpecifier:
+		a.apply(n, "Local", nil, n.Local)
+		a.apply(n, "Exported", nil, n.Exported)
+	case *parser.ExportDefaultDeclaration:
+		a.apply(n, "Declaration", nil, n.Declaration)
+	case *parser.ExportAllDeclaration:
+		a.applyOptional(n, "Exported", n.Exported)
+		a.apply(n, "Source", nil, n.Source)
+
+	// Expressions
+	case *parser.Identifier, *parser.IntegerLiteral, *parser.BigIntLiteral, *parser.StringLiteral, *parser.RegExpLiteral, *parser.TemplateElement, *parser.BooleanLiteral,
+		*parser.NullLiteral, *parser.UndefinedLiteral, *parser.ThisExpression:
+		// Leaves
+	case *parser.AssignmentExpression:
+		a.apply(n, "Left", nil, n.Left)
+		a.apply(n, "Value", nil, n.Value)
+	case *parser.TemplateLiteral:
+		a.applyList(n, "Quasis")
+		a.applyList(n, "Expressions")
+	case *parser.TaggedTemplateExpression:
+		a.apply(n, "Tag", nil, n.Tag)
+		a.apply(n, "Quasi", nil, n.Quasi)
+	case *parser.ArrayLiteral:
+		a.applyList(n, "Elements")
+	case *parser.ObjectLiteral:
+		a.applyList(n, "Properties")
+	case *parser.Property:
+		a.apply(n, "Key", nil, n.Key)
+		a.apply(n, "Value", nil, n.Value)
+	case *parser.BinaryExpression:
+		a.apply(n, "Left", nil, n.Left)
+		a.apply(n, "Right", nil, n.Right)
+	case *parser.MemberExpression:
+		a.apply(n, "Object", nil, n.Object)
+		a.apply(n, "Property", nil, n.Property)
+	case *parser.NewExpression:
+		a.apply(n, "Callee", nil, n.Callee)
+		a.applyList(n, "Arguments")
+	case *parser.MetaProperty:
+		a.apply(n, "Meta", nil, n.Meta)
+		a.apply(n, "Property", nil, n.Property)
+	case *parser.CallExpression:
+		a.apply(n, "Function", nil, n.Function)
+		a.applyList(n, "Arguments")
+	case *parser.FunctionExpression:
+		a.applyOptional(n, "Name", n.Name)
+		a.applyList(n, "Parameters")
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.ArrowFunctionExpression:
+		a.applyList(n, "Parameters")
+		a.apply(n, "Body", nil, n.Body)
+	case *parser.AwaitExpression:
+		a.apply(n, "Argument", nil, n.Argument)
+	case *parser.YieldExpression:
+		a.applyOptional(n, "Argument", n.Argument)
+	case *parser.PrefixExpression:
+		a.apply(n, "Right", nil, n.Right)
+	case *parser.UpdateExpression:
+		a.apply(n, "Argument", nil, n.Argument)
+	case *parser.ConditionalExpression:
+		a.apply(n, "Test", nil, n.Test)
+		a.apply(n, "Consequent", nil, n.Consequent)
+		a.apply(n, "Alternate", nil, n.Alternate)
+	case *parser.SequenceExpression:
+		a.applyList(n, "Expressions")
+	case *parser.ImportExpression:
+		a.apply(n, "Source", nil, n.Source)
+
+	default:
+		panic(fmt.Sprintf("astutil.Apply: unexpected node type %T", n))
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+	a.cursor = saved
+}
+
+// applyOptional applies to the node of a field that may be empty, typed nil pointers included.
+func (a *application) applyOptional(parent parser.Node, name string, n parser.Node) {
+	if n != nil && !reflect.ValueOf(n).IsNil() {
+		a.apply(parent, name, nil, n)
+	}
+}
+
+func (a *application) applyList(parent parser.Node, name string) {
+	// The iterator is reused, and restored once the list is done
+	saved := a.iter
+	a.iter.index = 0
+	for {
+		// The list is fetched again each time since it may have been modified
+		v := reflect.Indirect(reflect.ValueOf(parent)).FieldByName(name)
+		if a.iter.index >= v.Len() {
+			break
+		}
+		var n parser.Node
+		if element := v.Index(a.iter.index); !element.IsNil() {
+			n = element.Interface().(parser.Node)
+		}
+		a.iter.step = 1
+		if n != nil {
+			a.apply(parent, name, &a.iter, n)
+		}
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
@@ -0,0 +1,840 @@
+package estree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gojo/lexer"
+	"gojo/parser"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Unmarshal decodes a program from ESTree JSON. Nodes gojo does not support (e.g., classes or
+// destructuring patterns) are reported as errors. Bodies of if and while statements are wrapped in
+// blocks when they are single statements, as gojo requires braces there.
+func Unmarshal(data []byte) (program *parser.Program, err error) {
+	reader := json.NewDecoder(bytes.NewReader(data))
+	reader.UseNumber()
+	var value interface{}
+	if err := reader.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			decodeErr, ok := recovered.(*DecodeError)
+			if !ok {
+				panic(recovered)
+			}
+			program, err = nil, decodeErr
+		}
+	}()
+	d := &decoder{}
+	return d.program(d.node(value, "Program")), nil
+}
+
+// DecodeError is an ESTree node that cannot be converted to gojo's AST.
+type DecodeError struct {
+	Line    int // The line of the node, 0 when it has no loc
+	Message string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Line == 0 {
+		return "estree: " + e.Message
+	}
+	return fmt.Sprintf("estree (Line: %d): %s", e.Line, e.Message)
+}
+
+// jsonNode is a decoded ESTree node.
+type jsonNode map[string]interface{}
+
+type decoder struct {
+	strict bool // Whether the code being decoded is strict mode code
+}
+
+func (d *decoder) fail(node jsonNode, format string, args ...interface{}) {
+	panic(&DecodeError{Line: d.loc(node).Start.Line, Message: fmt.Sprintf(format, args...)})
+}
+
+// node checks that a value is a node of one of the given types, any type when none is given.
+func (d *decoder) node(value interface{}, kinds ...string) jsonNode {
+	var node jsonNode
+	switch value := value.(type) {
+	case jsonNode:
+		node = value
+	case map[string]interface{}:
+		node = value
+	default:
+		panic(&DecodeError{Message: fmt.Sprintf("expected a node, got %v", value)})
+	}
+	kind, ok := node["type"].(string)
+	if !ok {
+		d.fail(node, "node without a type")
+	}
+	if len(kinds) == 0 {
+		return node
+	}
+	for _, expected := range kinds {
+		if kind == expected {
+			return node
+		}
+	}
+	d.fail(node, "expected %s, got %s", strings.Join(kinds, " or "), kind)
+	return nil
+}
+
+func (n jsonNode) kind() string { return n["type"].(string) }
+
+// child returns the node of a property, nil when it is null or missing.
+func (d *decoder) child(node jsonNode, key string, kinds ...string) jsonNode {
+	if node[key] == nil {
+		return nil
+	}
+	return d.node(node[key], kinds...)
+}
+
+// required returns the node of a property which may not be null.
+func (d *decoder) required(node jsonNode, key string, kinds ...string) jsonNode {
+	child := d.child(node, key, kinds...)
+	if child == nil {
+		d.fail(node, "%s of %s is missing", key, node.kind())
+	}
+	return child
+}
+
+// list returns the nodes of an array property.
+func (d *decoder) list(node jsonNode, key string) []jsonNode {
+	values, ok := node[key].([]interface{})
+	if !ok && node[key] != nil {
+		d.fail(node, "%s of %s is not an array", key, node.kind())
+	}
+	var nodes []jsonNode
+	for _, value := range values {
+		if value == nil {
+			d.fail(node, "holes in %s of %s are not supported", key, node.kind())
+		}
+		nodes = append(nodes, d.node(value))
+	}
+	return nodes
+}
+
+func (d *decoder) boolean(node jsonNode, key string) bool {
+	value, _ := node[key].(bool)
+	return value
+}
+
+func (d *decoder) text(node jsonNode, key string) string {
+	value, ok := node[key].(string)
+	if !ok {
+		d.fail(node, "%s of %s is not a string", key, node.kind())
+	}
+	return value
+}
+
+// loc reads the range of a node, the positions are left at 0 for the parts that are missing.
+func (d *decoder) loc(node jsonNode) parser.Loc {
+	var loc parser.Loc
+	loc.Start.Offset = intValue(node["start"])
+	loc.End.Offset = intValue(node["end"])
+	if lines, ok := node["loc"].(map[string]interface{}); ok {
+		for _, side := range []struct {
+			key      string
+			position *parser.Position
+		}{{"start", &loc.Start}, {"end", &loc.End}} {
+			if position, ok := lines[side.key].(map[string]interface{}); ok {
+				side.position.Line = intValue(position["line"])
+				side.position.Column = intValue(position["column"]) + 1
+			}
+		}
+	}
+	return loc
+}
+
+func intValue(value interface{}) int {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0
+	}
+	integer, _ := number.Int64()
+	return int(integer)
+}
+
+// token creates the token a node would start with, with the given text, e.g., the keyword of a statement or
+// the operator of an expression.
+func (d *decoder) token(node jsonNode, text string) lexer.GojoToken {
+	tokenType := lexer.TokenText["identifier"]
+	for _, types := range []map[string]*lexer.GojoTokenType{lexer.TokenKeywords, lexer.TokenPunctuation,
+		lexer.TokenOperators, lexer.TokenLiterals} {
+		if t, ok := types[text]; ok {
+			tokenType = t
+			break
+		}
+	}
+	return d.typedToken(node, tokenType, text)
+}
+
+func (d *decoder) typedToken(node jsonNode, tokenType *lexer.GojoTokenType, text string) lexer.GojoToken {
+	loc := d.loc(node)
+	return lexer.GojoToken{
+		Type:      tokenType,
+		Text:      text,
+		Line:      loc.Start.Line,
+		Column:    loc.Start.Column,
+		Start:     loc.Start.Offset,
+		End:       loc.End.Offset,
+		EndLine:   loc.End.Line,
+		EndColumn: loc.End.Column,
+	}
+}
+
+/**
+ * Programs and statements
+ */
+
+func (d *decoder) program(node jsonNode) *parser.Program {
+	program := &parser.Program{Loc: d.loc(node), SourceType: parser.Script}
+	if node["sourceType"] == "module" {
+		program.SourceType = parser.Module
+		d.strict = true
+	}
+	body := d.list(node, "body")
+	d.strict = d.strict || hasUseStrict(body)
+	program.Strict = d.strict
+	program.Statements = d.statements(body, program.SourceType == parser.Module)
+	return program
+}
+
+// hasUseStrict reports whether the directive prologue of a body has a "use strict" directive.
+func hasUseStrict(body []jsonNode) bool {
+	for _, stmt := range body {
+		directive, ok := stmt["directive"].(string)
+		if !ok {
+			return false
+		}
+		if directive == "use strict" {
+			return true
+		}
+	}
+	return false
+}
+
+// statements decodes a list of statements.
+func (d *decoder) statements(nodes []jsonNode, module bool) []parser.Statement {
+	statements := []parser.Statement{}
+	for _, node := range nodes {
+		if module {
+			statements = append(statements, d.moduleItem(node))
+		} else {
+			statements = append(statements, d.statement(node))
+		}
+	}
+	return statements
+}
+
+// statement decodes a statement.
+func (d *decoder) statement(node jsonNode) parser.Statement {
+	loc := d.loc(node)
+	switch node.kind() {
+	case "EmptyStatement":
+		return &parser.EmptyStatement{Loc: loc, Token: d.token(node, ";")}
+	case "ExpressionStatement":
+		expression := d.expression(d.required(node, "expression"))
+		stmt := &parser.ExpressionStatement{Loc: loc, Token: d.token(node, ""), Expression: expression}
+		if directive, ok := node["directive"].(string); ok {
+			stmt.Directive = directive
+			stmt.Token = d.typedToken(node, lexer.TokenLiterals["string"], directive)
+		}
+		return stmt
+	case "VariableDeclaration":
+		return d.variableDeclaration(node)
+	case "FunctionDeclaration":
+		return d.functionDeclaration(node)
+	case "BlockStatement":
+		return d.block(node)
+	case "IfStatement":
+		stmt := &parser.IfStatement{Loc: loc, Token: d.token(node, "if")}
+		stmt.Condition = d.expression(d.required(node, "test"))
+		stmt.Consequence = d.body(d.required(node, "consequent"))
+		if alternate := d.child(node, "alternate"); alternate != nil {
+			stmt.Alternative = d.body(alternate)
+		}
+		return stmt
+	case "WhileStatement":
+		return &parser.WhileStatement{
+			Loc:       loc,
+			Token:     d.token(node, "while"),
+			Condition: d.expression(d.required(node, "test")),
+			Body:      d.body(d.required(node, "body")),
+		}
+	case "ForStatement":
+		stmt := &parser.ForStatement{Loc: loc, Token: d.token(node, "for")}
+		if init := d.child(node, "init"); init != nil {
+			if init.kind() == "VariableDeclaration" {
+				stmt.Init = d.variableDeclaration(init)
+			} else {
+				stmt.Init = d.expression(init)
+			}
+		}
+		if test := d.child(node, "test"); test != nil {
+			stmt.Test = d.expression(test)
+		}
+		if update := d.child(node, "update"); update != nil {
+			stmt.Update = d.expression(update)
+		}
+		stmt.Body = d.statement(d.required(node, "body"))
+		return stmt
+	case "ForInStatement":
+		stmt := &parser.ForInStatement{Loc: loc, Token: d.token(node, "for")}
+		stmt.Left = d.forLeft(d.required(node, "left"), "for...in")
+		stmt.Right = d.expression(d.required(node, "right"))
+		stmt.Body = d.statement(d.required(node, "body"))
+		return stmt
+	case "ForOfStatement":
+		stmt := &parser.ForOfStatement{Loc: loc, Token: d.token(node, "for"), Await: d.boolean(node, "await")}
+		stmt.Left = d.forLeft(d.required(node, "left"), "for...of")
+		stmt.Right = d.expression(d.required(node, "right"))
+		stmt.Body = d.statement(d.required(node, "body"))
+		return stmt
+	case "SwitchStatement":
+		stmt := &parser.SwitchStatement{Loc: loc, Token: d.token(node, "switch")}
+		stmt.Expression = d.expression(d.required(node, "discriminant"))
+		stmt.Cases = []*parser.CaseClause{}
+		for _, caseNode := range d.list(node, "cases") {
+			d.node(caseNode, "SwitchCase")
+			clause := &parser.CaseClause{Loc: d.loc(caseNode), Token: d.token(caseNode, "case")}
+			if test := d.child(caseNode, "test"); test != nil {
+				clause.Condition = d.expression(test)
+			} else {
+				clause.Token = d.token(caseNode, "default")
+			}
+			clause.Consequent = d.statements(d.list(caseNode, "consequent"), false)
+			stmt.Cases = append(stmt.Cases, clause)
+		}
+		return stmt
+	case "BreakStatement":
+		return &parser.BreakStatement{Loc: loc, Token: d.token(node, "break"), Label: d.optionalIdentifier(node,
+			"label")}
+	case "ContinueStatement":
+		return &parser.ContinueStatement{Loc: loc, Token: d.token(node, "continue"),
+			Label: d.optionalIdentifier(node, "label")}
+	case "LabeledStatement":
+		label := d.identifier(d.required(node, "label"))
+		return &parser.LabeledStatement{Loc: loc, Token: label.Token, Label: label,
+			Body: d.statement(d.required(node, "body"))}
+	case "ReturnStatement":
+		stmt := &parser.ReturnStatement{Loc: loc, Token: d.token(node, "return")}
+		if argument := d.child(node, "argument"); argument != nil {
+			stmt.Value = d.expression(argument)
+		}
+		return stmt
+	case "ThrowStatement":
+		return &parser.ThrowStatement{Loc: loc, Token: d.token(node, "throw"),
+			Argument: d.expression(d.required(node, "argument"))}
+	case "TryStatement":
+		stmt := &parser.TryStatement{Loc: loc, Token: d.token(node, "try")}
+		stmt.Block = d.block(d.required(node, "block", "BlockStatement"))
+		if handler := d.child(node, "handler", "CatchClause"); handler != nil {
+			stmt.Handler = &parser.CatchClause{
+				Loc:   d.loc(handler),
+				Token: d.token(handler, "catch"),
+				Param: d.optionalIdentifier(handler, "param"),
+				Body:  d.block(d.required(handler, "body", "BlockStatement")),
+			}
+		}
+		if finalizer := d.child(node, "finalizer", "BlockStatement"); finalizer != nil {
+			stmt.Finalizer = d.block(finalizer)
+		}
+		if stmt.Handler == nil && stmt.Finalizer == nil {
+			d.fail(node, "TryStatement without handler or finalizer")
+		}
+		return stmt
+	case "WithStatement":
+		return &parser.WithStatement{Loc: loc, Token: d.token(node, "with"),
+			Object: d.expression(d.required(node, "object")), Body: d.statement(d.required(node, "body"))}
+	}
+	d.fail(node, "unsupported statement %s", node.kind())
+	return nil
+}
+
+// body decodes the body of an if or a while statement, which gojo keeps in a block.
+func (d *decoder) body(node jsonNode) *parser.BlockStatement {
+	stmt := d.statement(node)
+	if block, ok := stmt.(*parser.BlockStatement); ok {
+		return block
+	}
+	return &parser.BlockStatement{Loc: d.loc(node), Token: d.token(node, "{"), Statements: []parser.Statement{stmt}}
+}
+
+func (d *decoder) block(node jsonNode) *parser.BlockStatement {
+	return &parser.BlockStatement{
+		Loc:        d.loc(node),
+		Token:      d.token(node, "{"),
+		Statements: d.statements(d.list(node, "body"), false),
+	}
+}
+
+func (d *decoder) variableDeclaration(node jsonNode) *parser.VariableDeclaration {
+	kind := d.text(node, "kind")
+	if kind != "var" && kind != "let" && kind != "const" {
+		d.fail(node, "unsupported declaration kind %s", kind)
+	}
+	declaration := &parser.VariableDeclaration{Loc: d.loc(node), Token: d.token(node, kind),
+		IsConstant: kind == "const"}
+	for _, declaratorNode := range d.list(node, "declarations") {
+		d.node(declaratorNode, "VariableDeclarator")
+		name := d.identifier(d.required(declaratorNode, "id"))
+		declarator := &parser.VariableDeclarator{Loc: d.loc(declaratorNode), Token: name.Token, Name: name}
+		if init := d.child(declaratorNode, "init"); init != nil {
+			declarator.Value = d.expression(init)
+		}
+		declaration.Declarations = append(declaration.Declarations, declarator)
+	}
+	if len(declaration.Declarations) == 0 {
+		d.fail(node, "VariableDeclaration without declarations")
+	}
+	return declaration
+}
+
+// forLeft decodes the left side of a for...in or for...of loop: a declaration of one binding without
+// initializer, or an assignment target.
+func (d *decoder) forLeft(node jsonNode, loop string) parser.Node {
+	if node.kind() != "VariableDeclaration" {
+		return d.assignmentTarget(node)
+	}
+	declaration := d.variableDeclaration(node)
+	if len(declaration.Declarations) != 1 || declaration.Declarations[0].Value != nil {
+		d.fail(node, "the declaration of a %s loop must have one binding without initializer", loop)
+	}
+	return declaration
+}
+
+func (d *decoder) functionDeclaration(node jsonNode) *parser.FunctionDeclaration {
+	function := d.function(node)
+	return &parser.FunctionDeclaration{
+		Loc:        function.Loc,
+		Token:      function.Token,
+		Name:       function.Name,
+		Parameters: function.Parameters,
+		Body:       function.Body,
+		Generator:  function.Generator,
+		Async:      function.Async,
+		Strict:     function.Strict,
+	}
+}
+
+// function decodes a function declaration or expression, its body is strict when the function is in
+// strict mode code or starts with a "use strict" directive.
+func (d *decoder) function(node jsonNode) *parser.FunctionExpression {
+	function := &parser.FunctionExpression{
+		Loc:        d.loc(node),
+		Token:      d.token(node, "function"),
+		Name:       d.optionalIdentifier(node, "id"),
+		Parameters: d.parameters(node),
+		Generator:  d.boolean(node, "generator"),
+		Async:      d.boolean(node, "async"),
+	}
+	body := d.required(node, "body", "BlockStatement")
+	strict := d.strict
+	d.strict = d.strict || hasUseStrict(d.list(body, "body"))
+	function.Strict = d.strict
+	function.Body = d.block(body)
+	d.strict = strict
+	return function
+}
+
+func (d *decoder) parameters(node jsonNode) []*parser.Identifier {
+	parameters := []*parser.Identifier{}
+	for _, parameter := range d.list(node, "params") {
+		parameters = append(parameters, d.identifier(parameter))
+	}
+	return parameters
+}
+
+/**
+ * Modules
+ */
+
+func (d *decoder) moduleItem(node jsonNode) parser.Statement {
+	loc := d.loc(node)
+	switch node.kind() {
+	case "ImportDeclaration":
+		decl := &parser.ImportDeclaration{Loc: loc, Token: d.token(node, "import"), Source: d.source(node)}
+		for _, specifier := range d.list(node, "specifiers") {
+			local := d.identifier(d.required(specifier, "local"))
+			specifierLoc := d.loc(specifier)
+			switch specifier.kind() {
+			case "ImportDefaultSpecifier":
+				decl.Specifiers = append(decl.Specifiers, &parser.ImportDefaultSpecifier{Loc: specifierLoc,
+					Token: local.Token, Local: local})
+			case "ImportNamespaceSpecifier":
+				decl.Specifiers = append(decl.Specifiers, &parser.ImportNamespaceSpecifier{Loc: specifierLoc,
+					Token: d.token(specifier, "*"), Local: local})
+			case "ImportSpecifier":
+				imported := d.identifier(d.required(specifier, "imported"))
+				decl.Specifiers = append(decl.Specifiers, &parser.ImportSpecifier{Loc: specifierLoc,
+					Token: imported.Token, Imported: imported, Local: local})
+			default:
+				d.fail(specifier, "unsupported import specifier %s", specifier.kind())
+			}
+		}
+		return decl
+	case "ExportNamedDeclaration":
+		decl := &parser.ExportNamedDeclaration{Loc: loc, Token: d.token(node, "export")}
+		if declaration := d.child(node, "declaration", "VariableDeclaration",
+			"FunctionDeclaration"); declaration != nil {
+			decl.Declaration = d.statement(declaration)
+			return decl
+		}
+		for _, specifier := range d.list(node, "specifiers") {
+			d.node(specifier, "ExportSpecifier")
+			local := d.identifier(d.required(specifier, "local"))
+			decl.Specifiers = append(decl.Specifiers, &parser.ExportSpecifier{Loc: d.loc(specifier),
+				Token: local.Token, Local: local, Exported: d.identifier(d.required(specifier, "exported"))})
+		}
+		if node["source"] != nil {
+			decl.Source = d.source(node)
+		}
+		return decl
+	case "ExportDefaultDeclaration":
+		decl := &parser.ExportDefaultDeclaration{Loc: loc, Token: d.token(node, "export")}
+		declaration := d.required(node, "declaration")
+		if declaration.kind() == "FunctionDeclaration" {
+			decl.Declaration = d.functionDeclaration(declaration)
+		} else {
+			decl.Declaration = d.expression(declaration)
+		}
+		return decl
+	case "ExportAllDeclaration":
+		return &parser.ExportAllDeclaration{Loc: loc, Token: d.token(node, "export"),
+			Exported: d.optionalIdentifier(node, "exported"), Source: d.source(node)}
+	}
+	return d.statement(node)
+}
+
+// source decodes the module specifier of an import or export declaration.
+func (d *decoder) source(node jsonNode) *parser.StringLiteral {
+	literal, ok := d.literal(d.required(node, "source", "Literal")).(*parser.StringLiteral)
+	if !ok {
+		d.fail(node, "the source of %s is not a string", node.kind())
+	}
+	return literal
+}
+
+/**
+ * Expressions
+ */
+
+var unaryOperators = map[string]bool{"!": true, "~": true, "+": true, "-": true, "typeof": true, "void": true,
+	"delete": true}
+
+func (d *decoder) expression(node jsonNode) parser.Expression {
+	loc := d.loc(node)
+	switch node.kind() {
+	case "Identifier":
+		if node["name"] == "undefined" {
+			return &parser.UndefinedLiteral{Loc: loc, Token: d.token(node, "undefined")}
+		}
+		return d.identifier(node)
+	case "Literal":
+		return d.literal(node)
+	case "ParenthesizedExpression":
+		return d.expression(d.required(node, "expression"))
+	case "TemplateLiteral":
+		return d.template(node)
+	case "TaggedTemplateExpression":
+		return &parser.TaggedTemplateExpression{Loc: loc, Token: d.token(node, "`"),
+			Tag: d.expression(d.required(node, "tag")), Quasi: d.template(d.required(node, "quasi",
+				"TemplateLiteral"))}
+	case "ThisExpression":
+		return &parser.ThisExpression{Loc: loc, Token: d.token(node, "this")}
+	case "ArrayExpression":
+		array := &parser.ArrayLiteral{Loc: loc, Token: d.token(node, "[")}
+		for _, element := range d.list(node, "elements") {
+			array.Elements = append(array.Elements, d.expression(element))
+		}
+		return array
+	case "ObjectExpression":
+		object := &parser.ObjectLiteral{Loc: loc, Token: d.token(node, "{")}
+		for _, propertyNode := range d.list(node, "properties") {
+			object.Properties = append(object.Properties, d.property(propertyNode))
+		}
+		return object
+	case "FunctionExpression":
+		return d.function(node)
+	case "ArrowFunctionExpression":
+		arrow := &parser.ArrowFunctionExpression{Loc: loc, Token: d.token(node, "=>"), Parameters: d.parameters(node),
+			Async: d.boolean(node, "async")}
+		body := d.required(node, "body")
+		strict := d.strict
+		if body.kind() == "BlockStatement" {
+			d.strict = d.strict || hasUseStrict(d.list(body, "body"))
+			arrow.Strict = d.strict
+			arrow.Body = d.block(body)
+		} else {
+			arrow.Strict = d.strict
+			arrow.Body = d.expression(body)
+		}
+		d.strict = strict
+		return arrow
+	case "BinaryExpression", "LogicalExpression":
+		operator := d.text(node, "operator")
+		return &parser.BinaryExpression{Loc: loc, Token: d.token(node, operator),
+			Left: d.expression(d.required(node, "left")), Operator: operator,
+			Right: d.expression(d.required(node, "right"))}
+	case "AssignmentExpression":
+		operator := d.text(node, "operator")
+		return &parser.AssignmentExpression{Loc: loc, Token: d.token(node, operator), Operator: operator,
+			Left: d.assignmentTarget(d.required(node, "left")), Value: d.expression(d.required(node, "right"))}
+	case "UnaryExpression":
+		operator := d.text(node, "operator")
+		if !unaryOperators[operator] {
+			d.fail(node, "unsupported unary operator %s", operator)
+		}
+		return &parser.PrefixExpression{Loc: loc, Token: d.token(node, operator), Operator: operator,
+			Right: d.expression(d.required(node, "argument"))}
+	case "UpdateExpression":
+		operator := d.text(node, "operator")
+		return &parser.UpdateExpression{Loc: loc, Token: d.token(node, operator), Operator: operator,
+			Prefix: d.boolean(node, "prefix"), Argument: d.assignmentTarget(d.required(node, "argument"))}
+	case "ConditionalExpression":
+		return &parser.ConditionalExpression{Loc: loc, Token: d.token(node, "?"),
+			Test:       d.expression(d.required(node, "test")),
+			Consequent: d.expression(d.required(node, "consequent")),
+			Alternate:  d.expression(d.required(node, "alternate"))}
+	case "SequenceExpression":
+		sequence := &parser.SequenceExpression{Loc: loc, Token: d.token(node, ",")}
+		for _, expression := range d.list(node, "expressions") {
+			sequence.Expressions = append(sequence.Expressions, d.expression(expression))
+		}
+		if len(sequence.Expressions) < 2 {
+			d.fail(node, "SequenceExpression with less than two expressions")
+		}
+		return sequence
+	case "MemberExpression":
+		if d.boolean(node, "optional") {
+			d.fail(node, "optional chaining is not supported")
+		}
+		member := &parser.MemberExpression{Loc: loc, Token: d.token(node, "."),
+			Object: d.expression(d.required(node, "object")), Computed: d.boolean(node, "computed")}
+		if member.Computed {
+			member.Token = d.token(node, "[")
+			member.Property = d.expression(d.required(node, "property"))
+		} else {
+			member.Property = d.identifier(d.required(node, "property"))
+		}
+		return member
+	case "CallExpression":
+		if d.boolean(node, "optional") {
+			d.fail(node, "optional chaining is not supported")
+		}
+		call := &parser.CallExpression{Loc: loc, Token: d.token(node, "("),
+			Function: d.expression(d.required(node, "callee"))}
+		call.Arguments = d.arguments(node)
+		return call
+	case "NewExpression":
+		return &parser.NewExpression{Loc: loc, Token: d.token(node, "new"),
+			Callee: d.expression(d.required(node, "callee")), Arguments: d.arguments(node)}
+	case "MetaProperty":
+		meta := d.identifier(d.required(node, "meta"))
+		property := d.identifier(d.required(node, "property"))
+		if meta.Value+"."+property.Value != "new.target" && meta.Value+"."+property.Value != "import.meta" {
+			d.fail(node, "unsupported meta property %s.%s", meta.Value, property.Value)
+		}
+		return &parser.MetaProperty{Loc: loc, Token: d.token(node, meta.Value), Meta: meta, Property: property}
+	case "AwaitExpression":
+		return &parser.AwaitExpression{Loc: loc, Token: d.token(node, "await"),
+			Argument: d.expression(d.required(node, "argument"))}
+	case "YieldExpression":
+		yield := &parser.YieldExpression{Loc: loc, Token: d.token(node, "yield"), Delegate: d.boolean(node,
+			"delegate")}
+		if argument := d.child(node, "argument"); argument != nil {
+			yield.Argument = d.expression(argument)
+		}
+		return yield
+	case "ImportExpression":
+		return &parser.ImportExpression{Loc: loc, Token: d.token(node, "import"),
+			Source: d.expression(d.required(node, "source"))}
+	}
+	d.fail(node, "unsupported expression %s", node.kind())
+	return nil
+}
+
+func (d *decoder) identifier(node jsonNode) *parser.Identifier {
+	d.node(node, "Identifier")
+	name := d.text(node, "name")
+	return &parser.Identifier{Loc: d.loc(node), Token: d.typedToken(node, lexer.TokenText["identifier"], name),
+		Value: name}
+}
+
+func (d *decoder) optionalIdentifier(node jsonNode, key string) *parser.Identifier {
+	if child := d.child(node, key); child != nil {
+		return d.identifier(child)
+	}
+	return nil
+}
+
+// assignmentTarget decodes the target of an assignment, which can only be an identifier or a property.
+func (d *decoder) assignmentTarget(node jsonNode) parser.Expression {
+	if node.kind() != "Identifier" && node.kind() != "MemberExpression" {
+		d.fail(node, "unsupported assignment target %s", node.kind())
+	}
+	return d.expression(node)
+}
+
+func (d *decoder) arguments(node jsonNode) []parser.Expression {
+	var arguments []parser.Expression
+	for _, argument := range d.list(node, "arguments") {
+		arguments = append(arguments, d.expression(argument))
+	}
+	return arguments
+}
+
+// literal decodes a literal, numbers must be integers.
+func (d *decoder) literal(node jsonNode) parser.Expression {
+	loc := d.loc(node)
+	if regex, ok := node["regex"].(map[string]interface{}); ok {
+		pattern, _ := regex["pattern"].(string)
+		flags, _ := regex["flags"].(string)
+		return &parser.RegExpLiteral{Loc: loc, Token: d.typedToken(node, lexer.TokenLiterals["regexp"],
+			"/"+pattern+"/"+flags), Pattern: pattern, Flags: flags}
+	}
+	if digits, ok := node["bigint"].(string); ok {
+		return d.bigInt(node, loc, digits)
+	}
+
+	switch value := node["value"].(type) {
+	case nil:
+		// JSON has no infinities, a number literal such as 1e400 has a null value
+		if raw, ok := node["raw"].(string); ok && math.IsInf(parser.NumberValue(raw), 1) {
+			return &parser.IntegerLiteral{Loc: loc, Token: d.typedToken(node, lexer.TokenLiterals["number"], raw),
+				Value: math.Inf(1)}
+		}
+		return &parser.NullLiteral{Loc: loc, Token: d.token(node, "null")}
+	case bool:
+		text := fmt.Sprint(value)
+		return &parser.BooleanLiteral{Loc: loc, Token: d.token(node, text), Value: value}
+	case string:
+		token := d.typedToken(node, lexer.TokenLiterals["string"], value)
+		token.Raw, _ = node["raw"].(string)
+		return &parser.StringLiteral{Loc: loc, Token: token, Value: value}
+	case json.Number:
+		number, err := value.Float64()
+		if err != nil {
+			d.fail(node, "unsupported number %s", value)
+		}
+		// The raw text is kept when it has the same value, e.g., 0x1f
+		text := value.String()
+		if raw, ok := node["raw"].(string); ok && parser.NumberValue(raw) == number {
+			text = raw
+		}
+		return &parser.IntegerLiteral{Loc: loc, Token: d.typedToken(node, lexer.TokenLiterals["number"], text),
+			Value: number}
+	}
+	d.fail(node, "unsupported literal %v", node["value"])
+	return nil
+}
+
+// bigInt decodes a BigInt literal from the decimal digits of its value.
+func (d *decoder) bigInt(node jsonNode, loc parser.Loc, digits string) *parser.BigIntLiteral {
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		d.fail(node, "invalid bigint %q", digits)
+	}
+	text := digits + "n"
+	if raw, ok := node["raw"].(string); ok {
+		if rawValue, ok := new(big.Int).SetString(strings.TrimSuffix(raw, "n"), 0); ok && rawValue.Cmp(value) == 0 {
+			text = raw
+		}
+	}
+	return &parser.BigIntLiteral{Loc: loc, Token: d.typedToken(node, lexer.TokenLiterals["bigint"], text),
+		Value: value}
+}
+
+func (d *decoder) template(node jsonNode) *parser.TemplateLiteral {
+	template := &parser.TemplateLiteral{Loc: d.loc(node), Token: d.token(node, "`")}
+	for _, quasi := range d.list(node, "quasis") {
+		d.node(quasi, "TemplateElement")
+		value, ok := quasi["value"].(map[string]interface{})
+		if !ok {
+			d.fail(quasi, "TemplateElement without value")
+		}
+		element := &parser.TemplateElement{Loc: d.loc(quasi), Tail: d.boolean(quasi, "tail")}
+		element.Raw, _ = value["raw"].(string)
+		if cooked, ok := value["cooked"].(string); ok {
+			element.Cooked = &cooked
+		}
+		element.Loc = widenTemplateElementLoc(element)
+		element.Token = d.typedToken(quasi, lexer.TokenLiterals["template"], element.Raw)
+		template.Quasis = append(template.Quasis, element)
+	}
+	for _, expression := range d.list(node, "expressions") {
+		template.Expressions = append(template.Expressions, d.expression(expression))
+	}
+	if len(template.Quasis) != len(template.Expressions)+1 {
+		d.fail(node, "TemplateLiteral must have one more quasi than expressions")
+	}
+	return template
+}
+
+// widenTemplateElementLoc extends the range of a template chunk to its delimiters, as gojo locates it.
+func widenTemplateElementLoc(element *parser.TemplateElement) parser.Loc {
+	loc := element.Loc
+	if loc.End.Line == 0 {
+		return loc
+	}
+	closing := 2
+	if element.Tail {
+		closing = 1
+	}
+	loc.Start.Offset--
+	loc.Start.Column--
+	loc.End.Offset += closing
+	loc.End.Column += closing
+	return loc
+}
+
+func (d *decoder) property(node jsonNode) *parser.Property {
+	d.node(node, "Property")
+	if kind, _ := node["kind"].(string); kind != "init" {
+		d.fail(node, "unsupported property kind %s", kind)
+	}
+	property := &parser.Property{
+		Loc:       d.loc(node),
+		Computed:  d.boolean(node, "computed"),
+		Shorthand: d.boolean(node, "shorthand"),
+		Method:    d.boolean(node, "method"),
+	}
+	key := d.required(node, "key")
+	switch {
+	case property.Computed:
+		property.Key = d.expression(key)
+	case key.kind() == "Identifier":
+		property.Key = d.identifier(key)
+	case key.kind() == "Literal":
+		property.Key = d.literal(key)
+		switch property.Key.(type) {
+		case *parser.StringLiteral, *parser.IntegerLiteral, *parser.BigIntLiteral:
+		default:
+			d.fail(key, "unsupported property key %s", property.Key.String())
+		}
+	default:
+		d.fail(key, "unsupported property key %s", key.kind())
+	}
+	property.Token = d.typedToken(node, lexer.TokenText["identifier"], property.Key.TokenLiteral())
+
+	// Shorthand properties share the identifier of the key and the value
+	if property.Shorthand {
+		property.Value = property.Key
+		return property
+	}
+	value := d.required(node, "value")
+	if property.Method {
+		property.Value = d.function(d.node(value, "FunctionExpression"))
+	} else {
+		property.Value = d.expression(value)
+	}
+	return property
+}
@@ -0,0 +1,373 @@
+// Package estree converts gojo's AST from and to ESTree JSON, the AST format of acorn and esprima, so
+// programs can be compared with their fixtures or exchanged with other tools.
+//
+// Nodes have the byte offsets of their range in start and end, and a loc with lines counted from 1 and
+// columns from 0. Templates are located like in acorn: their elements exclude the backticks and the
+// ${ and } around substitutions.
+package estree
+
+import (
+	"bytes"
+	"encoding/json"
+	"gojo/parser"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Marshal encodes a program as ESTree JSON.
+func Marshal(program *parser.Program) ([]byte, error) {
+	return json.Marshal(encode(program))
+}
+
+// MarshalIndent is like Marshal, with each property on its own line.
+func MarshalIndent(program *parser.Program, indent string) ([]byte, error) {
+	data, err := Marshal(program)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// object is a JSON object which keeps its properties in order, so nodes start with their type and range.
+type object []property
+
+type property struct {
+	key   string
+	value interface{}
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for idx, property := range o {
+		if idx > 0 {
+			out.WriteByte(',')
+		}
+		key, _ := json.Marshal(property.key)
+		value, err := json.Marshal(property.value)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(key)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// encodeNode creates the object of a node with the given type and properties.
+func encodeNode(loc parser.Loc, kind string, properties ...property) object {
+	node := object{
+		{"type", kind},
+		{"start", loc.Start.Offset},
+		{"end", loc.End.Offset},
+		{"loc", object{{"start", encodePosition(loc.Start)}, {"end", encodePosition(loc.End)}}},
+	}
+	return append(node, properties...)
+}
+
+func encodePosition(position parser.Position) object {
+	return object{{"line", position.Line}, {"column", position.Column - 1}}
+}
+
+// encode converts a node and its children, nil nodes are null.
+func encode(node parser.Node) interface{} {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return nil
+	}
+	loc := node.Location()
+
+	switch node := node.(type) {
+	case *parser.Program:
+		return encodeNode(loc, "Program",
+			property{"body", encodeList(node.Statements)},
+			property{"sourceType", node.SourceType.String()})
+
+	// Statements
+	case *parser.ExpressionStatement:
+		statement := encodeNode(loc, "ExpressionStatement", property{"expression", encode(node.Expression)})
+		if node.Directive != "" {
+			statement = append(statement, property{"directive", node.Directive})
+		}
+		return statement
+	case *parser.VariableDeclaration:
+		return encodeNode(loc, "VariableDeclaration",
+			property{"declarations", encodeList(node.Declarations)},
+			property{"kind", node.Token.Text})
+	case *parser.VariableDeclarator:
+		return encodeNode(loc, "VariableDeclarator",
+			property{"id", encode(node.Name)},
+			property{"init", encode(node.Value)})
+	case *parser.FunctionDeclaration:
+		return encodeFunction(loc, "FunctionDeclaration", node.Name, node.Parameters, node.Body, node.Generator,
+			node.Async)
+	case *parser.BlockStatement:
+		return encodeNode(loc, "BlockStatement", property{"body", encodeList(node.Statements)})
+	case *parser.IfStatement:
+		return encodeNode(loc, "IfStatement",
+			property{"test", encode(node.Condition)},
+			property{"consequent", encode(node.Consequence)},
+			property{"alternate", encode(node.Alternative)})
+	case *parser.WhileStatement:
+		return encodeNode(loc, "WhileStatement",
+			property{"test", encode(node.Condition)},
+			property{"body", encode(node.Body)})
+	case *parser.ForStatement:
+		return encodeNode(loc, "ForStatement",
+			property{"init", encode(node.Init)},
+			property{"test", encode(node.Test)},
+			property{"update", encode(node.Update)},
+			property{"body", encode(node.Body)})
+	case *parser.ForInStatement:
+		return encodeNode(loc, "ForInStatement",
+			property{"left", encode(node.Left)},
+			property{"right", encode(node.Right)},
+			property{"body", encode(node.Body)})
+	case *parser.ForOfStatement:
+		return encodeNode(loc, "ForOfStatement",
+			property{"await", node.Await},
+			property{"left", encode(node.Left)},
+			property{"right", encode(node.Right)},
+			property{"body", encode(node.Body)})
+	case *parser.SwitchStatement:
+		return encodeNode(loc, "SwitchStatement",
+			property{"discriminant", encode(node.Expression)},
+			property{"cases", encodeList(node.Cases)})
+	case *parser.CaseClause:
+		return encodeNode(loc, "SwitchCase",
+			property{"test", encode(node.Condition)},
+			property{"consequent", encodeList(node.Consequent)})
+	case *parser.EmptyStatement:
+		return encodeNode(loc, "EmptyStatement")
+	case *parser.BreakStatement:
+		return encodeNode(loc, "BreakStatement", property{"label", encode(node.Label)})
+	case *parser.ContinueStatement:
+		return encodeNode(loc, "ContinueStatement", property{"label", encode(node.Label)})
+	case *parser.LabeledStatement:
+		return encodeNode(loc, "LabeledStatement",
+			property{"label", encode(node.Label)},
+			property{"body", encode(node.Body)})
+	case *parser.ReturnStatement:
+		return encodeNode(loc, "ReturnStatement", property{"argument", encode(node.Value)})
+	case *parser.ThrowStatement:
+		return encodeNode(loc, "ThrowStatement", property{"argument", encode(node.Argument)})
+	case *parser.TryStatement:
+		return encodeNode(loc, "TryStatement",
+			property{"block", encode(node.Block)},
+			property{"handler", encode(node.Handler)},
+			property{"finalizer", encode(node.Finalizer)})
+	case *parser.CatchClause:
+		return encodeNode(loc, "CatchClause",
+			property{"param", encode(node.Param)},
+			property{"body", encode(node.Body)})
+	case *parser.WithStatement:
+		return encodeNode(loc, "WithStatement",
+			property{"object", encode(node.Object)},
+			property{"body", encode(node.Body)})
+
+	// Modules
+	case *parser.ImportDeclaration:
+		return encodeNode(loc, "ImportDeclaration",
+			property{"specifiers", encodeList(node.Specifiers)},
+			property{"source", encode(node.Source)})
+	case *parser.ImportDefaultSpecifier:
+		return encodeNode(loc, "ImportDefaultSpecifier", property{"local", encode(node.Local)})
+	case *parser.ImportNamespaceSpecifier:
+		return encodeNode(loc, "ImportNamespaceSpecifier", property{"local", encode(node.Local)})
+	case *parser.ImportSpecifier:
+		return encodeNode(loc, "ImportSpecifier",
+			property{"imported", encode(node.Imported)},
+			property{"local", encode(node.Local)})
+	case *parser.ExportNamedDeclaration:
+		return encodeNode(loc, "ExportNamedDeclaration",
+			property{"declaration", encode(node.Declaration)},
+			property{"specifiers", encodeList(node.Specifiers)},
+			property{"source", encode(node.Source)})
+	case *parser.ExportSpecifier:
+		return encodeNode(loc, "ExportSpecifier",
+			property{"local", encode(node.Local)},
+			property{"exported", encode(node.Exported)})
+	case *parser.ExportDefaultDeclaration:
+		return encodeNode(loc, "ExportDefaultDeclaration", property{"declaration", encode(node.Declaration)})
+	case *parser.ExportAllDeclaration:
+		return encodeNode(loc, "ExportAllDeclaration",
+			property{"exported", encode(node.Exported)},
+			property{"source", encode(node.Source)})
+	case *parser.ImportExpression:
+		return encodeNode(loc, "ImportExpression", property{"source", encode(node.Source)})
+
+	// Expressions
+	case *parser.Identifier:
+		return encodeNode(loc, "Identifier", property{"name", node.Value})
+	case *parser.UndefinedLiteral:
+		return encodeNode(loc, "Identifier", property{"name", "undefined"})
+	case *parser.IntegerLiteral:
+		var value interface{} = node.Value
+		// JSON has no infinities, JSON.stringify writes null like for a literal such as 1e400
+		if math.IsInf(node.Value, 0) {
+			value = nil
+		}
+		return encodeNode(loc, "Literal", property{"value", value}, property{"raw", node.Token.Text})
+	case *parser.BigIntLiteral:
+		// JSON has no BigInts, the value is given as decimal digits
+		return encodeNode(loc, "Literal", property{"value", nil}, property{"raw", node.Token.Text},
+			property{"bigint", node.Value.String()})
+	case *parser.StringLiteral:
+		return encodeNode(loc, "Literal", property{"value", node.Value}, property{"raw", stringRaw(node)})
+	case *parser.BooleanLiteral:
+		return encodeNode(loc, "Literal", property{"value", node.Value}, property{"raw", node.Token.Text})
+	case *parser.NullLiteral:
+		return encodeNode(loc, "Literal", property{"value", nil}, property{"raw", "null"})
+	case *parser.RegExpLiteral:
+		// The value would be a RegExp object, which JSON has no equivalent of
+		return encodeNode(loc, "Literal",
+			property{"value", nil},
+			property{"raw", node.Token.Text},
+			property{"regex", object{{"pattern", node.Pattern}, {"flags", node.Flags}}})
+	case *parser.TemplateLiteral:
+		return encodeNode(loc, "TemplateLiteral",
+			property{"quasis", encodeList(node.Quasis)},
+			property{"expressions", encodeList(node.Expressions)})
+	case *parser.TemplateElement:
+		return encodeNode(templateElementLoc(node), "TemplateElement",
+			property{"value", object{{"raw", node.Raw}, {"cooked", node.Cooked}}},
+			property{"tail", node.Tail})
+	case *parser.TaggedTemplateExpression:
+		return encodeNode(loc, "TaggedTemplateExpression",
+			property{"tag", encode(node.Tag)},
+			property{"quasi", encode(node.Quasi)})
+	case *parser.ArrayLiteral:
+		return encodeNode(loc, "ArrayExpression", property{"elements", encodeList(node.Elements)})
+	case *parser.ObjectLiteral:
+		return encodeNode(loc, "ObjectExpression", property{"properties", encodeList(node.Properties)})
+	case *parser.Property:
+		return encodeNode(loc, "Property",
+			property{"method", node.Method},
+			property{"shorthand", node.Shorthand},
+			property{"computed", node.Computed},
+			property{"key", encode(node.Key)},
+			property{"value", encode(node.Value)},
+			property{"kind", "init"})
+	case *parser.FunctionExpression:
+		return encodeFunction(loc, "FunctionExpression", node.Name, node.Parameters, node.Body, node.Generator,
+			node.Async)
+	case *parser.ArrowFunctionExpression:
+		_, block := node.Body.(*parser.BlockStatement)
+		return encodeNode(loc, "ArrowFunctionExpression",
+			property{"id", nil},
+			property{"expression", !block},
+			property{"generator", false},
+			property{"async", node.Async},
+			property{"params", encodeList(node.Parameters)},
+			property{"body", encode(node.Body)})
+	case *parser.BinaryExpression:
+		kind := "BinaryExpression"
+		switch node.Operator {
+		case "&&", "||", "??":
+			kind = "LogicalExpression"
+		}
+		return encodeNode(loc, kind,
+			property{"left", encode(node.Left)},
+			property{"operator", node.Operator},
+			property{"right", encode(node.Right)})
+	case *parser.AssignmentExpression:
+		return encodeNode(loc, "AssignmentExpression",
+			property{"operator", node.Operator},
+			property{"left", encode(node.Left)},
+			property{"right", encode(node.Value)})
+	case *parser.PrefixExpression:
+		return encodeNode(loc, "UnaryExpression",
+			property{"operator", node.Operator},
+			property{"prefix", true},
+			property{"argument", encode(node.Right)})
+	case *parser.UpdateExpression:
+		return encodeNode(loc, "UpdateExpression",
+			property{"operator", node.Operator},
+			property{"prefix", node.Prefix},
+			property{"argument", encode(node.Argument)})
+	case *parser.ConditionalExpression:
+		return encodeNode(loc, "ConditionalExpression",
+			property{"test", encode(node.Test)},
+			property{"consequent", encode(node.Consequent)},
+			property{"alternate", encode(node.Alternate)})
+	case *parser.SequenceExpression:
+		return encodeNode(loc, "SequenceExpression", property{"expressions", encodeList(node.Expressions)})
+	case *parser.MemberExpression:
+		return encodeNode(loc, "MemberExpression",
+			property{"object", encode(node.Object)},
+			property{"property", encode(node.Property)},
+			property{"computed", node.Computed},
+			property{"optional", false})
+	case *parser.CallExpression:
+		return encodeNode(loc, "CallExpression",
+			property{"callee", encode(node.Function)},
+			property{"arguments", encodeList(node.Arguments)},
+			property{"optional", false})
+	case *parser.NewExpression:
+		return encodeNode(loc, "NewExpression",
+			property{"callee", encode(node.Callee)},
+			property{"arguments", encodeList(node.Arguments)})
+	case *parser.ThisExpression:
+		return encodeNode(loc, "ThisExpression")
+	case *parser.MetaProperty:
+		return encodeNode(loc, "MetaProperty",
+			property{"meta", encode(node.Meta)},
+			property{"property", encode(node.Property)})
+	case *parser.AwaitExpression:
+		return encodeNode(loc, "AwaitExpression", property{"argument", encode(node.Argument)})
+	case *parser.YieldExpression:
+		return encodeNode(loc, "YieldExpression",
+			property{"delegate", node.Delegate},
+			property{"argument", encode(node.Argument)})
+	}
+	panic("estree: unknown node " + reflect.TypeOf(node).String())
+}
+
+func encodeFunction(loc parser.Loc, kind string, name *parser.Identifier, parameters []*parser.Identifier,
+	body *parser.BlockStatement, generator bool, async bool) object {
+	return encodeNode(loc, kind,
+		property{"id", encode(name)},
+		property{"expression", false},
+		property{"generator", generator},
+		property{"async", async},
+		property{"params", encodeList(parameters)},
+		property{"body", encode(body)})
+}
+
+// encodeList converts a list of nodes, which is never null.
+func encodeList[N parser.Node](nodes []N) []interface{} {
+	list := []interface{}{}
+	for _, node := range nodes {
+		list = append(list, encode(node))
+	}
+	return list
+}
+
+// templateElementLoc narrows the range of a template chunk, which starts with a backtick or "}" and ends
+// with a backtick or "${", to its text.
+func templateElementLoc(element *parser.TemplateElement) parser.Loc {
+	loc := element.Location()
+	closing := 2
+	if element.Tail {
+		closing = 1
+	}
+	loc.Start.Offset++
+	loc.Start.Column++
+	loc.End.Offset -= closing
+	loc.End.Column -= closing
+	return loc
+}
+
+// stringRaw returns the source text of a string literal, quoting its value when it was not parsed.
+func stringRaw(literal *parser.StringLiteral) string {
+	if literal.Token.Raw != "" {
+		return literal.Token.Raw
+	}
+	return strconv.Quote(literal.Value)
+}
@@ -0,0 +1,134 @@
+package interpreter
+
+import "fmt"
+
+// asyncGeneratorRequest is a call to next, throw or return waiting for its turn.
+type asyncGeneratorRequest struct {
+	resumption resumption
+	promise    *promise
+}
+
+// asyncGenerator drives the body of an async generator function. Each call to next, throw or return
+// returns a promise right away and is queued, the body handles the requests one at a time.
+type asyncGenerator struct {
+	coroutine *generator
+	queue     []asyncGeneratorRequest
+	running   bool // Whether the body is handling the request at the head of the queue
+}
+
+// newAsyncGenerator creates the async generator object returned by calling an async generator function.
+func (i *Interpreter) newAsyncGenerator(function *Function, f frame) *Object {
+	prototypeValue, _ := function.Get("prototype")
+	prototype, ok := asObject(prototypeValue)
+	if !ok {
+		prototype = i.asyncGeneratorPrototype
+	}
+	object := NewObject(prototype)
+	gen := &asyncGenerator{coroutine: i.newCoroutine(function, f)}
+	// The reactions of an awaited promise resume the body too, so they hold the async generator
+	gen.coroutine.releaseWith(gen)
+	object.internal = gen
+	return object
+}
+
+// addAsyncGeneratorPrototype creates the prototype shared by all async generator objects.
+func (i *Interpreter) addAsyncGeneratorPrototype() {
+	i.asyncGeneratorPrototype = NewObject(nil)
+	methods := map[string]resumeMode{"next": resumeNext, "throw": resumeThrow, "return": resumeReturn}
+	for name, mode := range methods {
+		name, mode := name, mode
+		i.asyncGeneratorPrototype.SetHidden(name, NewBuiltinFunction(func(this Value, args ...Value) Value {
+			object, p := i.newPromise()
+			gen := asyncGeneratorOf(this)
+			if gen == nil {
+				i.rejectPromise(p, newError(i.errorPrototypes["TypeError"], "TypeError",
+					fmt.Sprintf("AsyncGenerator.prototype.%s called on incompatible receiver %v", name, this)))
+				return object
+			}
+			gen.enqueue(asyncGeneratorRequest{resumption: resumption{mode: mode, value: argument(args, 0)},
+				promise: p})
+			return object
+		}))
+	}
+	// Async generators are async iterables, they are their own iterator
+	i.asyncGeneratorPrototype.SetHidden(propertyKey(SymbolAsyncIterator), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return this
+	}))
+}
+
+// asyncGeneratorOf returns the async generator backing an object, or nil for any other value.
+func asyncGeneratorOf(value Value) *asyncGenerator {
+	if object, ok := value.(*Object); ok {
+		if gen, ok := object.internal.(*asyncGenerator); ok {
+			return gen
+		}
+	}
+	return nil
+}
+
+func (ag *asyncGenerator) enqueue(request asyncGeneratorRequest) {
+	ag.queue = append(ag.queue, request)
+	if !ag.running {
+		ag.resumeNext()
+	}
+}
+
+// resumeNext handles the queued requests: requests to a finished generator are settled right away,
+// otherwise the body is resumed with the request at the head of the queue.
+func (ag *asyncGenerator) resumeNext() {
+	i := ag.coroutine.interpreter
+	for len(ag.queue) > 0 {
+		request := ag.queue[0]
+		r := request.resumption
+
+		// A generator that never started has no body to resume into
+		if ag.coroutine.state == suspendedStart && r.mode != resumeNext {
+			ag.coroutine.state = completed
+		}
+		if ag.coroutine.state != completed {
+			ag.running = true
+			ag.drive(r)
+			return
+		}
+
+		ag.queue = ag.queue[1:]
+		switch r.mode {
+		case resumeThrow:
+			i.rejectPromise(request.promise, r.value)
+		case resumeReturn:
+			i.resolvePromise(request.promise, iteratorResult(r.value, true))
+		default:
+			i.resolvePromise(request.promise, iteratorResult(Undefined, true))
+		}
+	}
+}
+
+// drive resumes the body until it yields or finishes, which settles the promise of the current request.
+// Awaits in the body suspend it until the awaited value settles.
+func (ag *asyncGenerator) drive(r resumption) {
+	i := ag.coroutine.interpreter
+	var result generatorResult
+	exception := i.protect(func() {
+		result = ag.coroutine.resume(r)
+	})
+
+	if exception == nil && result.await {
+		i.then(i.promiseResolve(result.value), func(value Value) {
+			ag.drive(resumption{mode: resumeNext, value: value})
+		}, func(reason Value) {
+			ag.drive(resumption{mode: resumeThrow, value: reason})
+		})
+		return
+	}
+
+	request := ag.queue[0]
+	ag.queue = ag.queue[1:]
+	ag.running = false
+	if exception != nil {
+		i.rejectPromise(request.promise, exception.Value)
+	} else {
+		i.resolvePromise(request.promise, iteratorResult(result.value, result.done))
+	}
+	ag.resumeNext()
+}
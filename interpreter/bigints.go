@@ -0,0 +1,108 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+	"strings"
+)
+
+// addBigInt creates the BigInt function, which converts integers, booleans and strings to BigInts. It is not
+// a constructor.
+func (i *Interpreter) addBigInt() {
+	constructor := &BuiltinConstructor{Object: NewObject(nil), Name: "BigInt"}
+	constructor.Call = func(this Value, args ...Value) Value {
+		return toBigInt(argument(args, 0))
+	}
+	i.Env["BigInt"] = constructor
+}
+
+func toBigInt(value Value) *BigInt {
+	switch value := value.(type) {
+	case *BigInt:
+		return value
+	case Number:
+		// NaN and the infinities are not integers either
+		if math.IsInf(float64(value), 0) || math.Trunc(float64(value)) != float64(value) {
+			throwError("RangeError", "The number %v cannot be converted to a BigInt because it is not an integer",
+				toString(value))
+		}
+		integer, _ := big.NewFloat(float64(value)).Int(nil)
+		return NewBigInt(integer)
+	case Boolean:
+		if value {
+			return NewBigInt(big.NewInt(1))
+		}
+		return NewBigInt(big.NewInt(0))
+	case String:
+		integer, ok := stringToBigInt(string(value))
+		if !ok {
+			throwError("SyntaxError", "Cannot convert %s to a BigInt", string(value))
+		}
+		return NewBigInt(integer)
+	default:
+		throwError("TypeError", "Cannot convert %v to a BigInt", value)
+		return nil
+	}
+}
+
+// stringToBigInt parses an integer, ignoring surrounding whitespace, like BigInt does. An empty string is 0.
+func stringToBigInt(text string) (*big.Int, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return big.NewInt(0), true
+	}
+	integer, ok := new(big.Int).SetString(text, 0)
+	return integer, ok && !strings.Contains(text, "_")
+}
+
+// bigIntOperation applies an arithmetic, bitwise or relational operator to two BigInts, it reports false for
+// the operators BigInts do not support.
+func bigIntOperation(operator string, left *big.Int, right *big.Int) (Value, bool) {
+	switch operator {
+	case "+":
+		return NewBigInt(new(big.Int).Add(left, right)), true
+	case "-":
+		return NewBigInt(new(big.Int).Sub(left, right)), true
+	case "*":
+		return NewBigInt(new(big.Int).Mul(left, right)), true
+	case "/", "%":
+		if right.Sign() == 0 {
+			throwError("RangeError", "Division by zero")
+		}
+		// Quo and Rem truncate toward zero like JavaScript, unlike Div and Mod
+		if operator == "/" {
+			return NewBigInt(new(big.Int).Quo(left, right)), true
+		}
+		return NewBigInt(new(big.Int).Rem(left, right)), true
+	case "**":
+		if right.Sign() < 0 {
+			throwError("RangeError", "Exponent must be non-negative")
+		}
+		return NewBigInt(new(big.Int).Exp(left, right, nil)), true
+	case "&":
+		return NewBigInt(new(big.Int).And(left, right)), true
+	case "|":
+		return NewBigInt(new(big.Int).Or(left, right)), true
+	case "^":
+		return NewBigInt(new(big.Int).Xor(left, right)), true
+	case "<<", ">>":
+		// A negative shift shifts the other way
+		if (operator == "<<") == (right.Sign() >= 0) {
+			return NewBigInt(new(big.Int).Lsh(left, uint(new(big.Int).Abs(right).Uint64()))), true
+		}
+		return NewBigInt(new(big.Int).Rsh(left, uint(new(big.Int).Abs(right).Uint64()))), true
+	case ">>>":
+		throwError("TypeError", "BigInts have no unsigned right shift, use >> instead")
+		return nil, false
+	case "<":
+		return Boolean(left.Cmp(right) < 0), true
+	case ">":
+		return Boolean(left.Cmp(right) > 0), true
+	case "<=":
+		return Boolean(left.Cmp(right) <= 0), true
+	case ">=":
+		return Boolean(left.Cmp(right) >= 0), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+)
+
+// BuiltinFunction is a function implemented in Go that can be called from JavaScript. Like any function
+// it is an object of its own, two builtin functions are the same only if they are the same pointer.
+type BuiltinFunction struct {
+	Call func(this Value, args ...Value) Value // The this value is the object it was called on, or undefined
+}
+
+func NewBuiltinFunction(call func(this Value, args ...Value) Value) *BuiltinFunction {
+	return &BuiltinFunction{Call: call}
+}
+
+func (bf *BuiltinFunction) String() string {
+	return "[Function (native)]"
+}
+
+// BuiltinConstructor is a constructor implemented in Go, its own properties hold the static methods.
+// Call is used when it is called without new, and Construct is nil if new is not allowed.
+type BuiltinConstructor struct {
+	*Object
+	Name      string
+	Call      func(this Value, args ...Value) Value
+	Construct func(args ...Value) Value
+}
+
+func (bc *BuiltinConstructor) String() string {
+	return fmt.Sprintf("[Function: %s]", bc.Name)
+}
+
+// globalObject marks the global object, whose properties are the global variables.
+type globalObject struct{}
+
+// addGlobalObject creates the global object, the this value of scripts and of sloppy mode functions
+// called without one. It shares its properties with the global scope.
+func (i *Interpreter) addGlobalObject() {
+	i.global = &Object{Properties: i.Env, internal: globalObject{}}
+	i.this = i.global
+	i.Env["globalThis"] = i.global
+	// NaN and Infinity are read-only properties of the global object
+	i.Env["NaN"] = Number(math.NaN())
+	i.Env["Infinity"] = Number(math.Inf(1))
+	i.readOnly = map[string]bool{"NaN": true, "Infinity": true}
+}
+
+func (i *Interpreter) addBuiltins() {
+	i.addErrors()
+	i.addSymbol()
+	i.addGeneratorPrototype()
+	i.addAsyncGeneratorPrototype()
+	i.addPromise()
+	i.addRegExp()
+	i.addString()
+	i.addBigInt()
+
+	console := NewObject(nil)
+	console.Set("log", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		fmt.Println(goValues(args)...)
+		return Undefined
+	}))
+	i.Env["console"] = console
+
+	mathObject := NewObject(nil)
+	for name, function := range map[string]*BuiltinFunction{
+		"sqrt": NewBuiltinFunction(func(this Value, args ...Value) Value {
+			if len(args) != 1 {
+				fmt.Printf("Error: Math.sqrt expects 1 argument, got %d\n", len(args))
+				return Undefined
+			}
+			return Number(math.Sqrt(toNumber(args[0])))
+		}),
+		"pow": NewBuiltinFunction(func(this Value, args ...Value) Value {
+			if len(args) != 2 {
+				fmt.Printf("Error: Math.pow expects 2 arguments, got %d\n", len(args))
+				return Undefined
+			}
+			return Number(exponentiate(toNumber(args[0]), toNumber(args[1])))
+		}),
+	} {
+		mathObject.Set(name, function)
+	}
+	i.Env["Math"] = mathObject
+}
@@ -0,0 +1,105 @@
+package interpreter
+
+// Environment is a scope holding variable bindings, linked to the scope it is nested in.
+type Environment struct {
+	store     map[string]Value
+	constants map[string]bool
+	outer     *Environment
+	function  bool    // Whether var declarations are scoped here, true for function bodies and the global scope
+	object    *Object // For the scope of a with statement, the object whose properties are the bindings
+	// The let and const bindings in their temporal dead zone: declared in the scope but not initialized yet
+	uninitialized map[string]bool
+}
+
+// NewEnvironment creates the global scope on top of the given maps.
+func NewEnvironment(store map[string]Value, constants map[string]bool) *Environment {
+	return &Environment{store: store, constants: constants, function: true}
+}
+
+// NewEnclosedEnvironment creates a scope nested in outer.
+func NewEnclosedEnvironment(outer *Environment, function bool) *Environment {
+	return &Environment{
+		store:     make(map[string]Value),
+		constants: make(map[string]bool),
+		outer:     outer,
+		function:  function,
+	}
+}
+
+// NewObjectEnvironment creates the scope of a with statement, nested in outer.
+func NewObjectEnvironment(outer *Environment, object *Object) *Environment {
+	env := NewEnclosedEnvironment(outer, false)
+	env.object = object
+	return env
+}
+
+// Get looks up a variable in this scope and then in the enclosing ones.
+func (e *Environment) Get(name string) (Value, bool) {
+	if env := e.resolve(name); env != nil {
+		if env.object != nil {
+			return env.object.Get(name)
+		}
+		env.checkInitialized(name)
+		return env.store[name], true
+	}
+	return Undefined, false
+}
+
+// resolve returns the scope a variable is declared in, or nil if it is undeclared.
+func (e *Environment) resolve(name string) *Environment {
+	for env := e; env != nil; env = env.outer {
+		if env.object != nil {
+			if _, ok := env.object.Get(name); ok {
+				return env
+			}
+			continue
+		}
+		if _, ok := env.store[name]; ok || env.uninitialized[name] {
+			return env
+		}
+	}
+	return nil
+}
+
+// set updates a binding in this scope, the scope of a with statement updates the property of its object.
+func (e *Environment) set(name string, value Value) {
+	if e.object != nil {
+		e.object.Set(name, value)
+		return
+	}
+	e.checkInitialized(name)
+	e.store[name] = value
+}
+
+// Declare creates or overwrites a binding in this scope.
+func (e *Environment) Declare(name string, value Value, constant bool) {
+	delete(e.uninitialized, name)
+	e.store[name] = value
+	if constant {
+		e.constants[name] = true
+	}
+}
+
+// DeclareUninitialized creates a binding for a let or const declaration before it runs, using it throws a
+// ReferenceError until Declare initializes it.
+func (e *Environment) DeclareUninitialized(name string) {
+	if e.uninitialized == nil {
+		e.uninitialized = map[string]bool{}
+	}
+	e.uninitialized[name] = true
+}
+
+func (e *Environment) checkInitialized(name string) {
+	if e.uninitialized[name] {
+		throwError("ReferenceError", "Cannot access '%s' before initialization", name)
+	}
+}
+
+// functionScope returns the closest scope var declarations belong to.
+func (e *Environment) functionScope() *Environment {
+	env := e
+	for !env.function && env.outer != nil {
+		env = env.outer
+	}
+	return env
+}
@@ -0,0 +1,92 @@
+package interpreter
+
+// errorObject marks error objects, which format as their name and message.
+// The name is the one of the constructor that created it, used until the object has a prototype.
+type errorObject struct {
+	name string
+}
+
+// errorNames are the built-in error constructors, Error first since the others inherit from it.
+var errorNames = []string{"Error", "TypeError", "RangeError", "SyntaxError", "ReferenceError"}
+
+// addErrors creates the Error constructor and the constructors of the native errors, whose prototypes
+// inherit from Error.prototype.
+func (i *Interpreter) addErrors() {
+	i.errorPrototypes = make(map[string]*Object)
+	for _, name := range errorNames {
+		name := name
+		prototype := NewObject(i.errorPrototypes["Error"])
+		prototype.SetHidden("name", String(name))
+		prototype.SetHidden("message", String(""))
+		if name == "Error" {
+			prototype.SetHidden("toString", NewBuiltinFunction(func(this Value, args ...Value) Value {
+				object, ok := this.(*Object)
+				if !ok {
+					throwError("TypeError", "Error.prototype.toString called on %v", formatValue(this, 0))
+				}
+				return String(errorString(object))
+			}))
+		}
+		i.errorPrototypes[name] = prototype
+
+		constructor := &BuiltinConstructor{Object: NewObject(nil), Name: name}
+		constructor.Construct = func(args ...Value) Value {
+			object := newError(prototype, name, "")
+			if message := argument(args, 0); message != Undefined {
+				object.SetHidden("message", String(toString(message)))
+			}
+			return object
+		}
+		// Called without new, an error constructor still creates an error
+		constructor.Call = func(this Value, args ...Value) Value {
+			return constructor.Construct(args...)
+		}
+		constructor.Set("prototype", prototype)
+		prototype.SetHidden("constructor", constructor)
+		i.Env[name] = constructor
+	}
+}
+
+// newError creates an error object with a message. The errors throwError throws have no prototype
+// yet, completeError gives them the prototype of their constructor once they are caught.
+func newError(prototype *Object, name string, message string) *Object {
+	object := NewObject(prototype)
+	object.internal = errorObject{name: name}
+	object.SetHidden("message", String(message))
+	return object
+}
+
+// completeError links an error thrown by the interpreter to the prototype of its constructor,
+// so that code catching it can use instanceof and the methods of Error.prototype.
+func (i *Interpreter) completeError(value Value) {
+	object, ok := value.(*Object)
+	if !ok || object.Prototype != nil {
+		return
+	}
+	if e, ok := object.internal.(errorObject); ok {
+		object.Prototype = i.errorPrototypes[e.name]
+	}
+}
+
+// errorString formats an error as its name and message, separated by a colon unless one is empty.
+func errorString(object *Object) string {
+	name := "Error"
+	if e, ok := object.internal.(errorObject); ok {
+		name = e.name
+	}
+	if value, found := object.Get("name"); found && value != Undefined {
+		name = toString(value)
+	}
+	message := ""
+	if value, found := object.Get("message"); found && value != Undefined {
+		message = toString(value)
+	}
+	switch {
+	case name == "":
+		return message
+	case message == "":
+		return name
+	default:
+		return name + ": " + message
+	}
+}
@@ -0,0 +1,334 @@
+package interpreter
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// generatorState tracks where a generator is in its lifecycle.
+type generatorState int
+
+const (
+	suspendedStart generatorState = iota
+	suspendedYield
+	executing
+	completed
+)
+
+// resumeMode tells how a suspended generator is resumed: by next, throw or return.
+type resumeMode int
+
+const (
+	resumeNext resumeMode = iota
+	resumeThrow
+	resumeReturn
+	resumeAbandon // Releases a coroutine suspended forever, see releaseWith
+)
+
+type resumption struct {
+	mode  resumeMode
+	value Value
+}
+
+// generatorResult is sent back to the caller when the generator yields, awaits or finishes.
+type generatorResult struct {
+	value Value
+	done  bool
+	await bool        // Whether the body awaits the value rather than yielding it
+	panic interface{} // A panic raised by the body, e.g. an uncaught *Exception, re-raised in the caller
+}
+
+// generatorReturn unwinds the body of a generator when it is resumed with return.
+type generatorReturn struct {
+	value Value
+}
+
+// generatorAbandoned unwinds the body of an abandoned coroutine. Unlike a return, it runs no finally blocks.
+type generatorAbandoned struct{}
+
+// generator runs the body of a generator or async function as a coroutine: the body runs on its own
+// goroutine, and control is handed back and forth over channels so only one side ever runs at a time.
+type generator struct {
+	interpreter *Interpreter
+	function    *Function
+	state       generatorState
+	frame       frame // The environment the body runs in, saved while it is suspended
+	resumptions chan resumption
+	results     chan generatorResult
+}
+
+// frame is the part of the interpreter state that belongs to the running function.
+type frame struct {
+	scope     *Environment
+	this      Value
+	newTarget Value
+	generator *generator
+	strict    bool
+}
+
+func (i *Interpreter) saveFrame() frame {
+	return frame{scope: i.scope, this: i.this, newTarget: i.newTarget, generator: i.generator, strict: i.strict}
+}
+
+func (i *Interpreter) restoreFrame(f frame) {
+	i.scope, i.this, i.newTarget, i.generator, i.strict = f.scope, f.this, f.newTarget, f.generator, f.strict
+}
+
+// newCoroutine prepares the body of a function to run as a coroutine in the given frame.
+func (i *Interpreter) newCoroutine(function *Function, f frame) *generator {
+	i.releaseAbandoned()
+	return &generator{
+		interpreter: i,
+		function:    function,
+		state:       suspendedStart,
+		frame:       f,
+		resumptions: make(chan resumption),
+		results:     make(chan generatorResult),
+	}
+}
+
+// newGenerator creates the generator object returned by calling a generator function.
+// The body does not run until the first call to next.
+func (i *Interpreter) newGenerator(function *Function, f frame) *Object {
+	prototypeValue, _ := function.Get("prototype")
+	prototype, ok := asObject(prototypeValue)
+	if !ok {
+		prototype = i.generatorPrototype
+	}
+	object := NewObject(prototype)
+	gen := i.newCoroutine(function, f)
+	gen.releaseWith(object)
+	object.internal = gen
+	return object
+}
+
+// addGeneratorPrototype creates the prototype shared by all generator objects.
+func (i *Interpreter) addGeneratorPrototype() {
+	i.generatorPrototype = NewObject(nil)
+	methods := map[string]resumeMode{"next": resumeNext, "throw": resumeThrow, "return": resumeReturn}
+	for name, mode := range methods {
+		name, mode := name, mode
+		i.generatorPrototype.SetHidden(name, NewBuiltinFunction(func(this Value, args ...Value) Value {
+			gen := generatorOf(this)
+			if gen == nil {
+				fmt.Printf("Error: Generator.prototype.%s called on incompatible receiver %v\n", name, this)
+				return Undefined
+			}
+			result, done := gen.step(resumption{mode: mode, value: argument(args, 0)})
+			// The generator object must not be released while its body runs
+			runtime.KeepAlive(this)
+			return iteratorResult(result, done)
+		}))
+	}
+	// Generators are iterable, they are their own iterator
+	i.generatorPrototype.SetHidden(propertyKey(SymbolIterator), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return this
+	}))
+}
+
+// generatorOf returns the generator backing a generator object, or nil for any other value.
+func generatorOf(value Value) *generator {
+	if object, ok := value.(*Object); ok {
+		if gen, ok := object.internal.(*generator); ok {
+			return gen
+		}
+	}
+	return nil
+}
+
+// iteratorResult creates an object of the form { value, done }.
+func iteratorResult(value Value, done bool) *Object {
+	result := NewObject(nil)
+	result.Set("value", value)
+	result.Set("done", Boolean(done))
+	return result
+}
+
+// step resumes the generator and waits until it yields or finishes.
+func (g *generator) step(r resumption) (Value, bool) {
+	switch g.state {
+	case executing:
+		throwError("TypeError", "Generator is already running")
+	case completed:
+		return g.finished(r)
+	case suspendedStart:
+		// A generator that never started has no body to resume into
+		if r.mode != resumeNext {
+			g.state = completed
+			return g.finished(r)
+		}
+	}
+	result := g.resume(r)
+	return result.value, result.done
+}
+
+// resume runs the body from where it is suspended until it yields, awaits or finishes.
+func (g *generator) resume(r resumption) generatorResult {
+	if g.state == suspendedStart {
+		go g.run()
+	}
+
+	i := g.interpreter
+	caller := i.saveFrame()
+	g.state = executing
+	g.resumptions <- r
+	result := <-g.results
+	i.restoreFrame(caller)
+
+	if result.done {
+		g.state = completed
+	} else {
+		g.state = suspendedYield
+	}
+	if result.panic != nil {
+		panic(result.panic)
+	}
+	return result
+}
+
+// finished resumes a completed generator: return completes with its value and throw rethrows.
+func (g *generator) finished(r resumption) (Value, bool) {
+	switch r.mode {
+	case resumeThrow:
+		panic(&Exception{Value: r.value})
+	case resumeReturn:
+		return r.value, true
+	default:
+		return Undefined, true
+	}
+}
+
+// run evaluates the body of the generator on its own goroutine.
+func (g *generator) run() {
+	i := g.interpreter
+	<-g.resumptions // The first next() starts the body, its argument is ignored
+
+	var result generatorResult
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if ret, ok := recovered.(*generatorReturn); ok {
+				result = generatorResult{value: ret.value, done: true}
+			} else if _, ok := recovered.(generatorAbandoned); ok {
+				result = generatorResult{value: Undefined, done: true}
+			} else {
+				result = generatorResult{done: true, panic: recovered}
+			}
+		}
+		g.results <- result
+	}()
+
+	g.frame.generator = g
+	i.restoreFrame(g.frame)
+	result = generatorResult{value: i.evalFunctionBody(g.function), done: true}
+}
+
+// suspend hands a yielded or awaited value to the caller and returns how the body was resumed.
+func (g *generator) suspend(value Value, await bool) resumption {
+	i := g.interpreter
+	g.frame = i.saveFrame()
+	g.results <- generatorResult{value: value, await: await}
+	r := <-g.resumptions
+	if r.mode == resumeAbandon {
+		panic(generatorAbandoned{})
+	}
+	i.restoreFrame(g.frame)
+	return r
+}
+
+// releaseWith releases the goroutine of the coroutine once a handle is garbage collected. The handle is
+// what resumes the body, e.g. a generator object, so without it a suspended body would block its goroutine,
+// and keep its scopes alive, forever. A body whose own scopes reach the handle keeps it alive and is never
+// released, e.g. a generator stored in a variable of the function that declares it.
+func (g *generator) releaseWith(handle interface{}) {
+	i := g.interpreter
+	runtime.SetFinalizer(handle, func(interface{}) {
+		i.abandonedLock.Lock()
+		defer i.abandonedLock.Unlock()
+		i.abandoned = append(i.abandoned, g)
+	})
+}
+
+// releaseAbandoned unwinds the bodies of the coroutines whose handles were garbage collected. Finalizers
+// only queue them, the bodies are unwound here so that only one goroutine ever runs the interpreter.
+func (i *Interpreter) releaseAbandoned() {
+	i.abandonedLock.Lock()
+	abandoned := i.abandoned
+	i.abandoned = nil
+	i.abandonedLock.Unlock()
+
+	for _, g := range abandoned {
+		// A body that never started has no goroutine yet
+		if g.state == suspendedYield {
+			g.resume(resumption{mode: resumeAbandon})
+		}
+	}
+}
+
+// isAsync reports whether the body belongs to an async generator.
+func (g *generator) isAsync() bool {
+	return g.function.Async && g.function.Generator
+}
+
+// evalYield implements yield: the resumption becomes the value of the expression, a thrown
+// exception or a return unwinding the body. Async generators await values before yielding them.
+func (g *generator) evalYield(value Value) Value {
+	if g.isAsync() {
+		value = g.evalAwait(value)
+	}
+	r := g.suspend(value, false)
+	switch r.mode {
+	case resumeThrow:
+		panic(&Exception{Value: r.value})
+	case resumeReturn:
+		if g.isAsync() {
+			r.value = g.evalAwait(r.value)
+		}
+		panic(&generatorReturn{value: r.value})
+	default:
+		return r.value
+	}
+}
+
+// evalAwait implements await: the body is suspended until the value settles, and resumed with the
+// fulfillment value or with the rejection reason thrown.
+func (g *generator) evalAwait(value Value) Value {
+	r := g.suspend(value, true)
+	if r.mode == resumeThrow {
+		panic(&Exception{Value: r.value})
+	}
+	return r.value
+}
+
+// evalDelegate implements yield*, forwarding each resumption to the inner iterator until it is done.
+// Async generators delegate to async iterators.
+func (g *generator) evalDelegate(iterable Value) Value {
+	i := g.interpreter
+	record := i.getIterator(iterable, g.isAsync())
+
+	r := resumption{mode: resumeNext, value: Undefined}
+	for {
+		method := record.next
+		switch r.mode {
+		case resumeThrow:
+			if method = i.getMethod(record.iterator, String("throw")); method == Undefined {
+				i.iteratorClose(record)
+				throwError("TypeError", "The iterator does not provide a 'throw' method")
+			}
+		case resumeReturn:
+			if method = i.getMethod(record.iterator, String("return")); method == Undefined {
+				panic(&generatorReturn{value: r.value})
+			}
+		}
+
+		value, done := i.iteratorStep(record, method, r.value)
+		if done {
+			// Returning from the outer generator ends the delegation with the same value
+			if r.mode == resumeReturn {
+				panic(&generatorReturn{value: value})
+			}
+			return value
+		}
+		r = g.suspend(value, false)
+	}
+}
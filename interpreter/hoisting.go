@@ -0,0 +1,104 @@
+package interpreter
+
+import (
+	"gojo/parser"
+	"gojo/scope"
+)
+
+// analyzeScopes finds the declarations of each scope of a program with the scope package, the interpreter
+// binds them when it enters the scope.
+func (i *Interpreter) analyzeScopes(program *parser.Program) {
+	for node, s := range scope.Analyze(program).Scopes {
+		i.scopes[node] = s
+		for _, declaration := range s.BlockFunctions {
+			for _, name := range declaration.Identifiers {
+				i.blockFunctions[name] = true
+			}
+		}
+	}
+}
+
+// instantiateDeclarations declares the names of the scope of a node in the current environment before its
+// first statement runs, in the state their declaration gives them when the scope is entered: vars are
+// undefined unless the name is already bound (e.g., a global of an earlier script), functions are
+// initialized so they can be called before their declaration, and let, const and imports are uninitialized
+// until they run. The name of a function expression is bound to the running function.
+func (i *Interpreter) instantiateDeclarations(node parser.Node, running *Function) {
+	s := i.scopes[node]
+	if s == nil {
+		return
+	}
+	for _, declaration := range s.Declarations {
+		switch declaration.Initial() {
+		case scope.Undefined:
+			i.declareVar(declaration.Name)
+		case scope.Uninitialized:
+			i.scope.DeclareUninitialized(declaration.Name)
+		case scope.FunctionObject:
+			if function, ok := declaration.Node.(*parser.FunctionDeclaration); ok {
+				i.scope.Declare(declaration.Name, i.newFunctionDeclaration(function), false)
+			} else {
+				i.scope.Declare(declaration.Name, running, false)
+			}
+		}
+	}
+	// A function of a block is assigned to its var when its declaration runs, until then it is undefined
+	for _, declaration := range s.BlockFunctions {
+		i.declareVar(declaration.Name)
+	}
+	if program, ok := node.(*parser.Program); ok {
+		for _, stmt := range program.Statements {
+			if export, ok := stmt.(*parser.ExportDefaultDeclaration); ok {
+				i.instantiateDefaultExport(export)
+			}
+		}
+	}
+}
+
+// hasDeclarations reports whether the scope of a block declares let, const or functions, which need an
+// environment of their own.
+func (i *Interpreter) hasDeclarations(node parser.Node) bool {
+	s := i.scopes[node]
+	return s != nil && len(s.Declarations) > 0
+}
+
+func (i *Interpreter) declareVar(name string) {
+	if _, bound := i.scope.store[name]; !bound {
+		i.scope.Declare(name, Undefined, false)
+	}
+}
+
+func (i *Interpreter) newFunctionDeclaration(declaration *parser.FunctionDeclaration) *Function {
+	return i.newFunction(&Function{
+		Name:       declaration.Name.Value,
+		Parameters: declaration.Parameters,
+		Body:       declaration.Body,
+		Node:       declaration,
+		Generator:  declaration.Generator,
+		Async:      declaration.Async,
+		Strict:     declaration.Strict,
+	})
+}
+
+// instantiateDefaultExport binds the default export of a module to its exported function declaration. A named
+// one is the function its name is bound to, an anonymous one is named default.
+func (i *Interpreter) instantiateDefaultExport(stmt *parser.ExportDefaultDeclaration) {
+	declaration, ok := stmt.Declaration.(*parser.FunctionDeclaration)
+	if !ok {
+		return
+	}
+	if declaration.Name != nil {
+		i.scope.Declare(defaultExportBinding, i.scope.store[declaration.Name.Value], false)
+		return
+	}
+	function := i.newFunction(&Function{
+		Name:       "default",
+		Parameters: declaration.Parameters,
+		Body:       declaration.Body,
+		Node:       declaration,
+		Generator:  declaration.Generator,
+		Async:      declaration.Async,
+		Strict:     true,
+	})
+	i.scope.Declare(defaultExportBinding, function, false)
+}
@@ -4,43 +4,81 @@ import (
 	"fmt"
 	"gojo/config"
 	"gojo/parser"
-	"math"
-	"strconv"
+	"gojo/scope"
+	"math/big"
+	"sync"
 )
 
 type Interpreter struct {
-	Env       map[string]interface{}
-	Constants map[string]bool
+	Env                     map[string]Value // The global variables
+	Constants               map[string]bool
+	scope                   *Environment // The scope of the running code
+	this                    Value        // The this value of the running function, undefined at the top level
+	newTarget               Value        // The constructor new was applied to, undefined in plain calls
+	generator               *generator   // The generator or async function whose body is running, if any
+	strict                  bool         // Whether the running code is strict mode code
+	global                  *Object      // The global object, its properties are the global variables
+	generatorPrototype      *Object
+	asyncGeneratorPrototype *Object
+	promisePrototype        *Object
+	regExpPrototype         *Object
+	stringPrototype         *Object
+	errorPrototypes         map[string]*Object                  // The prototypes of the error constructors by name
+	templates               map[*parser.TemplateLiteral]*Object // The strings object of each tagged template
+	scopes                  map[parser.Node]*scope.Scope        // The scopes of the programs run so far, by node
+	blockFunctions          map[*parser.Identifier]bool         // The names of block functions that are also vars
+	readOnly                map[string]bool                     // Read-only properties of the global object, e.g. NaN
+	jobs                    []func()                            // Pending promise jobs, run once the running code finishes
+	rejections              []*promise
+	meta                    *Object      // The import.meta object of the running module
+	abandoned               []*generator // Suspended coroutines nothing can resume anymore, see releaseWith
+	abandonedLock           sync.Mutex   // Guards abandoned, which finalizers append to from their own goroutine
+}
+
+// Exception is a thrown JavaScript value, it unwinds evaluation as a panic until it is caught.
+type Exception struct {
+	Value Value
+}
+
+func (e *Exception) Error() string {
+	return fmt.Sprintf("Uncaught %v", e.Value)
+}
+
+// completionType tells how a statement finished, so that return can unwind enclosing statements.
+type completionType int
+
+const (
+	normalCompletion completionType = iota
+	returnCompletion
+	breakCompletion
+	continueCompletion
+)
+
+// completion is the result of evaluating a statement.
+type completion struct {
+	Type   completionType
+	Value  Value
+	Target string // The label a break or continue targets, empty for the innermost loop or switch
 }
 
 func New() *Interpreter {
 	// Initialize interpreter with empty environment and constants
 	interpreter := &Interpreter{
-		Env:       make(map[string]interface{}),
-		Constants: make(map[string]bool),
-	}
-	// Add built-in functions
-	interpreter.Env["console"] = map[string]interface{}{
-		"log": func(args ...interface{}) {
-			fmt.Println(args...)
-		},
-	}
-	interpreter.Env["Math"] = map[string]interface{}{
-		"sqrt": func(x float64) float64 {
-			return math.Sqrt(x)
-		},
-		"pow": func(x, y float64) float64 {
-			return math.Pow(x, y)
-		},
+		Env:            make(map[string]Value),
+		Constants:      make(map[string]bool),
+		newTarget:      Undefined,
+		scopes:         make(map[parser.Node]*scope.Scope),
+		blockFunctions: make(map[*parser.Identifier]bool),
 	}
+	interpreter.scope = NewEnvironment(interpreter.Env, interpreter.Constants)
+	interpreter.addGlobalObject()
+	interpreter.addBuiltins()
 	return interpreter
 }
 
 func (i *Interpreter) Interpret(program *parser.Program) {
 	fmt.Println("╔═══ 🌸 Program Output:")
-	for _, stmt := range program.Statements {
-		i.evalStatement(stmt)
-	}
+	i.evalProgram(program)
 	if config.LoadConfig().Verbose {
 		fmt.Println("╔═══ 🌸 Program Environment:")
 		maxKeyLength := 0
@@ -58,30 +96,85 @@ func (i *Interpreter) Interpret(program *parser.Program) {
 
 // InterpretREPL is used to interpret a single line of input in the REPL.
 func (i *Interpreter) InterpretREPL(program *parser.Program) {
-	for _, stmt := range program.Statements {
-		i.evalStatement(stmt)
+	i.evalProgram(program)
+}
+
+// evalProgram runs the statements of a program and then the promise jobs they queued.
+func (i *Interpreter) evalProgram(program *parser.Program) {
+	i.analyzeScopes(program)
+	if program.SourceType == parser.Module {
+		i.evalModule(program)
+	} else {
+		i.strict = program.Strict
+		i.runUncaught(func() {
+			i.instantiateDeclarations(program, nil)
+			i.evalStatements(program.Statements)
+		})
 	}
+	i.runJobs()
+}
+
+// runUncaught runs code outside any try statement: an uncaught exception stops it and is reported.
+func (i *Interpreter) runUncaught(run func()) {
+	global := i.saveFrame()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			exception, ok := recovered.(*Exception)
+			if !ok {
+				panic(recovered)
+			}
+			i.restoreFrame(global)
+			fmt.Println(exception.Error())
+		}
+	}()
+	run()
 }
 
-func (i *Interpreter) evalStatement(stmt parser.Statement) {
+func (i *Interpreter) evalStatement(stmt parser.Statement) completion {
 	switch stmt := stmt.(type) {
 	case *parser.VariableDeclaration:
-		value := i.evalExpression(stmt.Value)
-		i.Env[stmt.Name.Value] = value
-		// Set constant, probably not very performant
-		if stmt.IsConstant {
-			i.Constants[stmt.Name.Value] = true
-		}
+		i.evalVariableDeclaration(stmt)
 	case *parser.FunctionDeclaration:
-		i.Env[stmt.Name.Value] = stmt
+		// Functions are created when their scope is entered. In sloppy mode, a function declared in a block
+		// is also assigned to the var of the same name once its declaration runs.
+		if i.blockFunctions[stmt.Name] {
+			i.scope.functionScope().set(stmt.Name.Value, i.scope.store[stmt.Name.Value])
+		}
+	case *parser.ImportDeclaration, *parser.ExportNamedDeclaration, *parser.ExportDefaultDeclaration,
+		*parser.ExportAllDeclaration:
+		return i.evalModuleDeclaration(stmt)
+	case *parser.ThrowStatement:
+		panic(&Exception{Value: i.evalExpression(stmt.Argument)})
+	case *parser.TryStatement:
+		return i.evalTryStatement(stmt)
+	case *parser.WithStatement:
+		return i.evalWithStatement(stmt)
+	case *parser.BlockStatement:
+		return i.evalBlockStatement(stmt)
+	case *parser.ReturnStatement:
+		var value Value = Undefined
+		if stmt.Value != nil {
+			value = i.evalExpression(stmt.Value)
+		}
+		return completion{Type: returnCompletion, Value: value}
 	case *parser.IfStatement:
-		i.evalIfStatement(stmt)
+		return i.evalIfStatement(stmt)
 	case *parser.SwitchStatement:
-		i.evalSwitchStatement(stmt)
+		return i.evalSwitchStatement(stmt)
 	case *parser.WhileStatement:
-		for i.evalExpression(stmt.Condition).(bool) {
-			i.evalBlockStatement(stmt.Body)
-		}
+		return i.evalWhileStatement(stmt, nil)
+	case *parser.ForStatement:
+		return i.evalForStatement(stmt, nil)
+	case *parser.ForInStatement:
+		return i.evalForInStatement(stmt, nil)
+	case *parser.ForOfStatement:
+		return i.evalForOfStatement(stmt, nil)
+	case *parser.LabeledStatement:
+		return i.evalLabeledStatement(stmt, nil)
+	case *parser.BreakStatement:
+		return completion{Type: breakCompletion, Target: labelName(stmt.Label)}
+	case *parser.ContinueStatement:
+		return completion{Type: continueCompletion, Target: labelName(stmt.Label)}
 	case *parser.ExpressionStatement:
 		result := i.evalExpression(stmt.Expression)
 		// Print the result of the expression if in REPL mode
@@ -92,420 +185,834 @@ func (i *Interpreter) evalStatement(stmt parser.Statement) {
 			fmt.Println(result)
 		}
 	}
+	return completion{Type: normalCompletion}
 }
 
-func (i *Interpreter) evalIfStatement(stmt *parser.IfStatement) {
+// evalVariableDeclaration initializes the bindings of a declaration. var bindings are hoisted, so a var
+// declaration only assigns the ones it initializes, to the binding the name refers to (e.g., a catch parameter).
+func (i *Interpreter) evalVariableDeclaration(stmt *parser.VariableDeclaration) {
+	for _, declarator := range stmt.Declarations {
+		name := declarator.Name.Value
+		if stmt.Token.Text != "var" {
+			// Bindings without an initializer default to undefined
+			var value Value = Undefined
+			if declarator.Value != nil {
+				value = i.evalExpression(declarator.Value)
+			}
+			i.scope.Declare(name, value, stmt.IsConstant)
+			continue
+		}
+		if declarator.Value == nil {
+			continue
+		}
+		value := i.evalExpression(declarator.Value)
+		if scope := i.scope.resolve(name); scope != nil {
+			scope.set(name, value)
+		} else {
+			i.scope.functionScope().Declare(name, value, false)
+		}
+	}
+}
+
+func (i *Interpreter) evalIfStatement(stmt *parser.IfStatement) completion {
 	condition := i.evalExpression(stmt.Condition)
-	if condition.(bool) {
-		i.evalBlockStatement(stmt.Consequence)
+	if isTruthy(condition) {
+		return i.evalBlockStatement(stmt.Consequence)
 	} else if stmt.Alternative != nil {
 		switch alternative := stmt.Alternative.Statements[0].(type) {
 		case *parser.IfStatement:
-			i.evalIfStatement(alternative)
+			return i.evalIfStatement(alternative)
 		default:
-			i.evalBlockStatement(stmt.Alternative)
+			return i.evalBlockStatement(stmt.Alternative)
 		}
 	}
+	return completion{Type: normalCompletion}
 }
 
-func (i *Interpreter) evalSwitchStatement(stmt *parser.SwitchStatement) {
-	exprVal := i.evalExpression(stmt.Expression)
+// evalSwitchStatement runs the statements from the first case clause strictly equal to the value, or from
+// the default clause if none is, falling through the next clauses until a break.
+func (i *Interpreter) evalSwitchStatement(stmt *parser.SwitchStatement) completion {
+	value := i.evalExpression(stmt.Expression)
 
+	var statements []parser.Statement
 	for _, caseClause := range stmt.Cases {
-		caseValue := i.evalExpression(caseClause.Condition)
-		if exprVal == caseValue {
-			i.evalBlockStatement(caseClause.Body)
-			return
+		statements = append(statements, caseClause.Consequent...)
+	}
+	caller := i.scope
+	defer func() { i.scope = caller }()
+	i.scope = NewEnclosedEnvironment(i.scope, false)
+	i.instantiateDeclarations(stmt, nil)
+
+	// Case expressions are evaluated in order until one matches, the default clause is skipped
+	start := -1
+	for idx, caseClause := range stmt.Cases {
+		if caseClause.Condition != nil && strictEquals(i.evalExpression(caseClause.Condition), value) {
+			start = idx
+			break
+		}
+	}
+	if start == -1 {
+		for idx, caseClause := range stmt.Cases {
+			if caseClause.Condition == nil {
+				start = idx
+			}
+		}
+	}
+	if start == -1 {
+		return completion{Type: normalCompletion}
+	}
+
+	for _, caseClause := range stmt.Cases[start:] {
+		for _, s := range caseClause.Consequent {
+			result := i.evalStatement(s)
+			if result.Type == normalCompletion {
+				continue
+			}
+			// A break only leaves the switch statement
+			if result.Type == breakCompletion && result.Target == "" {
+				return completion{Type: normalCompletion}
+			}
+			return result
+		}
+	}
+	return completion{Type: normalCompletion}
+}
+
+func (i *Interpreter) evalWhileStatement(stmt *parser.WhileStatement, labels []string) completion {
+	for isTruthy(i.evalExpression(stmt.Condition)) {
+		if next, result := loopContinues(i.evalBlockStatement(stmt.Body), labels); !next {
+			return result
+		}
+	}
+	return completion{Type: normalCompletion}
+}
+
+// evalForStatement runs a for loop. The let bindings of its head are copied into a new scope before each
+// iteration, so closures created in the body capture the values of their own iteration.
+func (i *Interpreter) evalForStatement(stmt *parser.ForStatement, labels []string) completion {
+	caller := i.scope
+	defer func() { i.scope = caller }()
+
+	var perIteration []string
+	switch init := stmt.Init.(type) {
+	case *parser.VariableDeclaration:
+		if init.Token.Text != "var" {
+			i.scope = NewEnclosedEnvironment(i.scope, false)
+			for _, declarator := range init.Declarations {
+				i.scope.DeclareUninitialized(declarator.Name.Value)
+				if !init.IsConstant {
+					perIteration = append(perIteration, declarator.Name.Value)
+				}
+			}
+		}
+		i.evalStatement(init)
+	case parser.Expression:
+		i.evalExpression(init)
+	}
+
+	i.copyBindings(perIteration)
+	for stmt.Test == nil || isTruthy(i.evalExpression(stmt.Test)) {
+		if next, result := loopContinues(i.evalStatement(stmt.Body), labels); !next {
+			return result
+		}
+		i.copyBindings(perIteration)
+		if stmt.Update != nil {
+			i.evalExpression(stmt.Update)
+		}
+	}
+	return completion{Type: normalCompletion}
+}
+
+// copyBindings replaces the current scope with a new one holding a copy of its bindings of the given names.
+func (i *Interpreter) copyBindings(names []string) {
+	if len(names) == 0 {
+		return
+	}
+	scope := NewEnclosedEnvironment(i.scope.outer, false)
+	for _, name := range names {
+		scope.Declare(name, i.scope.store[name], false)
+	}
+	i.scope = scope
+}
+
+// evalForInStatement runs the body for each property name of an object, skipping the properties deleted
+// before their iteration. Undefined and null run no iteration.
+func (i *Interpreter) evalForInStatement(stmt *parser.ForInStatement, labels []string) completion {
+	value := i.evalExpression(stmt.Right)
+	object, isObject := asObject(value)
+	for _, key := range forInKeys(value) {
+		if isObject {
+			if _, ok := object.Get(key); !ok {
+				continue
+			}
 		}
+		if next, result := loopContinues(i.evalForInIteration(stmt, key), labels); !next {
+			return result
+		}
+	}
+	return completion{Type: normalCompletion}
+}
+
+func (i *Interpreter) evalForInIteration(stmt *parser.ForInStatement, key string) completion {
+	caller := i.saveFrame()
+	defer i.restoreFrame(caller)
+
+	if !i.bindLoopTarget(stmt.Left, String(key)) {
+		return completion{Type: breakCompletion}
+	}
+	return i.evalStatement(stmt.Body)
+}
+
+// evalLabeledStatement runs the body of a labeled statement, a break targeting the label ends it.
+// The labels of a loop, possibly nested labeled statements, are the ones continue can target it with.
+func (i *Interpreter) evalLabeledStatement(stmt *parser.LabeledStatement, labels []string) completion {
+	labels = append(labels, stmt.Label.Value)
+
+	var result completion
+	switch body := stmt.Body.(type) {
+	case *parser.LabeledStatement:
+		result = i.evalLabeledStatement(body, labels)
+	case *parser.WhileStatement:
+		result = i.evalWhileStatement(body, labels)
+	case *parser.ForStatement:
+		result = i.evalForStatement(body, labels)
+	case *parser.ForInStatement:
+		result = i.evalForInStatement(body, labels)
+	case *parser.ForOfStatement:
+		result = i.evalForOfStatement(body, labels)
+	default:
+		result = i.evalStatement(body)
+	}
+
+	if result.Type == breakCompletion && result.Target == stmt.Label.Value {
+		return completion{Type: normalCompletion}
+	}
+	return result
+}
+
+// loopContinues tells a loop what to do after its body completes: go on with the next iteration, or
+// stop and complete with the returned completion. An unlabeled break only stops the loop, while a
+// return and a break or continue targeting an enclosing statement go on unwinding.
+func loopContinues(result completion, labels []string) (bool, completion) {
+	switch result.Type {
+	case normalCompletion:
+		return true, result
+	case continueCompletion:
+		if result.Target == "" {
+			return true, completion{Type: normalCompletion}
+		}
+		for _, label := range labels {
+			if label == result.Target {
+				return true, completion{Type: normalCompletion}
+			}
+		}
+	case breakCompletion:
+		if result.Target == "" {
+			return false, completion{Type: normalCompletion}
+		}
+	}
+	return false, result
+}
+
+func labelName(label *parser.Identifier) string {
+	if label == nil {
+		return ""
+	}
+	return label.Value
+}
+
+// evalWithStatement runs the body with the properties of the object in scope.
+func (i *Interpreter) evalWithStatement(stmt *parser.WithStatement) completion {
+	value := i.evalExpression(stmt.Object)
+	if isNullish(value) {
+		throwError("TypeError", "Cannot convert undefined or null to object")
+	}
+	object, ok := asObject(value)
+	if !ok {
+		// Primitives have no properties of their own to add to the scope
+		object = NewObject(nil)
 	}
 
-	if stmt.DefaultCase != nil {
-		i.evalBlockStatement(stmt.DefaultCase.Body)
+	caller := i.scope
+	defer func() { i.scope = caller }()
+	// Declarations in the body belong to a scope of their own, not to the object
+	i.scope = NewEnclosedEnvironment(NewObjectEnvironment(i.scope, object), false)
+	return i.evalStatement(stmt.Body)
+}
+
+// evalBlockStatement runs a block. A block declaring let, const or functions gets a scope of its own, where
+// they are declared before its first statement runs.
+func (i *Interpreter) evalBlockStatement(block *parser.BlockStatement) completion {
+	if i.hasDeclarations(block) {
+		caller := i.scope
+		defer func() { i.scope = caller }()
+		i.scope = NewEnclosedEnvironment(i.scope, false)
+		i.instantiateDeclarations(block, nil)
 	}
+	return i.evalStatements(block.Statements)
 }
 
-func (i *Interpreter) evalBlockStatement(block *parser.BlockStatement) {
-	for _, stmt := range block.Statements {
-		i.evalStatement(stmt)
+func (i *Interpreter) evalStatements(statements []parser.Statement) completion {
+	for _, stmt := range statements {
+		// Stop at the first statement that does not complete normally, e.g. a return
+		if result := i.evalStatement(stmt); result.Type != normalCompletion {
+			return result
+		}
 	}
+	return completion{Type: normalCompletion}
 }
 
-func (i *Interpreter) evalExpression(expr parser.Expression) interface{} {
+func (i *Interpreter) evalExpression(expr parser.Expression) Value {
 	switch expr := expr.(type) {
 	case *parser.StringLiteral:
-		return expr.Value
+		return String(expr.Value)
 	case *parser.BooleanLiteral:
-		return expr.Value
+		return Boolean(expr.Value)
+	case *parser.UndefinedLiteral:
+		return Undefined
+	case *parser.NullLiteral:
+		return Null
+	case *parser.TemplateLiteral:
+		return i.evalTemplateLiteral(expr)
+	case *parser.TaggedTemplateExpression:
+		return i.evalTaggedTemplate(expr)
+	case *parser.RegExpLiteral:
+		// Each evaluation creates a new object, the pattern was validated by the parser
+		return i.newRegExp(expr.Pattern, expr.Flags)
 	case *parser.IntegerLiteral:
-		integer, err := strconv.ParseInt(expr.Token.Text, 0, 64)
-		if err != nil {
-			fmt.Printf("Error (Line: %d): %v\n", expr.Token.Line, err)
-			return nil
-		}
-		return integer
+		return Number(expr.Value)
+	case *parser.BigIntLiteral:
+		return NewBigInt(new(big.Int).Set(expr.Value))
 	case *parser.Identifier:
-		identifierValue, ok := i.Env[expr.Value]
+		identifierValue, ok := i.scope.Get(expr.Value)
 		if !ok {
-			fmt.Printf("Error (Line: %d): Variable '%s' not found\n", expr.Token.Line, expr.Value)
-			return nil
+			throwError("ReferenceError", "%s is not defined", expr.Value)
 		}
 		return identifierValue
 	case *parser.AssignmentExpression:
 		return i.evalAssignmentExpression(expr)
+	case *parser.ThisExpression:
+		return i.this
+	case *parser.MetaProperty:
+		if expr.Meta.Value == "import" {
+			return i.importMeta()
+		}
+		return i.newTarget
+	case *parser.ImportExpression:
+		return i.evalImportExpression(expr)
 	case *parser.CallExpression:
 		return i.evalCallExpression(expr)
-	case *parser.MemberAccessExpression:
-		object := i.evalExpression(expr.Object)
-		if object == nil {
-			fmt.Printf("Error: Object '%s' not found\n", expr.Object.String())
-			return nil
-		}
-		if objectIdentifier, ok := expr.Object.(*parser.Identifier); ok && objectIdentifier.Value == "console" {
-			// Handle 'console.log'
-			if expr.Property.Value == "log" {
-				return "console.log"
-			} else {
-				fmt.Printf("Error: Unsupported method '%s' for object 'console'\n", expr.Property.Value)
-				return nil
-			}
-		}
-		return object
-	case *parser.ArrayLiteral:
-		var elements []interface{}
-		for _, element := range expr.Elements {
-			elements = append(elements, i.evalExpression(element))
+	case *parser.NewExpression:
+		return i.evalNewExpression(expr)
+	case *parser.ObjectLiteral:
+		return i.evalObjectLiteral(expr)
+	case *parser.FunctionExpression:
+		return i.evalFunctionExpression(expr, false)
+	case *parser.ArrowFunctionExpression:
+		return i.newFunction(&Function{Parameters: expr.Parameters, Body: expr.Body, Node: expr, Async: expr.Async,
+			Arrow: true, Strict: expr.Strict})
+	case *parser.AwaitExpression:
+		return i.generator.evalAwait(i.evalExpression(expr.Argument))
+	case *parser.YieldExpression:
+		var value Value = Undefined
+		if expr.Argument != nil {
+			value = i.evalExpression(expr.Argument)
 		}
-		return elements
-	case *parser.ArrayAccessExpression:
-		left := i.evalExpression(expr.Left)
-		index := i.evalExpression(expr.Index)
-		array, ok := left.([]interface{})
-		if !ok {
-			fmt.Printf("Error: Not an array (Line: %d)\n", expr.Token.Line)
-			return nil
+		if expr.Delegate {
+			return i.generator.evalDelegate(value)
 		}
-		arrayIndex, ok := index.(int64)
+		return i.generator.evalYield(value)
+	case *parser.MemberExpression:
+		ref, ok := i.evalReference(expr)
 		if !ok {
-			fmt.Printf("Error: Index is not an integer (Line: %d)\n", expr.Token.Line)
-			return nil
+			return Undefined
 		}
-		if arrayIndex < 0 || arrayIndex >= int64(len(array)) {
-			fmt.Printf("Error: Index out of bounds (Line: %d)\n", expr.Token.Line)
-			return nil
+		value, _ := i.getValue(ref)
+		return value
+	case *parser.ArrayLiteral:
+		var elements []Value
+		for _, element := range expr.Elements {
+			elements = append(elements, i.evalExpression(element))
 		}
-		return array[arrayIndex]
+		return newArrayObject(elements)
 	case *parser.BinaryExpression:
 		leftVal := i.evalExpression(expr.Left)
-		rightVal := i.evalExpression(expr.Right)
+		// Logical operators short-circuit and produce one of their operands
 		switch expr.Operator {
-		case "+":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt + rightInt
-			}
-			leftStr, leftOk := leftVal.(string)
-			rightStr, rightOk := rightVal.(string)
-			if leftOk && rightOk {
-				return leftStr + rightStr
-			}
-			fmt.Println("Error: Invalid types for + operation")
-			return nil
-		case "-":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt - rightInt
-			}
-			fmt.Println("Error: Invalid types for - operation")
-			return nil
-		case "*":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt * rightInt
-			}
-			fmt.Println("Error: Invalid types for * operation")
-			return nil
-		case "/":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				if rightInt != 0 {
-					return leftInt / rightInt
-				} else {
-					fmt.Println("Error: Division by zero")
-					return nil
-				}
-			}
-			fmt.Println("Error: Invalid types for / operation")
-			return nil
-		case "%":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt % rightInt
-			}
-			fmt.Println("Error: Invalid types for % operation")
-			return nil
-		case "<":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt < rightInt
-			}
-			leftStr, leftOk := leftVal.(string)
-			rightStr, rightOk := rightVal.(string)
-			if leftOk && rightOk {
-				return leftStr < rightStr
-			}
-			fmt.Println("Error: Invalid types for < operation")
-			return nil
-		case ">":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt > rightInt
-			}
-			leftStr, leftOk := leftVal.(string)
-			rightStr, rightOk := rightVal.(string)
-			if leftOk && rightOk {
-				return leftStr > rightStr
-			}
-			fmt.Println("Error: Invalid types for > operation")
-			return nil
-		case "<=":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt <= rightInt
-			}
-			leftStr, leftOk := leftVal.(string)
-			rightStr, rightOk := rightVal.(string)
-			if leftOk && rightOk {
-				return leftStr <= rightStr
-			}
-			fmt.Println("Error: Invalid types for <= operation")
-			return nil
-		case ">=":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt >= rightInt
-			}
-			leftStr, leftOk := leftVal.(string)
-			rightStr, rightOk := rightVal.(string)
-			if leftOk && rightOk {
-				return leftStr >= rightStr
-			}
-			fmt.Println("Error: Invalid types for >= operation")
-			return nil
-		case "==":
-			return leftVal == rightVal
-		case "!=":
-			return leftVal != rightVal
-		case "===":
-			return leftVal == rightVal
-		case "!==":
-			return leftVal != rightVal
 		case "&&":
-			leftBool, leftOk := leftVal.(bool)
-			rightBool, rightOk := rightVal.(bool)
-			if leftOk && rightOk {
-				return leftBool && rightBool
+			if !isTruthy(leftVal) {
+				return leftVal
 			}
-			fmt.Println("Error: Invalid types for && operation")
-			return nil
+			return i.evalExpression(expr.Right)
 		case "||":
-			leftBool, leftOk := leftVal.(bool)
-			rightBool, rightOk := rightVal.(bool)
-			if leftOk && rightOk {
-				return leftBool || rightBool
-			}
-			fmt.Println("Error: Invalid types for || operation")
-			return nil
-		case "&":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt & rightInt
+			if isTruthy(leftVal) {
+				return leftVal
 			}
-			fmt.Println("Error: Invalid types for & operation")
-			return nil
-		case "|":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt | rightInt
+			return i.evalExpression(expr.Right)
+		case "??":
+			if !isNullish(leftVal) {
+				return leftVal
 			}
-			fmt.Println("Error: Invalid types for | operation")
-			return nil
-		case "^":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt ^ rightInt
-			}
-			fmt.Println("Error: Invalid types for ^ operation")
-			return nil
-		case "<<":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt << rightInt
-			}
-			fmt.Println("Error: Invalid types for << operation")
-			return nil
-		case ">>":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return leftInt >> rightInt
-			}
-			fmt.Println("Error: Invalid types for >> operation")
-			return nil
-		case ">>>":
-			leftInt, leftOk := leftVal.(int64)
-			rightInt, rightOk := rightVal.(int64)
-			if leftOk && rightOk {
-				return int64(uint64(leftInt) >> uint64(rightInt))
-			}
-			fmt.Println("Error: Invalid types for >>> operation")
-			return nil
-		default:
-			fmt.Printf("Error: Unsupported operator '%s'\n", expr.Operator)
-			return nil
+			return i.evalExpression(expr.Right)
+		}
+		rightVal := i.evalExpression(expr.Right)
+		return i.evalBinaryOperation(expr.Operator, leftVal, rightVal)
+	case *parser.PrefixExpression:
+		return i.evalPrefixExpression(expr)
+	case *parser.UpdateExpression:
+		return i.evalUpdateExpression(expr)
+	case *parser.ConditionalExpression:
+		if isTruthy(i.evalExpression(expr.Test)) {
+			return i.evalExpression(expr.Consequent)
+		}
+		return i.evalExpression(expr.Alternate)
+	case *parser.SequenceExpression:
+		var result Value
+		for _, expression := range expr.Expressions {
+			result = i.evalExpression(expression)
 		}
+		return result
 	default:
 		fmt.Println("Error: Unsupported expression type", expr)
 	}
-	return nil
+	return Undefined
 }
 
-func (i *Interpreter) evalCallExpression(expr *parser.CallExpression) interface{} {
-	var functionName string
-	switch function := expr.Function.(type) {
-	case *parser.Identifier:
-		functionName = function.Value
-	case *parser.MemberAccessExpression:
-		object := i.evalExpression(function.Object)
-		if object == nil {
-			fmt.Printf("Error: Object '%s' not found\n", function.Object.String())
-			return nil
-		}
-		if objectIdentifier, ok := function.Object.(*parser.Identifier); ok {
-			switch objectIdentifier.Value {
-			case "console":
-				functionName = "console." + function.Property.Value
-			case "Math":
-				functionName = "Math." + function.Property.Value
+// evalBinaryOperation applies an operator to the values of its operands. + concatenates when either operand
+// is a string or an object, arithmetic operators otherwise work on numbers, or on two BigInts.
+func (i *Interpreter) evalBinaryOperation(operator string, leftVal Value, rightVal Value) Value {
+	switch operator {
+	case "===":
+		return Boolean(strictEquals(leftVal, rightVal))
+	case "!==":
+		return Boolean(!strictEquals(leftVal, rightVal))
+	case "==":
+		return Boolean(i.looselyEquals(leftVal, rightVal))
+	case "!=":
+		return Boolean(!i.looselyEquals(leftVal, rightVal))
+	case "<", ">", "<=", ">=":
+		// Unordered values, e.g. NaN, make every comparison false
+		order, ordered := compare(leftVal, rightVal)
+		switch {
+		case !ordered:
+			return Boolean(false)
+		case operator == "<":
+			return Boolean(order < 0)
+		case operator == ">":
+			return Boolean(order > 0)
+		case operator == "<=":
+			return Boolean(order <= 0)
+		default:
+			return Boolean(order >= 0)
+		}
+	case "in":
+		return Boolean(hasProperty(leftVal, rightVal))
+	case "instanceof":
+		return Boolean(i.instanceOf(leftVal, rightVal))
+	case "+":
+		if isConcatenated(leftVal) || isConcatenated(rightVal) {
+			return String(toString(leftVal) + toString(rightVal))
+		}
+	}
+
+	if leftBig, rightBig, ok := bigIntOperands(leftVal, rightVal); ok {
+		if result, ok := bigIntOperation(operator, leftBig, rightBig); ok {
+			return result
+		}
+	} else if leftVal.Kind() == BigIntKind || rightVal.Kind() == BigIntKind {
+		throwError("TypeError", "Cannot mix BigInt and other types, use explicit conversions")
+	} else if result, ok := numberOperation(operator, toNumber(leftVal), toNumber(rightVal)); ok {
+		return Number(result)
+	}
+	fmt.Printf("Error: Unsupported operator '%s'\n", operator)
+	return Undefined
+}
+
+// isConcatenated reports whether an operand of + makes it concatenate strings.
+func isConcatenated(value Value) bool {
+	return value.Kind() == StringKind || value.Kind() == ObjectKind
+}
+
+func (i *Interpreter) evalCallExpression(expr *parser.CallExpression) Value {
+	function, this, ok := i.evalCallee(expr.Function)
+	if !ok {
+		return Undefined
+	}
+	args := i.evalExpressions(expr.Arguments)
+
+	if !isCallable(function) {
+		throwError("TypeError", "%s is not a function", targetLabel(expr.Function))
+	}
+	return i.call(function, this, args...)
+}
+
+// evalCallee evaluates the function of a call, calling a property binds this to the object it was read from.
+func (i *Interpreter) evalCallee(expr parser.Expression) (Value, Value, bool) {
+	member, ok := expr.(*parser.MemberExpression)
+	if !ok {
+		return i.evalExpression(expr), Undefined, true
+	}
+	ref, ok := i.evalReference(member)
+	if !ok {
+		return Undefined, Undefined, false
+	}
+	function, ok := i.getValue(ref)
+	if !ok {
+		return Undefined, Undefined, false
+	}
+	return function, ref.base, true
+}
+
+// call calls a function value with the given this value and arguments.
+func (i *Interpreter) call(function Value, this Value, args ...Value) Value {
+	switch function := function.(type) {
+	case *BuiltinFunction:
+		return function.Call(this, args...)
+	case *Function:
+		return i.callFunction(function, this, args, Undefined)
+	case *BuiltinConstructor:
+		if function.Call == nil {
+			throwError("TypeError", "%s constructor cannot be invoked without 'new'", function.Name)
+		}
+		return function.Call(this, args...)
+	default:
+		throwError("TypeError", "%v is not a function", formatValue(function, 0))
+		return Undefined
+	}
+}
+
+// evalNewExpression implements [[Construct]]: the constructor runs with this bound to a new object
+// inheriting from its prototype property, and that object is the result unless an object is returned.
+func (i *Interpreter) evalNewExpression(expr *parser.NewExpression) Value {
+	constructor := i.evalExpression(expr.Callee)
+	args := i.evalExpressions(expr.Arguments)
+
+	if builtin, ok := constructor.(*BuiltinConstructor); ok && builtin.Construct != nil {
+		return builtin.Construct(args...)
+	}
+
+	function, ok := constructor.(*Function)
+	if !ok || !function.isConstructor() {
+		throwError("TypeError", "%s is not a constructor", targetLabel(expr.Callee))
+	}
+
+	// A prototype property that is not an object is ignored
+	prototypeValue, _ := function.Get("prototype")
+	prototype, _ := asObject(prototypeValue)
+	this := NewObject(prototype)
+
+	result := i.callFunction(function, this, args, function)
+	if _, ok := asObject(result); ok {
+		return result
+	}
+	return this
+}
+
+// callFunction runs the body of a function with the given this value, arguments and new.target.
+// The caller's state is restored even when an exception unwinds the call.
+func (i *Interpreter) callFunction(function *Function, this Value, args []Value,
+	newTarget Value) Value {
+	// The body runs in a new scope nested in the scope the function was created in
+	scope := NewEnclosedEnvironment(function.Closure, true)
+	for idx, param := range function.Parameters {
+		// Missing arguments are undefined
+		scope.Declare(param.Value, argument(args, idx), false)
+	}
+
+	// Arrow functions use the this value and new.target of the code they were created in
+	if function.Arrow {
+		this, newTarget = function.this, function.newTarget
+	} else if !function.Strict && isNullish(this) {
+		// Sloppy mode functions called without a this value get the global object
+		this = i.global
+	}
+	calleeFrame := frame{scope: scope, this: this, newTarget: newTarget, strict: function.Strict}
+
+	// Calling a generator function only creates the generator, the body runs on demand
+	if function.Generator && function.Async {
+		return i.newAsyncGenerator(function, calleeFrame)
+	}
+	if function.Generator {
+		return i.newGenerator(function, calleeFrame)
+	}
+	if function.Async {
+		return i.startAsync(function, calleeFrame)
+	}
+
+	caller := i.saveFrame()
+	i.restoreFrame(calleeFrame)
+	defer i.restoreFrame(caller)
+	return i.evalFunctionBody(function)
+}
+
+// evalFunctionBody evaluates the body of a function in the current frame and returns its result.
+func (i *Interpreter) evalFunctionBody(function *Function) Value {
+	body, ok := function.Body.(*parser.BlockStatement)
+	if !ok {
+		return i.evalExpression(function.Body.(parser.Expression))
+	}
+	i.instantiateDeclarations(function.Node, function)
+	if result := i.evalStatements(body.Statements); result.Type == returnCompletion {
+		return result.Value
+	}
+	return Undefined
+}
+
+// evalTryStatement runs the try block, then the catch clause if the block threw, and always the finally block.
+// An exception or a return from the finally block overrides how the rest of the statement completed.
+func (i *Interpreter) evalTryStatement(stmt *parser.TryStatement) completion {
+	result, thrown := i.evalProtected(func() completion { return i.evalBlockStatement(stmt.Block) })
+
+	if exception, ok := thrown.(*Exception); ok && stmt.Handler != nil {
+		i.completeError(exception.Value)
+		result, thrown = i.evalProtected(func() completion {
+			// The catch parameter and the declarations of the body share the scope of the catch clause
+			i.scope = NewEnclosedEnvironment(i.scope, false)
+			if stmt.Handler.Param != nil {
+				i.scope.Declare(stmt.Handler.Param.Value, exception.Value, false)
+			}
+			i.instantiateDeclarations(stmt.Handler, nil)
+			return i.evalStatements(stmt.Handler.Body.Statements)
+		})
+	}
+
+	if stmt.Finalizer != nil {
+		if finalResult := i.evalBlockStatement(stmt.Finalizer); finalResult.Type != normalCompletion {
+			return finalResult
+		}
+	}
+
+	if thrown != nil {
+		panic(thrown)
+	}
+	return result
+}
+
+// evalProtected runs a part of a try statement, recovering the exception or generator return unwinding it
+// so that the rest of the statement can run.
+func (i *Interpreter) evalProtected(run func() completion) (result completion, thrown interface{}) {
+	caller := i.saveFrame()
+	defer func() {
+		i.restoreFrame(caller)
+		if recovered := recover(); recovered != nil {
+			switch recovered.(type) {
+			case *Exception, *generatorReturn:
+				thrown = recovered
 			default:
-				fmt.Printf("Error: Unsupported object '%s'\n", function.Object.String())
-				return nil
+				panic(recovered)
 			}
-		} else {
-			fmt.Printf("Error: Unsupported object '%s'\n", function.Object.String())
-			return nil
 		}
-	default:
-		fmt.Printf("Error: Unsupported function type '%s'\n", expr.Function.String())
-		return nil
+	}()
+	return run(), nil
+}
+
+// evalFunctionExpression creates the function of a function expression, or of a method (e.g., { m() {} }).
+func (i *Interpreter) evalFunctionExpression(expr *parser.FunctionExpression, method bool) *Function {
+	var name string
+	if expr.Name != nil {
+		name = expr.Name.Value
 	}
+	return i.newFunction(&Function{
+		Name:       name,
+		Parameters: expr.Parameters,
+		Body:       expr.Body,
+		Node:       expr,
+		Generator:  expr.Generator,
+		Async:      expr.Async,
+		Method:     method,
+		Strict:     expr.Strict,
+	})
+}
 
-	switch functionName {
-	case "console.log":
-		args := i.evalExpressions(expr.Arguments)
-		if logFunc, ok := i.Env["console"].(map[string]interface{})["log"].(func(...interface{})); ok {
-			logFunc(args...)
+func (i *Interpreter) evalObjectLiteral(expr *parser.ObjectLiteral) Value {
+	object := NewObject(nil)
+	for _, property := range expr.Properties {
+		var key string
+		switch {
+		case property.Computed:
+			key = propertyKey(i.evalExpression(property.Key))
+		case property.Shorthand:
+			key = property.Key.(*parser.Identifier).Value
+		default:
+			key = literalKey(property.Key)
+		}
+		var value Value
+		if method, ok := property.Value.(*parser.FunctionExpression); ok && property.Method {
+			value = i.evalFunctionExpression(method, true)
 		} else {
-			fmt.Println("Error: console.log function not found")
+			value = i.evalExpression(property.Value)
 		}
-		return nil
-	case "Math.sqrt":
-		if len(expr.Arguments) != 1 {
-			fmt.Printf("Error: Math.sqrt expects 1 argument, got %d\n", len(expr.Arguments))
-			return nil
+		if function, ok := value.(*Function); ok && isAnonymousFunction(property.Value) {
+			function.Name = key
+			if isSymbolKey(key) {
+				function.Name = symbolKeyLabel(key)
+			}
 		}
-		sqrtNumber := i.evalExpression(expr.Arguments[0])
-		if num, ok := sqrtNumber.(int64); ok {
-			return math.Sqrt(float64(num))
-		} else {
-			fmt.Printf("Error: Math.sqrt expects a numeric argument\n")
-			return nil
-		}
-	case "Math.pow":
-		if len(expr.Arguments) != 2 {
-			fmt.Printf("Error: Math.pow expects 2 arguments, got %d\n", len(expr.Arguments))
-			return nil
-		}
-		base := i.evalExpression(expr.Arguments[0])
-		exponent := i.evalExpression(expr.Arguments[1])
-		if baseNum, ok1 := base.(int64); ok1 {
-			if expNum, ok2 := exponent.(int64); ok2 {
-				return math.Pow(float64(baseNum), float64(expNum))
+
+		// A literal __proto__ property sets the prototype when the value is an object or null
+		if key == "__proto__" && !property.Computed && !property.Shorthand {
+			if prototype, ok := asObject(value); ok {
+				object.Prototype = prototype
+			} else if value == Null {
+				object.Prototype = nil
 			}
+			continue
+		}
+		object.Set(key, value)
+	}
+	return object
+}
+
+func (i *Interpreter) evalAssignmentExpression(expr *parser.AssignmentExpression) Value {
+	ref, ok := i.evalReference(expr.Left)
+	if !ok {
+		return Undefined
+	}
+
+	var evaluated Value
+	switch expr.Operator {
+	case "=":
+		evaluated = i.evalExpression(expr.Value)
+	case "&&=", "||=", "??=":
+		current, ok := i.getValue(ref)
+		if !ok {
+			return Undefined
+		}
+		// Logical assignments short-circuit and only assign when the right side is evaluated
+		shortCircuits := map[string]bool{
+			"&&=": !isTruthy(current),
+			"||=": isTruthy(current),
+			"??=": !isNullish(current),
 		}
-		fmt.Printf("Error: Math.pow expects numeric arguments\n")
-		return nil
-	case "typeof":
-		if len(expr.Arguments) != 1 {
-			fmt.Printf("Error: typeof expects 1 argument, got %d\n", len(expr.Arguments))
-			return nil
+		if shortCircuits[expr.Operator] {
+			return current
 		}
-		typeArgument := i.evalExpression(expr.Arguments[0])
-		return fmt.Sprintf("%T", typeArgument)
+		evaluated = i.evalExpression(expr.Value)
 	default:
-		// Check if it's a user-defined function
-		if function, ok := i.Env[functionName]; ok {
-			if funcDecl, ok := function.(*parser.FunctionDeclaration); ok {
-				// TODO: Probably improve this with stack based environment
-				// Create a new environment for the function call
-				newEnv := make(map[string]interface{})
-				for k, v := range i.Env {
-					newEnv[k] = v
-				}
+		current, ok := i.getValue(ref)
+		if !ok {
+			return Undefined
+		}
+		// Compound assignment, e.g. "+=" applies "+"
+		operator := expr.Operator[:len(expr.Operator)-1]
+		evaluated = i.evalBinaryOperation(operator, current, i.evalExpression(expr.Value))
+	}
 
-				for idx, param := range funcDecl.Parameters {
-					newEnv[param.Value] = i.evalExpression(expr.Arguments[idx])
-				}
+	if !i.putValue(ref, evaluated) {
+		return Undefined
+	}
 
-				originalEnv := i.Env
-				i.Env = newEnv
-				i.evalBlockStatement(funcDecl.Body)
-				i.Env = originalEnv
+	fmt.Printf("%s = %v (Line: %d)\n", ref.label, evaluated, expr.Token.Line)
+	return evaluated
+}
 
-				return nil // TODO: This should probably return the result of evalBlockStatement?
+func (i *Interpreter) evalPrefixExpression(expr *parser.PrefixExpression) Value {
+	if expr.Operator == "typeof" {
+		// typeof an undeclared variable is "undefined" rather than an error
+		if identifier, ok := expr.Right.(*parser.Identifier); ok {
+			if _, declared := i.scope.Get(identifier.Value); !declared {
+				return String("undefined")
 			}
 		}
+		return String(typeOf(i.evalExpression(expr.Right)))
+	}
+	if expr.Operator == "delete" {
+		switch operand := expr.Right.(type) {
+		case *parser.Identifier:
+			// Variables cannot be deleted
+			return Boolean(false)
+		case *parser.MemberExpression:
+			ref, ok := i.evalReference(operand)
+			if !ok {
+				return Boolean(false)
+			}
+			return Boolean(i.deleteProperty(ref))
+		default:
+			// Any other operand is evaluated and discarded
+			i.evalExpression(expr.Right)
+			return Boolean(true)
+		}
+	}
 
-		fmt.Printf("Error: Unsupported function call '%s'\n", functionName)
-		return nil
+	right := i.evalExpression(expr.Right)
+	switch expr.Operator {
+	case "void":
+		return Undefined
+	case "!":
+		return Boolean(!isTruthy(right))
+	case "-":
+		if value, ok := right.(*BigInt); ok {
+			return NewBigInt(new(big.Int).Neg(value.value))
+		}
+		return Number(-toNumber(right))
+	case "+":
+		return Number(toNumber(right))
+	case "~":
+		if value, ok := right.(*BigInt); ok {
+			return NewBigInt(new(big.Int).Not(value.value))
+		}
+		return Number(^toInt32(toNumber(right)))
 	}
+	fmt.Printf("Error (Line: %d): Invalid type for %s operation\n", expr.Token.Line, expr.Operator)
+	return Undefined
 }
 
-func (i *Interpreter) evalAssignmentExpression(expr *parser.AssignmentExpression) interface{} {
-	_, ok := i.Env[expr.Name.Value]
+func (i *Interpreter) evalUpdateExpression(expr *parser.UpdateExpression) Value {
+	ref, ok := i.evalReference(expr.Argument)
 	if !ok {
-		fmt.Printf("Error (Line: %d): Variable '%s' not found\n", expr.Token.Line, expr.Name.Value)
-		return nil
+		return Undefined
 	}
-
-	if i.Constants[expr.Name.Value] {
-		fmt.Printf("Error (Line: %d): Cannot reassign to constant variable '%s'\n", expr.Token.Line, expr.Name.Value)
-		return nil
+	current, ok := i.getValue(ref)
+	if !ok {
+		return Undefined
 	}
 
-	evaluated := i.evalExpression(expr.Value)
-	i.Env[expr.Name.Value] = evaluated
+	// The old value is converted to a number, which a postfix update returns, and BigInts change by 1n
+	var oldValue, one Value = Number(0), Number(1)
+	if bigInt, ok := current.(*BigInt); ok {
+		oldValue, one = bigInt, NewBigInt(big.NewInt(1))
+	} else {
+		oldValue = Number(toNumber(current))
+	}
+	// "++" adds and "--" subtracts
+	newValue := i.evalBinaryOperation(expr.Operator[:1], oldValue, one)
+	if !i.putValue(ref, newValue) {
+		return Undefined
+	}
 
-	fmt.Printf("%s = %v (Line: %d)\n", expr.Name.Value, evaluated, expr.Token.Line)
-	return evaluated
+	if expr.Prefix {
+		return newValue
+	}
+	return oldValue
 }
 
-func (i *Interpreter) evalExpressions(expressions []parser.Expression) []interface{} {
-	var result []interface{}
+func (i *Interpreter) evalExpressions(expressions []parser.Expression) []Value {
+	var result []Value
 	for _, expression := range expressions {
 		result = append(result, i.evalExpression(expression))
 	}
 	return result
 }
+
+// isAnonymousFunction reports whether an expression defines a function without a name, such functions
+// are named after the property they are assigned to.
+func isAnonymousFunction(expr parser.Expression) bool {
+	switch expr := expr.(type) {
+	case *parser.FunctionExpression:
+		return expr.Name == nil
+	case *parser.ArrowFunctionExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// isCallable reports whether a value is a function that can be called.
+func isCallable(value Value) bool {
+	switch value.(type) {
+	case *BuiltinFunction, *Function, *BuiltinConstructor:
+		return true
+	default:
+		return false
+	}
+}
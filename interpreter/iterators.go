@@ -0,0 +1,178 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/parser"
+)
+
+// iteratorRecord is an iterator obtained from an iterable through the iteration protocol.
+type iteratorRecord struct {
+	iterator Value // The iterator object
+	next     Value // Its next method
+	async    bool  // Whether next returns promises, for iterators from Symbol.asyncIterator
+}
+
+// getIterator gets an iterator from an iterable. Async iteration prefers Symbol.asyncIterator and falls back
+// to Symbol.iterator, arrays and strings are iterated by builtin iterators.
+func (i *Interpreter) getIterator(iterable Value, async bool) *iteratorRecord {
+	if async {
+		if method := i.getMethod(iterable, SymbolAsyncIterator); method != Undefined {
+			return i.iteratorFromMethod(iterable, method, true)
+		}
+	}
+
+	switch iterable := iterable.(type) {
+	case String:
+		var chars []Value
+		for _, char := range iterable {
+			chars = append(chars, String(char))
+		}
+		return i.sliceIterator(chars)
+	case *Object:
+		if _, ok := iterable.internal.(arrayObject); ok {
+			return i.sliceIterator(arrayElements(iterable))
+		}
+	}
+
+	method := i.getMethod(iterable, SymbolIterator)
+	if method == Undefined {
+		throwError("TypeError", "%v is not iterable", iterable)
+	}
+	return i.iteratorFromMethod(iterable, method, false)
+}
+
+func (i *Interpreter) iteratorFromMethod(iterable Value, method Value, async bool) *iteratorRecord {
+	iterator := i.call(method, iterable)
+	object, ok := asObject(iterator)
+	if !ok {
+		throwError("TypeError", "Result of the Symbol.iterator method is not an object")
+	}
+	next, _ := object.Get("next")
+	return &iteratorRecord{iterator: iterator, next: next, async: async}
+}
+
+// sliceIterator creates an iterator over the elements of a slice.
+func (i *Interpreter) sliceIterator(elements []Value) *iteratorRecord {
+	index := 0
+	iterator := NewObject(nil)
+	next := NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if index >= len(elements) {
+			return iteratorResult(Undefined, true)
+		}
+		index++
+		return iteratorResult(elements[index-1], false)
+	})
+	iterator.Set("next", next)
+	return &iteratorRecord{iterator: iterator, next: next}
+}
+
+// getMethod returns the callable property of a value, or undefined if it is missing.
+func (i *Interpreter) getMethod(value Value, key Value) Value {
+	object, ok := asObject(value)
+	if !ok {
+		return Undefined
+	}
+	method, _ := object.Get(propertyKey(key))
+	if !isCallable(method) {
+		return Undefined
+	}
+	return method
+}
+
+// iteratorStep calls a method of the iterator (next, throw or return) and unpacks the result,
+// awaiting it for async iterators.
+func (i *Interpreter) iteratorStep(record *iteratorRecord, method Value, value Value) (Value,
+	bool) {
+	result := i.call(method, record.iterator, value)
+	if record.async {
+		result = i.generator.evalAwait(result)
+	}
+	object, ok := asObject(result)
+	if !ok {
+		throwError("TypeError", "Iterator result %v is not an object", result)
+	}
+	done, _ := object.Get("done")
+	resultValue, _ := object.Get("value")
+	return resultValue, isTruthy(done)
+}
+
+// iteratorClose calls the return method of an iterator that is left before it is done, if it has one.
+func (i *Interpreter) iteratorClose(record *iteratorRecord) {
+	method := i.getMethod(record.iterator, String("return"))
+	if method == Undefined {
+		return
+	}
+	result := i.call(method, record.iterator)
+	if record.async {
+		i.generator.evalAwait(result)
+	}
+}
+
+// evalForOfStatement runs the body for each value of an iterable. The iterator is closed when the loop
+// is left early by a break, a return or an exception.
+func (i *Interpreter) evalForOfStatement(stmt *parser.ForOfStatement, labels []string) completion {
+	record := i.getIterator(i.evalExpression(stmt.Right), stmt.Await)
+
+	for {
+		value, done := i.iteratorStep(record, record.next, Undefined)
+		if done {
+			return completion{Type: normalCompletion}
+		}
+		// Values of sync iterables are awaited one by one in for await loops
+		if stmt.Await && !record.async {
+			value = i.generator.evalAwait(value)
+		}
+
+		if next, result := loopContinues(i.evalForOfIteration(stmt, record, value), labels); !next {
+			i.iteratorClose(record)
+			return result
+		}
+	}
+}
+
+// evalForOfIteration binds the value and runs the body once. let and const bindings are fresh for each
+// iteration, so closures created in the body capture the value of their own iteration.
+func (i *Interpreter) evalForOfIteration(stmt *parser.ForOfStatement, record *iteratorRecord,
+	value Value) completion {
+	caller := i.saveFrame()
+	defer func() {
+		i.restoreFrame(caller)
+		if recovered := recover(); recovered != nil {
+			if _, ok := recovered.(*Exception); ok {
+				i.iteratorClose(record)
+			}
+			panic(recovered)
+		}
+	}()
+
+	if !i.bindLoopTarget(stmt.Left, value) {
+		return completion{Type: breakCompletion}
+	}
+	return i.evalStatement(stmt.Body)
+}
+
+// bindLoopTarget assigns the value of an iteration of a for...of or for...in loop to the left side of the loop.
+// let and const declarations bind it in a new scope. It reports false when the assignment fails.
+func (i *Interpreter) bindLoopTarget(left parser.Node, value Value) bool {
+	switch left := left.(type) {
+	case *parser.VariableDeclaration:
+		name := left.Declarations[0].Name.Value
+		if left.Token.Text == "var" {
+			i.scope.functionScope().Declare(name, value, false)
+		} else {
+			i.scope = NewEnclosedEnvironment(i.scope, false)
+			i.scope.Declare(name, value, left.IsConstant)
+		}
+	case parser.Expression:
+		ref, ok := i.evalReference(left)
+		if !ok || !i.putValue(ref, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// throwError throws an exception for an error detected by the interpreter, e.g. a TypeError.
+func throwError(name string, format string, args ...interface{}) {
+	panic(&Exception{Value: newError(nil, name, fmt.Sprintf(format, args...))})
+}
@@ -0,0 +1,80 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/parser"
+)
+
+// defaultExportBinding holds the value of an export default expression, it is not a valid identifier
+// so the module code cannot refer to it.
+const defaultExportBinding = "*default*"
+
+// evalModule runs the body of a module like the body of an async function, so top-level await suspends
+// the module until the awaited value settles. An exception thrown by the module is reported as uncaught.
+func (i *Interpreter) evalModule(program *parser.Program) {
+	body := i.newFunction(&Function{
+		Name:   "module",
+		Body:   &parser.BlockStatement{Statements: program.Statements},
+		Node:   program,
+		Async:  true,
+		Strict: true,
+	})
+	// Module code runs in the global scope with this undefined
+	moduleFrame := frame{scope: i.scope, this: Undefined, newTarget: Undefined, strict: true}
+	result := promiseOf(i.startAsync(body, moduleFrame))
+	i.then(result, func(value Value) {}, func(reason Value) {
+		fmt.Println((&Exception{Value: reason}).Error())
+	})
+}
+
+// evalModuleDeclaration evaluates an import or export declaration. Exported declarations are evaluated
+// like any other declaration, loading other modules is not supported.
+func (i *Interpreter) evalModuleDeclaration(stmt parser.Statement) completion {
+	switch stmt := stmt.(type) {
+	case *parser.ImportDeclaration:
+		throwModuleNotSupported(stmt.Source.Value)
+	case *parser.ExportAllDeclaration:
+		throwModuleNotSupported(stmt.Source.Value)
+	case *parser.ExportNamedDeclaration:
+		if stmt.Source != nil {
+			throwModuleNotSupported(stmt.Source.Value)
+		}
+		if stmt.Declaration != nil {
+			return i.evalStatement(stmt.Declaration)
+		}
+	case *parser.ExportDefaultDeclaration:
+		// Function declarations are instantiated with the module
+		if declaration, ok := stmt.Declaration.(parser.Expression); ok {
+			value := i.evalExpression(declaration)
+			if function, ok := value.(*Function); ok && isAnonymousFunction(declaration) {
+				function.Name = "default"
+			}
+			i.scope.Declare(defaultExportBinding, value, false)
+		}
+	}
+	return completion{Type: normalCompletion}
+}
+
+// evalImportExpression evaluates import(): the module is never loaded, so the returned promise is rejected.
+func (i *Interpreter) evalImportExpression(expr *parser.ImportExpression) Value {
+	specifier := i.evalExpression(expr.Source)
+	object, p := i.newPromise()
+	i.rejectPromise(p, newError(i.errorPrototypes["Error"], "Error", moduleNotSupported(toString(specifier))))
+	return object
+}
+
+// importMeta returns the import.meta object, created the first time it is used.
+func (i *Interpreter) importMeta() *Object {
+	if i.meta == nil {
+		i.meta = NewObject(nil)
+	}
+	return i.meta
+}
+
+func moduleNotSupported(specifier string) string {
+	return fmt.Sprintf("Cannot load module '%s': loading modules is not supported", specifier)
+}
+
+func throwModuleNotSupported(specifier string) {
+	throwError("Error", "%s", moduleNotSupported(specifier))
+}
@@ -0,0 +1,176 @@
+package interpreter
+
+import (
+	"gojo/parser"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// formatNumber converts a number to a string as Number.prototype.toString does: integers below 10^21 in full,
+// other numbers with the shortest digits that read back as the same number, with an exponent when they are
+// very large or very small (e.g., 1e+21 and 1e-7).
+func formatNumber(number float64) string {
+	switch {
+	case math.IsNaN(number):
+		return "NaN"
+	case math.IsInf(number, 1):
+		return "Infinity"
+	case math.IsInf(number, -1):
+		return "-Infinity"
+	case number == 0:
+		return "0"
+	}
+	sign := ""
+	if number < 0 {
+		sign, number = "-", -number
+	}
+
+	// The number is 0.digits × 10^point
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(number, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	point, _ := strconv.Atoi(exponent)
+	point++
+	switch {
+	case len(digits) <= point && point <= 21:
+		return sign + digits + strings.Repeat("0", point-len(digits))
+	case 0 < point && point <= 21:
+		return sign + digits[:point] + "." + digits[point:]
+	case -6 < point && point <= 0:
+		return sign + "0." + strings.Repeat("0", -point) + digits
+	}
+	exponent = strconv.Itoa(point - 1)
+	if point > 0 {
+		exponent = "+" + exponent
+	}
+	if len(digits) == 1 {
+		return sign + digits + "e" + exponent
+	}
+	return sign + digits[:1] + "." + digits[1:] + "e" + exponent
+}
+
+// toNumber converts a value to a number. Strings are parsed, ignoring surrounding whitespace, and are NaN
+// unless they are entirely a number. Objects convert through their string.
+func toNumber(value Value) float64 {
+	switch value := value.(type) {
+	case Number:
+		return float64(value)
+	case undefined:
+		return math.NaN()
+	case null:
+		return 0
+	case Boolean:
+		if value {
+			return 1
+		}
+		return 0
+	case String:
+		return parser.StringToNumber(string(value))
+	case *Symbol:
+		throwError("TypeError", "Cannot convert a Symbol value to a number")
+	case *BigInt:
+		throwError("TypeError", "Cannot convert a BigInt value to a number")
+	}
+	return parser.StringToNumber(toString(value))
+}
+
+// toUint32 implements ToUint32: the integer part of a number modulo 2^32, NaN and the infinities are 0.
+func toUint32(number float64) uint32 {
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0
+	}
+	modulo := math.Mod(math.Trunc(number), 1<<32)
+	if modulo < 0 {
+		modulo += 1 << 32
+	}
+	return uint32(modulo)
+}
+
+// toInt32 implements ToInt32: ToUint32 with the values from 2^31 wrapped around to negative numbers.
+func toInt32(number float64) int32 {
+	return int32(toUint32(number))
+}
+
+// exponentiate implements the ** operator, which unlike math.Pow is NaN for a NaN exponent and for 1 or -1
+// raised to an infinite power.
+func exponentiate(base float64, exponent float64) float64 {
+	if math.IsNaN(exponent) || math.IsInf(exponent, 0) && math.Abs(base) == 1 {
+		return math.NaN()
+	}
+	return math.Pow(base, exponent)
+}
+
+// numberOperation applies an arithmetic, bitwise or shift operator to two numbers. Bitwise operators work on
+// the numbers converted to 32-bit integers, and shifts use the low 5 bits of the shift count.
+func numberOperation(operator string, left float64, right float64) (float64, bool) {
+	switch operator {
+	case "+":
+		return left + right, true
+	case "-":
+		return left - right, true
+	case "*":
+		return left * right, true
+	case "/":
+		return left / right, true
+	case "%":
+		// Like JavaScript, the result has the sign of the dividend
+		return math.Mod(left, right), true
+	case "**":
+		return exponentiate(left, right), true
+	case "&":
+		return float64(toInt32(left) & toInt32(right)), true
+	case "|":
+		return float64(toInt32(left) | toInt32(right)), true
+	case "^":
+		return float64(toInt32(left) ^ toInt32(right)), true
+	case "<<":
+		return float64(toInt32(left) << (toUint32(right) & 31)), true
+	case ">>":
+		return float64(toInt32(left) >> (toUint32(right) & 31)), true
+	case ">>>":
+		return float64(toUint32(left) >> (toUint32(right) & 31)), true
+	default:
+		return 0, false
+	}
+}
+
+// compare orders two values for the relational operators: strings by their characters, anything else as
+// numbers. It reports false when the values are unordered, i.e. when one of them is NaN.
+func compare(left Value, right Value) (int, bool) {
+	if leftStr, rightStr, ok := stringOperands(left, right); ok {
+		return strings.Compare(string(leftStr), string(rightStr)), true
+	}
+	if left.Kind() == BigIntKind || right.Kind() == BigIntKind {
+		leftNum, leftOk := bigFloatOf(left)
+		rightNum, rightOk := bigFloatOf(right)
+		if !leftOk || !rightOk {
+			return 0, false
+		}
+		return leftNum.Cmp(rightNum), true
+	}
+	leftNum, rightNum := toNumber(left), toNumber(right)
+	switch {
+	case math.IsNaN(leftNum) || math.IsNaN(rightNum):
+		return 0, false
+	case leftNum < rightNum:
+		return -1, true
+	case leftNum > rightNum:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// bigFloatOf converts a BigInt or a value converting to a number other than NaN to a big.Float, to compare
+// BigInts with numbers exactly.
+func bigFloatOf(value Value) (*big.Float, bool) {
+	if bigInt, ok := value.(*BigInt); ok {
+		return new(big.Float).SetInt(bigInt.value), true
+	}
+	number := toNumber(value)
+	if math.IsNaN(number) {
+		return nil, false
+	}
+	return big.NewFloat(number), true
+}
@@ -0,0 +1,362 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/parser"
+	"strconv"
+	"strings"
+)
+
+// Object is a JavaScript object: an ordered set of properties and a link to its prototype.
+type Object struct {
+	Prototype  *Object // nil for objects without a prototype
+	Properties map[string]Value
+	keys       []string        // Property names in insertion order
+	internal   interface{}     // State of objects backed by Go, e.g. the *generator of a generator object
+	frozen     bool            // Whether its properties can no longer be added, changed or removed
+	hidden     map[string]bool // Own properties for...in loops leave out, e.g. the methods of built-in prototypes
+}
+
+func NewObject(prototype *Object) *Object {
+	return &Object{Prototype: prototype, Properties: make(map[string]Value)}
+}
+
+// Get looks up a property on the object and then along its prototype chain.
+func (o *Object) Get(key string) (Value, bool) {
+	for object := o; object != nil; object = object.Prototype {
+		if value, ok := object.Properties[key]; ok {
+			return value, true
+		}
+	}
+	return Undefined, false
+}
+
+// Set creates or updates an own property of the object.
+func (o *Object) Set(key string, value Value) {
+	if _, ok := o.Properties[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.Properties[key] = value
+}
+
+// SetHidden creates or updates an own property of the object that for...in loops do not visit.
+func (o *Object) SetHidden(key string, value Value) {
+	o.Set(key, value)
+	if o.hidden == nil {
+		o.hidden = map[string]bool{}
+	}
+	o.hidden[key] = true
+}
+
+// Delete removes an own property of the object.
+func (o *Object) Delete(key string) {
+	if _, ok := o.Properties[key]; !ok {
+		return
+	}
+	delete(o.Properties, key)
+	delete(o.hidden, key)
+	for idx, name := range o.keys {
+		if name == key {
+			o.keys = append(o.keys[:idx], o.keys[idx+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the own property names of the object in insertion order.
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) String() string {
+	return o.format(0)
+}
+
+func (o *Object) format(depth int) string {
+	if p, ok := o.internal.(*promise); ok {
+		switch p.state {
+		case fulfilled:
+			return fmt.Sprintf("Promise { %v }", p.value)
+		case rejected:
+			return fmt.Sprintf("Promise { <rejected> %v }", p.value)
+		default:
+			return "Promise { <pending> }"
+		}
+	}
+	if _, ok := o.internal.(errorObject); ok {
+		return errorString(o)
+	}
+	if _, ok := o.internal.(globalObject); ok {
+		return "Object [global]"
+	}
+	if r, ok := o.internal.(*regExp); ok {
+		return fmt.Sprintf("/%s/%s", r.re.Source, r.re.Flags)
+	}
+	_, isArray := o.internal.(arrayObject)
+	if isArray {
+		return o.formatArray(depth)
+	}
+	if len(o.keys) == 0 {
+		return "{}"
+	}
+	// Deeply nested objects are abbreviated, which also keeps cycles from recursing forever
+	if depth > 2 {
+		return "[Object]"
+	}
+	return "{ " + strings.Join(o.formatProperties(o.keys, depth), ", ") + " }"
+}
+
+// formatArray lists the elements of an array object, then its other properties except length.
+func (o *Object) formatArray(depth int) string {
+	elements := arrayElements(o)
+	var keys []string
+	for _, key := range o.keys {
+		if index, ok := arrayIndex(key); key != "length" && (!ok || index >= len(elements)) {
+			keys = append(keys, key)
+		}
+	}
+	if len(elements) == 0 && len(keys) == 0 {
+		return "[]"
+	}
+	if depth > 2 {
+		return "[Array]"
+	}
+	var properties []string
+	for _, element := range elements {
+		properties = append(properties, formatValue(element, depth))
+	}
+	properties = append(properties, o.formatProperties(keys, depth)...)
+	return "[ " + strings.Join(properties, ", ") + " ]"
+}
+
+func (o *Object) formatProperties(keys []string, depth int) []string {
+	var properties []string
+	for _, key := range keys {
+		label := key
+		if isSymbolKey(key) {
+			label = symbolKeyLabel(key)
+		}
+		properties = append(properties, fmt.Sprintf("%s: %s", label, formatValue(o.Properties[key], depth)))
+	}
+	return properties
+}
+
+// formatValue renders a property value of an object printed at the given depth.
+func formatValue(value Value, depth int) string {
+	switch value := value.(type) {
+	case *Object:
+		return value.format(depth + 1)
+	case String:
+		return fmt.Sprintf("'%s'", value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// arrayObject marks an array. Its elements are the properties 0 to length - 1, it can have other properties
+// (e.g., the index of a match).
+type arrayObject struct{}
+
+func newArrayObject(elements []Value) *Object {
+	array := NewObject(nil)
+	array.internal = arrayObject{}
+	for idx, element := range elements {
+		array.Set(strconv.Itoa(idx), element)
+	}
+	array.SetHidden("length", Number(len(elements)))
+	return array
+}
+
+// arrayElements returns the elements of an array object.
+func arrayElements(array *Object) []Value {
+	length, _ := array.Get("length")
+	var elements []Value
+	for idx := 0; idx < toIndex(length); idx++ {
+		element, _ := array.Get(strconv.Itoa(idx))
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+// setArrayProperty sets a property of an array object. Setting an element past the end grows the length, and
+// setting the length removes the elements past it.
+func setArrayProperty(array *Object, key string, value Value) {
+	lengthValue, _ := array.Get("length")
+	length := toIndex(lengthValue)
+	if key == "length" {
+		for idx := toIndex(value); idx < length; idx++ {
+			array.Delete(strconv.Itoa(idx))
+		}
+		array.SetHidden("length", Number(toIndex(value)))
+		return
+	}
+	if index, ok := arrayIndex(key); ok && index >= length {
+		array.SetHidden("length", Number(index+1))
+	}
+	array.Set(key, value)
+}
+
+// arrayIndex returns the index a property key stands for, if it is the canonical form of one (e.g., "1" but not
+// "01").
+func arrayIndex(key string) (int, bool) {
+	index, err := strconv.Atoi(key)
+	return index, err == nil && index >= 0 && strconv.Itoa(index) == key
+}
+
+// Function is a function defined in JavaScript, it is also an object with its own properties.
+type Function struct {
+	*Object
+	Name       string
+	Parameters []*parser.Identifier
+	Body       parser.Node // A *parser.BlockStatement, or an expression for concise arrow functions
+	Node       parser.Node // The function declaration or expression, or the *parser.Program of a module
+	Generator  bool
+	Async      bool
+	Arrow      bool
+	Method     bool // Written as a method of an object literal (e.g., { m() {} })
+	Strict     bool
+	Closure    *Environment // The scope the function was created in
+	this       Value        // The this value arrow functions capture when they are created
+	newTarget  Value        // The new.target value arrow functions capture when they are created
+}
+
+// newFunction completes a function created in the current scope, adding its prototype property.
+// For ordinary functions it is the prototype of constructed objects, for generators it is the prototype
+// of generator objects. Arrow and async functions and methods have none.
+func (i *Interpreter) newFunction(function *Function) *Function {
+	function.Object = NewObject(nil)
+	function.Closure = i.scope
+	if function.Arrow {
+		function.this, function.newTarget = i.this, i.newTarget
+	}
+	if function.Generator && function.Async {
+		function.SetHidden("prototype", NewObject(i.asyncGeneratorPrototype))
+	} else if function.Generator {
+		function.SetHidden("prototype", NewObject(i.generatorPrototype))
+	} else if function.isConstructor() {
+		prototype := NewObject(nil)
+		prototype.SetHidden("constructor", function)
+		function.SetHidden("prototype", prototype)
+	}
+	return function
+}
+
+// isConstructor reports whether the function can be used with new: generators, async and arrow functions and
+// methods cannot.
+func (f *Function) isConstructor() bool {
+	return !f.Generator && !f.Async && !f.Arrow && !f.Method
+}
+
+func (f *Function) String() string {
+	if f.Name == "" {
+		return "[Function (anonymous)]"
+	}
+	kind := "Function"
+	if f.Generator {
+		kind = "GeneratorFunction"
+	}
+	if f.Async {
+		kind = "Async" + kind
+	}
+	return fmt.Sprintf("[%s: %s]", kind, f.Name)
+}
+
+// asObject returns the object holding the properties of a value, if it has one.
+func asObject(value Value) (*Object, bool) {
+	switch value := value.(type) {
+	case *Object:
+		return value, true
+	case *Function:
+		return value.Object, true
+	case *BuiltinConstructor:
+		return value.Object, true
+	default:
+		return nil, false
+	}
+}
+
+// forInKeys returns the property names a for...in loop visits: the string keys of an object and then those of its
+// prototypes, leaving out hidden ones and those an earlier object of the chain has. Arrays and strings have their
+// indexes.
+func forInKeys(value Value) []string {
+	if value, ok := value.(String); ok {
+		return indexKeys(len(value))
+	}
+	object, ok := asObject(value)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for ; object != nil; object = object.Prototype {
+		for _, key := range object.Keys() {
+			if seen[key] || isSymbolKey(key) {
+				continue
+			}
+			seen[key] = true
+			if !object.hidden[key] {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func indexKeys(length int) []string {
+	keys := make([]string, length)
+	for idx := range keys {
+		keys[idx] = strconv.Itoa(idx)
+	}
+	return keys
+}
+
+// hasProperty implements the in operator: whether an object or one of its prototypes has a property.
+func hasProperty(key Value, value Value) bool {
+	object, ok := asObject(value)
+	if !ok {
+		throwError("TypeError", "Cannot use 'in' operator to search for '%v' in %v", key, formatValue(value, 0))
+	}
+	_, found := object.Get(propertyKey(key))
+	return found
+}
+
+// instanceOf implements the instanceof operator. A Symbol.hasInstance method of the constructor decides,
+// otherwise the value is an instance when the prototype property of the constructor is on its prototype chain.
+func (i *Interpreter) instanceOf(value Value, constructor Value) bool {
+	target, isObject := asObject(constructor)
+	if !isObject && !isCallable(constructor) {
+		throwError("TypeError", "Right-hand side of 'instanceof' is not an object")
+	}
+	if isObject {
+		if method, _ := target.Get(SymbolHasInstance.key); !isNullish(method) {
+			if !isCallable(method) {
+				throwError("TypeError", "%v is not a function", formatValue(method, 0))
+			}
+			return isTruthy(i.call(method, constructor, value))
+		}
+	}
+	if !isCallable(constructor) {
+		throwError("TypeError", "Right-hand side of 'instanceof' is not callable")
+	}
+
+	object, ok := asObject(value)
+	if !ok {
+		return false
+	}
+	var prototypeValue Value = Undefined
+	if isObject {
+		prototypeValue, _ = target.Get("prototype")
+	}
+	prototype, ok := asObject(prototypeValue)
+	if !ok {
+		throwError("TypeError", "Function has non-object prototype '%v' in instanceof check",
+			formatValue(prototypeValue, 0))
+	}
+	for object = object.Prototype; object != nil; object = object.Prototype {
+		if object == prototype {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,344 @@
+package interpreter
+
+import (
+	"fmt"
+	"runtime"
+)
+
+type promiseState int
+
+const (
+	pending promiseState = iota
+	fulfilled
+	rejected
+)
+
+// promise is the state of a Promise object, the callbacks waiting on it run as jobs once it settles.
+type promise struct {
+	state     promiseState
+	value     Value
+	resolved  bool // Whether the promise was resolved, possibly to another promise that is still pending
+	handled   bool // Whether anything waits on the promise, unhandled rejections are reported
+	reactions []promiseReaction
+}
+
+type promiseReaction struct {
+	onFulfilled func(value Value)
+	onRejected  func(reason Value)
+}
+
+// addPromise creates the Promise constructor and the prototype shared by all promises.
+func (i *Interpreter) addPromise() {
+	i.promisePrototype = NewObject(nil)
+	i.promisePrototype.SetHidden("then", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		return i.promiseThen(this, argument(args, 0), argument(args, 1))
+	}))
+	i.promisePrototype.SetHidden("catch", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		return i.promiseThen(this, Undefined, argument(args, 0))
+	}))
+	i.promisePrototype.SetHidden("finally", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		onFinally := argument(args, 0)
+		if !isCallable(onFinally) {
+			return i.promiseThen(this, Undefined, Undefined)
+		}
+		// The callback receives no argument and the settled value passes through it
+		onFulfilled := NewBuiltinFunction(func(_ Value, args ...Value) Value {
+			i.call(onFinally, Undefined)
+			return argument(args, 0)
+		})
+		onRejected := NewBuiltinFunction(func(_ Value, args ...Value) Value {
+			i.call(onFinally, Undefined)
+			panic(&Exception{Value: argument(args, 0)})
+		})
+		return i.promiseThen(this, onFulfilled, onRejected)
+	}))
+
+	constructor := &BuiltinConstructor{Object: NewObject(nil), Name: "Promise"}
+	constructor.Construct = func(args ...Value) Value {
+		executor := argument(args, 0)
+		if !isCallable(executor) {
+			fmt.Printf("Error: Promise resolver %v is not a function\n", executor)
+			return Undefined
+		}
+		object, p := i.newPromise()
+		resolve, reject := i.resolvingFunctions(p)
+		// An exception thrown by the executor rejects the promise
+		if _, exception := i.callProtected(executor, Undefined, resolve, reject); exception != nil {
+			reject.Call(Undefined, exception.Value)
+		}
+		return object
+	}
+	constructor.Set("prototype", i.promisePrototype)
+	constructor.Set("resolve", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		value := argument(args, 0)
+		if promiseOf(value) != nil {
+			return value
+		}
+		object, p := i.newPromise()
+		i.resolvePromise(p, value)
+		return object
+	}))
+	constructor.Set("reject", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		object, p := i.newPromise()
+		i.rejectPromise(p, argument(args, 0))
+		return object
+	}))
+	i.promisePrototype.SetHidden("constructor", constructor)
+	i.Env["Promise"] = constructor
+}
+
+func (i *Interpreter) newPromise() (*Object, *promise) {
+	p := &promise{}
+	object := NewObject(i.promisePrototype)
+	object.internal = p
+	return object, p
+}
+
+// promiseOf returns the promise backing a Promise object, or nil for any other value.
+func promiseOf(value Value) *promise {
+	if object, ok := value.(*Object); ok {
+		if p, ok := object.internal.(*promise); ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolvingFunctions creates the resolve and reject functions handed to a promise executor,
+// only the first call to either of them has an effect.
+func (i *Interpreter) resolvingFunctions(p *promise) (*BuiltinFunction, *BuiltinFunction) {
+	alreadyResolved := false
+	resolve := NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if !alreadyResolved {
+			alreadyResolved = true
+			i.resolvePromise(p, argument(args, 0))
+		}
+		return Undefined
+	})
+	reject := NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if !alreadyResolved {
+			alreadyResolved = true
+			i.rejectPromise(p, argument(args, 0))
+		}
+		return Undefined
+	})
+	return resolve, reject
+}
+
+// resolvePromise resolves a promise with a value: thenables are followed, anything else fulfills it.
+func (i *Interpreter) resolvePromise(p *promise, value Value) {
+	if p.resolved {
+		return
+	}
+	p.resolved = true
+
+	if promiseOf(value) == p {
+		i.settlePromise(p, rejected, String("TypeError: Chaining cycle detected for promise"))
+		return
+	}
+
+	object, ok := asObject(value)
+	if !ok {
+		i.settlePromise(p, fulfilled, value)
+		return
+	}
+	then, _ := object.Get("then")
+	if !isCallable(then) {
+		i.settlePromise(p, fulfilled, value)
+		return
+	}
+
+	// The thenable is followed in a job of its own, through a promise settling once it calls back
+	i.enqueueJob(func() {
+		follow := &promise{}
+		resolve, reject := i.resolvingFunctions(follow)
+		i.then(follow, func(value Value) {
+			i.settlePromise(p, fulfilled, value)
+		}, func(reason Value) {
+			i.settlePromise(p, rejected, reason)
+		})
+		if _, exception := i.callProtected(then, value, resolve, reject); exception != nil {
+			reject.Call(Undefined, exception.Value)
+		}
+	})
+}
+
+func (i *Interpreter) rejectPromise(p *promise, reason Value) {
+	if p.resolved {
+		return
+	}
+	p.resolved = true
+	i.settlePromise(p, rejected, reason)
+}
+
+// settlePromise fulfills or rejects a promise and queues the callbacks waiting on it.
+func (i *Interpreter) settlePromise(p *promise, state promiseState, value Value) {
+	if p.state != pending {
+		return
+	}
+	p.state, p.value = state, value
+	for _, reaction := range p.reactions {
+		i.enqueueReaction(p, reaction)
+	}
+	p.reactions = nil
+	if state == rejected && !p.handled {
+		i.rejections = append(i.rejections, p)
+	}
+}
+
+// then calls one of the callbacks in a job once the promise settles.
+func (i *Interpreter) then(p *promise, onFulfilled func(value Value), onRejected func(reason Value)) {
+	p.handled = true
+	reaction := promiseReaction{onFulfilled: onFulfilled, onRejected: onRejected}
+	if p.state == pending {
+		p.reactions = append(p.reactions, reaction)
+		return
+	}
+	i.enqueueReaction(p, reaction)
+}
+
+func (i *Interpreter) enqueueReaction(p *promise, reaction promiseReaction) {
+	i.enqueueJob(func() {
+		if p.state == fulfilled {
+			reaction.onFulfilled(p.value)
+		} else {
+			reaction.onRejected(p.value)
+		}
+	})
+}
+
+// promiseThen implements Promise.prototype.then: the returned promise is resolved with the result
+// of the callback, or rejected with the exception it throws. Missing callbacks pass the value through.
+func (i *Interpreter) promiseThen(this Value, onFulfilled Value, onRejected Value) Value {
+	p := promiseOf(this)
+	if p == nil {
+		fmt.Printf("Error: Promise.prototype.then called on incompatible receiver %v\n", this)
+		return Undefined
+	}
+
+	object, derived := i.newPromise()
+	settle := func(callback Value, value Value, passThrough func(*promise, Value)) {
+		if !isCallable(callback) {
+			passThrough(derived, value)
+			return
+		}
+		result, exception := i.callProtected(callback, Undefined, value)
+		if exception != nil {
+			i.rejectPromise(derived, exception.Value)
+			return
+		}
+		i.resolvePromise(derived, result)
+	}
+	i.then(p, func(value Value) {
+		settle(onFulfilled, value, i.resolvePromise)
+	}, func(reason Value) {
+		settle(onRejected, reason, i.rejectPromise)
+	})
+	return object
+}
+
+// promiseResolve returns the promise behind a value, wrapping values that are not promises.
+func (i *Interpreter) promiseResolve(value Value) *promise {
+	if p := promiseOf(value); p != nil {
+		return p
+	}
+	_, p := i.newPromise()
+	i.resolvePromise(p, value)
+	return p
+}
+
+// asyncFunction is the running call of an async function, held by the reactions of the promise it awaits.
+type asyncFunction struct {
+	coroutine *generator
+}
+
+// startAsync calls an async function: the body runs as a coroutine until its first await, and the
+// returned promise settles with its result. Each await resumes the body in a job once the value settles.
+func (i *Interpreter) startAsync(function *Function, f frame) *Object {
+	object, p := i.newPromise()
+	call := &asyncFunction{coroutine: i.newCoroutine(function, f)}
+	// A body awaiting a promise nothing can settle anymore is released
+	call.coroutine.releaseWith(call)
+
+	var step func(r resumption)
+	step = func(r resumption) {
+		var value Value
+		var done bool
+		exception := i.protect(func() {
+			value, done = call.coroutine.step(r)
+		})
+		runtime.KeepAlive(call)
+		switch {
+		case exception != nil:
+			i.rejectPromise(p, exception.Value)
+		case done:
+			i.resolvePromise(p, value)
+		default:
+			i.then(i.promiseResolve(value), func(value Value) {
+				step(resumption{mode: resumeNext, value: value})
+			}, func(reason Value) {
+				step(resumption{mode: resumeThrow, value: reason})
+			})
+		}
+	}
+	step(resumption{mode: resumeNext, value: Undefined})
+
+	return object
+}
+
+// callProtected calls a function, returning the exception it throws instead of unwinding the caller.
+func (i *Interpreter) callProtected(function Value, this Value, args ...Value) (Value,
+	*Exception) {
+	var result Value
+	exception := i.protect(func() {
+		result = i.call(function, this, args...)
+	})
+	return result, exception
+}
+
+// protect runs code and recovers the exception it throws, restoring the frame it was called in.
+func (i *Interpreter) protect(run func()) (exception *Exception) {
+	caller := i.saveFrame()
+	defer func() {
+		if r := recover(); r != nil {
+			thrown, ok := r.(*Exception)
+			if !ok {
+				panic(r)
+			}
+			i.restoreFrame(caller)
+			i.completeError(thrown.Value)
+			exception = thrown
+		}
+	}()
+	run()
+	return nil
+}
+
+func (i *Interpreter) enqueueJob(job func()) {
+	i.jobs = append(i.jobs, job)
+}
+
+// runJobs runs the queued jobs, including the ones they queue, then reports the rejections nothing handled.
+func (i *Interpreter) runJobs() {
+	for len(i.jobs) > 0 {
+		job := i.jobs[0]
+		i.jobs = i.jobs[1:]
+		i.runUncaught(job)
+	}
+	i.releaseAbandoned()
+
+	for _, p := range i.rejections {
+		if !p.handled {
+			fmt.Printf("Uncaught (in promise) %v\n", p.value)
+		}
+	}
+	i.rejections = nil
+}
+
+// argument returns the argument at an index, or undefined if it was not passed.
+func argument(args []Value, index int) Value {
+	if index < len(args) {
+		return args[index]
+	}
+	return Undefined
+}
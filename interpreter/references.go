@@ -0,0 +1,169 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/parser"
+	"gojo/printer"
+)
+
+// reference is a resolved assignment target: either a variable or a property of a value.
+// Resolving it once lets compound assignments evaluate the target's sub-expressions only once.
+type reference struct {
+	name  string // The variable name, when base is nil
+	base  Value  // The object or string holding the property
+	key   Value  // The property key, e.g. a String or a Number index
+	label string // Source text of the target, used in error messages
+}
+
+func (i *Interpreter) evalReference(expr parser.Expression) (*reference, bool) {
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		return &reference{name: expr.Value, label: expr.Value}, true
+	case *parser.MemberExpression:
+		base := i.evalExpression(expr.Object)
+		var key Value
+		if expr.Computed {
+			key = i.evalExpression(expr.Property)
+		} else {
+			key = String(expr.Property.(*parser.Identifier).Value)
+		}
+		if isNullish(base) {
+			throwError("TypeError", "Cannot access property '%v' of %s", key, typeOfNullish(base))
+		}
+		return &reference{base: base, key: key, label: targetLabel(expr)}, true
+	default:
+		fmt.Printf("Error: Invalid reference '%s'\n", expr.String())
+		return nil, false
+	}
+}
+
+func (i *Interpreter) getValue(ref *reference) (Value, bool) {
+	if ref.base == nil {
+		value, ok := i.scope.Get(ref.name)
+		if !ok {
+			throwError("ReferenceError", "%s is not defined", ref.name)
+		}
+		return value, true
+	}
+
+	if object, ok := asObject(ref.base); ok {
+		// Missing properties are undefined
+		value, _ := object.Get(propertyKey(ref.key))
+		return value, true
+	}
+
+	switch base := ref.base.(type) {
+	case String:
+		if ref.key == String("length") {
+			return Number(len(base)), true
+		}
+		if index, ok := arrayIndex(propertyKey(ref.key)); ok {
+			if index >= len(base) {
+				return Undefined, true
+			}
+			return base[index : index+1], true
+		}
+		value, _ := i.stringPrototype.Get(propertyKey(ref.key))
+		return value, true
+	default:
+		// Primitives without properties
+		return Undefined, true
+	}
+}
+
+func (i *Interpreter) putValue(ref *reference, value Value) bool {
+	if ref.base == nil {
+		scope := i.scope.resolve(ref.name)
+		if scope == nil {
+			// Sloppy mode code creates a global variable, strict mode code throws
+			if i.strict {
+				throwError("ReferenceError", "%s is not defined", ref.name)
+			}
+			i.global.Set(ref.name, value)
+			return true
+		}
+		if scope.outer == nil && i.readOnly[ref.name] {
+			return i.assignReadOnly(ref.name)
+		}
+		if scope.constants[ref.name] {
+			throwError("TypeError", "Assignment to constant variable.")
+		}
+		scope.set(ref.name, value)
+		return true
+	}
+
+	if object, ok := asObject(ref.base); ok {
+		// Frozen objects ignore assignments, strict mode code throws
+		if object.frozen || object == i.global && i.readOnly[propertyKey(ref.key)] {
+			return i.assignReadOnly(ref.key)
+		}
+		if _, isArray := object.internal.(arrayObject); isArray {
+			setArrayProperty(object, propertyKey(ref.key), value)
+		} else {
+			object.Set(propertyKey(ref.key), value)
+		}
+		return true
+	}
+
+	// Primitives have no properties of their own, sloppy mode code loses the assignment, strict mode code throws
+	if i.strict {
+		throwError("TypeError", "Cannot create property '%v' on %s '%s'", ref.key, ref.base.Kind(), toString(ref.base))
+	}
+	return true
+}
+
+// assignReadOnly ignores an assignment to a read-only property, strict mode code throws.
+func (i *Interpreter) assignReadOnly(key interface{}) bool {
+	if i.strict {
+		throwError("TypeError", "Cannot assign to read only property '%v' of object", key)
+	}
+	return true
+}
+
+func (i *Interpreter) deleteProperty(ref *reference) bool {
+	if object, ok := asObject(ref.base); ok {
+		key := propertyKey(ref.key)
+		if _, own := object.Properties[key]; own && object.frozen {
+			if i.strict {
+				throwError("TypeError", "Cannot delete property '%v' of object", ref.key)
+			}
+			return false
+		}
+		object.Delete(key)
+	}
+	return true
+}
+
+// propertyKey converts a computed key to the string used to index objects.
+func propertyKey(key Value) string {
+	if symbol, ok := key.(*Symbol); ok {
+		return symbol.key
+	}
+	return toString(key)
+}
+
+// literalKey converts the key of a non-computed object literal property to a property name.
+func literalKey(key parser.Expression) string {
+	switch key := key.(type) {
+	case *parser.Identifier:
+		return key.Value
+	case *parser.StringLiteral:
+		return key.Value
+	case *parser.IntegerLiteral:
+		// Numeric keys are canonicalized, e.g. { 0x10: a } defines "16"
+		return formatNumber(key.Value)
+	case *parser.BigIntLiteral:
+		return key.Value.String()
+	default:
+		return key.String()
+	}
+}
+
+// targetLabel renders an assignment target the way it is written in the source (e.g., arr[i++].name).
+func targetLabel(expr parser.Expression) string {
+	return printer.Print(expr)
+}
+
+func typeOfNullish(value Value) string {
+	return value.Kind().String()
+}
@@ -0,0 +1,454 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/regex"
+	"strings"
+	"unicode/utf8"
+)
+
+// regExp is the state of a RegExp object, its lastIndex is an ordinary property.
+type regExp struct {
+	re *regex.Regexp
+}
+
+// addRegExp creates the RegExp constructor and the prototype shared by all regular expressions. The
+// String methods matching patterns call the methods keyed by Symbol.match, Symbol.replace, ...
+func (i *Interpreter) addRegExp() {
+	i.regExpPrototype = NewObject(nil)
+	i.regExpPrototype.SetHidden("exec", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		return i.regExpExec(thisRegExp(this, "exec"), toString(argument(args, 0)))
+	}))
+	i.regExpPrototype.SetHidden("test", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		return Boolean(i.regExpExec(thisRegExp(this, "test"), toString(argument(args, 0))) != Null)
+	}))
+	i.regExpPrototype.SetHidden("toString", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		object := thisRegExp(this, "toString")
+		source, _ := object.Get("source")
+		flags, _ := object.Get("flags")
+		return String(fmt.Sprintf("/%s/%s", toString(source), toString(flags)))
+	}))
+	i.regExpPrototype.SetHidden(propertyKey(SymbolMatch), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return i.regExpMatch(thisRegExp(this, "[Symbol.match]"), toString(argument(args, 0)))
+	}))
+	i.regExpPrototype.SetHidden(propertyKey(SymbolMatchAll), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return i.regExpMatchAll(thisRegExp(this, "[Symbol.matchAll]"), toString(argument(args, 0)))
+	}))
+	i.regExpPrototype.SetHidden(propertyKey(SymbolReplace), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return i.regExpReplace(thisRegExp(this, "[Symbol.replace]"), toString(argument(args, 0)),
+			argument(args, 1))
+	}))
+	i.regExpPrototype.SetHidden(propertyKey(SymbolSplit), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return i.regExpSplit(thisRegExp(this, "[Symbol.split]"), toString(argument(args, 0)), argument(args, 1))
+	}))
+
+	constructor := &BuiltinConstructor{Object: NewObject(nil), Name: "RegExp"}
+	constructor.Construct = func(args ...Value) Value {
+		pattern, flags := argument(args, 0), argument(args, 1)
+		// A regular expression is copied, with its own flags unless others are given
+		if object, ok := pattern.(*Object); ok {
+			if r, ok := object.internal.(*regExp); ok {
+				if flags == Undefined {
+					flags = String(r.re.Flags.String())
+				}
+				return i.newRegExp(r.re.Source, toString(flags))
+			}
+		}
+		source := ""
+		if pattern != Undefined {
+			source = escapeSource(toString(pattern))
+		}
+		if flags == Undefined {
+			flags = String("")
+		}
+		return i.newRegExp(source, toString(flags))
+	}
+	constructor.Call = func(this Value, args ...Value) Value {
+		return constructor.Construct(args...)
+	}
+	constructor.Set("prototype", i.regExpPrototype)
+	i.regExpPrototype.SetHidden("constructor", constructor)
+	i.Env["RegExp"] = constructor
+}
+
+// newRegExp creates a RegExp object from the source of a pattern, as written between the slashes of a
+// literal. An invalid pattern throws a SyntaxError.
+func (i *Interpreter) newRegExp(source string, flags string) *Object {
+	if _, err := regex.ParseFlags(flags); err != nil {
+		throwError("SyntaxError", "Invalid flags supplied to RegExp constructor '%s'", flags)
+	}
+	re, err := regex.Compile(source, flags)
+	if err != nil {
+		throwError("SyntaxError", "Invalid regular expression: /%s/%s: %s", source, flags, err)
+	}
+
+	object := NewObject(i.regExpPrototype)
+	object.internal = &regExp{re: re}
+	object.Set("lastIndex", Number(0))
+	object.Set("source", String(source))
+	object.Set("flags", String(re.Flags.String()))
+	object.Set("hasIndices", Boolean(re.Flags.HasIndices))
+	object.Set("global", Boolean(re.Flags.Global))
+	object.Set("ignoreCase", Boolean(re.Flags.IgnoreCase))
+	object.Set("multiline", Boolean(re.Flags.Multiline))
+	object.Set("dotAll", Boolean(re.Flags.DotAll))
+	object.Set("unicode", Boolean(re.Flags.Unicode))
+	object.Set("unicodeSets", Boolean(re.Flags.UnicodeSets))
+	object.Set("sticky", Boolean(re.Flags.Sticky))
+	return object
+}
+
+// escapeSource escapes a pattern given as a string so it can be written between slashes, e.g., the source
+// of new RegExp("a/b") is a\/b. The empty pattern is written (?:) so it does not start a comment.
+func escapeSource(pattern string) string {
+	if pattern == "" {
+		return "(?:)"
+	}
+	var out strings.Builder
+	inClass := false
+	for idx := 0; idx < len(pattern); idx++ {
+		c := pattern[idx]
+		switch {
+		case c == '\\' && idx+1 < len(pattern):
+			out.WriteByte(c)
+			idx++
+			c = pattern[idx]
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			out.WriteByte('\\')
+		case c == '\n':
+			out.WriteString(`\n`)
+			continue
+		case c == '\r':
+			out.WriteString(`\r`)
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+func thisRegExp(this Value, method string) *Object {
+	if object, ok := this.(*Object); ok {
+		if _, ok := object.internal.(*regExp); ok {
+			return object
+		}
+	}
+	throwError("TypeError", "RegExp.prototype.%s called on incompatible receiver %v", method, this)
+	return nil
+}
+
+// regExpExec runs a regular expression on a string and returns the match, or null. Global and sticky
+// expressions start at their lastIndex and move it past the match.
+func (i *Interpreter) regExpExec(object *Object, input string) Value {
+	r := object.internal.(*regExp)
+	flags := r.re.Flags
+
+	lastIndex := 0
+	if flags.Global || flags.Sticky {
+		value, _ := object.Get("lastIndex")
+		lastIndex = toIndex(value)
+	}
+
+	var captures []int
+	if lastIndex <= len(input) {
+		captures = r.re.FindAt(input, lastIndex)
+	}
+	if captures == nil {
+		if flags.Global || flags.Sticky {
+			object.Set("lastIndex", Number(0))
+		}
+		return Null
+	}
+	if flags.Global || flags.Sticky {
+		object.Set("lastIndex", Number(captures[1]))
+	}
+	return i.newMatchArray(r.re, input, captures)
+}
+
+// newMatchArray creates the array of a match: the matched text and the captures, with the position of
+// the match, the input and the named groups. With the d flag, indices holds the [start, end] pairs.
+func (i *Interpreter) newMatchArray(re *regex.Regexp, input string, captures []int) *Object {
+	var elements, indices []Value
+	for group := 0; group <= re.NumGroups(); group++ {
+		start, end := captures[2*group], captures[2*group+1]
+		if start < 0 {
+			elements = append(elements, Undefined)
+			indices = append(indices, Undefined)
+			continue
+		}
+		elements = append(elements, String(input[start:end]))
+		indices = append(indices, newArrayObject([]Value{Number(start), Number(end)}))
+	}
+	result := newArrayObject(elements)
+	result.Set("index", Number(captures[0]))
+	result.Set("input", String(input))
+
+	var groups Value = Undefined
+	if re.HasNamedGroups() {
+		named := NewObject(nil)
+		for group, name := range re.GroupNames() {
+			if name != "" {
+				named.Set(name, elements[group])
+			}
+		}
+		groups = named
+	}
+	result.Set("groups", groups)
+
+	if re.Flags.HasIndices {
+		result.Set("indices", newArrayObject(indices))
+	}
+	return result
+}
+
+// regExpMatch implements String.prototype.match: the first match, or with the g flag the array of all the
+// matched strings. Both are null if nothing matches.
+func (i *Interpreter) regExpMatch(object *Object, input string) Value {
+	if !object.internal.(*regExp).re.Flags.Global {
+		return i.regExpExec(object, input)
+	}
+
+	object.Set("lastIndex", Number(0))
+	var matches []Value
+	for {
+		match, ok := i.regExpExec(object, input).(*Object)
+		if !ok {
+			break
+		}
+		matched := toString(arrayElements(match)[0])
+		matches = append(matches, String(matched))
+		if matched == "" {
+			i.advanceLastIndex(object, input)
+		}
+	}
+	if matches == nil {
+		return Null
+	}
+	return newArrayObject(matches)
+}
+
+// advanceLastIndex moves the lastIndex past an empty match, by a whole character, so matching goes on.
+func (i *Interpreter) advanceLastIndex(object *Object, input string) {
+	value, _ := object.Get("lastIndex")
+	object.Set("lastIndex", Number(advanceIndex(input, toIndex(value))))
+}
+
+func advanceIndex(input string, index int) int {
+	if index >= len(input) {
+		return index + 1
+	}
+	_, size := utf8.DecodeRuneInString(input[index:])
+	return index + size
+}
+
+// regExpMatchAll implements String.prototype.matchAll: an iterator over the matches of a copy of the
+// regular expression, so iterating does not move the lastIndex of the original.
+func (i *Interpreter) regExpMatchAll(object *Object, input string) Value {
+	r := object.internal.(*regExp)
+	source, _ := object.Get("source")
+	matcher := i.newRegExp(toString(source), r.re.Flags.String())
+	lastIndex, _ := object.Get("lastIndex")
+	matcher.Set("lastIndex", Number(toIndex(lastIndex)))
+
+	done := false
+	iterator := NewObject(nil)
+	iterator.Set("next", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if done {
+			return iteratorResult(Undefined, true)
+		}
+		match, ok := i.regExpExec(matcher, input).(*Object)
+		if !ok {
+			done = true
+			return iteratorResult(Undefined, true)
+		}
+		if !r.re.Flags.Global {
+			done = true
+		} else if toString(arrayElements(match)[0]) == "" {
+			i.advanceLastIndex(matcher, input)
+		}
+		return iteratorResult(match, false)
+	}))
+	iterator.Set(propertyKey(SymbolIterator), NewBuiltinFunction(func(this Value,
+		args ...Value) Value {
+		return this
+	}))
+	return iterator
+}
+
+// regExpReplace implements String.prototype.replace, for every match with the g flag. The replacement
+// is either a function called with the match, or a string where $&, $1, $<name>, ... stand for parts of
+// the match.
+func (i *Interpreter) regExpReplace(object *Object, input string, replaceValue Value) Value {
+	r := object.internal.(*regExp)
+	replacement := ""
+	if !isCallable(replaceValue) {
+		replacement = toString(replaceValue)
+	}
+
+	var matches []*Object
+	if r.re.Flags.Global {
+		object.Set("lastIndex", Number(0))
+	}
+	for {
+		match, ok := i.regExpExec(object, input).(*Object)
+		if !ok {
+			break
+		}
+		matches = append(matches, match)
+		if !r.re.Flags.Global {
+			break
+		}
+		if toString(arrayElements(match)[0]) == "" {
+			i.advanceLastIndex(object, input)
+		}
+	}
+
+	var out strings.Builder
+	nextPosition := 0
+	for _, match := range matches {
+		elements := arrayElements(match)
+		matched := toString(elements[0])
+		indexValue, _ := match.Get("index")
+		position := toIndex(indexValue)
+		groups, _ := match.Get("groups")
+
+		var replaced string
+		if isCallable(replaceValue) {
+			args := append([]Value{}, elements...)
+			args = append(args, Number(position), String(input))
+			if groups != Undefined {
+				args = append(args, groups)
+			}
+			replaced = toString(i.call(replaceValue, Undefined, args...))
+		} else {
+			replaced = substitute(replacement, matched, input, position, elements[1:], groups)
+		}
+
+		if position >= nextPosition {
+			out.WriteString(input[nextPosition:position])
+			out.WriteString(replaced)
+			nextPosition = position + len(matched)
+		}
+	}
+	out.WriteString(input[nextPosition:])
+	return String(out.String())
+}
+
+// substitute expands the $ patterns of a replacement string: $$, $&, $`, $', $n, $nn and $<name>.
+func substitute(replacement string, matched string, input string, position int, captures []Value,
+	groups Value) string {
+	var out strings.Builder
+	for idx := 0; idx < len(replacement); idx++ {
+		c := replacement[idx]
+		if c != '$' || idx+1 == len(replacement) {
+			out.WriteByte(c)
+			continue
+		}
+
+		switch next := replacement[idx+1]; {
+		case next == '$':
+			out.WriteByte('$')
+			idx++
+		case next == '&':
+			out.WriteString(matched)
+			idx++
+		case next == '`':
+			out.WriteString(input[:position])
+			idx++
+		case next == '\'':
+			if end := position + len(matched); end < len(input) {
+				out.WriteString(input[end:])
+			}
+			idx++
+		case next >= '0' && next <= '9':
+			// Two digits are a group number if there is such a group, otherwise one digit may be
+			digits, number := 1, int(next-'0')
+			if idx+2 < len(replacement) && replacement[idx+2] >= '0' && replacement[idx+2] <= '9' {
+				if twoDigits := number*10 + int(replacement[idx+2]-'0'); twoDigits >= 1 &&
+					twoDigits <= len(captures) {
+					digits, number = 2, twoDigits
+				}
+			}
+			if number < 1 || number > len(captures) {
+				out.WriteByte(c)
+				continue
+			}
+			if capture := captures[number-1]; capture != Undefined {
+				out.WriteString(toString(capture))
+			}
+			idx += digits
+		case next == '<':
+			named, ok := groups.(*Object)
+			end := strings.IndexByte(replacement[idx+2:], '>')
+			if !ok || end < 0 {
+				out.WriteByte(c)
+				continue
+			}
+			if capture, _ := named.Get(replacement[idx+2 : idx+2+end]); capture != Undefined {
+				out.WriteString(toString(capture))
+			}
+			idx += end + 2
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// regExpSplit implements String.prototype.split with a regular expression separator: the captures of
+// each separator are part of the result, and a separator never matches empty at the end of the previous one.
+func (i *Interpreter) regExpSplit(object *Object, input string, limit Value) Value {
+	re := object.internal.(*regExp).re
+	max := -1
+	if limit != Undefined {
+		max = toIndex(limit)
+	}
+	parts := []Value{}
+	if max == 0 {
+		return newArrayObject(parts)
+	}
+	if input == "" {
+		if re.MatchAt(input, 0) != nil {
+			return newArrayObject(parts)
+		}
+		return newArrayObject([]Value{String(input)})
+	}
+
+	// Appending stops once the limit is reached
+	add := func(part Value) bool {
+		parts = append(parts, part)
+		return max != -1 && len(parts) >= max
+	}
+	previous := 0
+	for position := 0; position < len(input); {
+		captures := re.MatchAt(input, position)
+		if captures == nil || captures[1] == previous || captures[1] > len(input) {
+			position = advanceIndex(input, position)
+			continue
+		}
+		if add(String(input[previous:position])) {
+			return newArrayObject(parts)
+		}
+		previous = captures[1]
+		for group := 1; group <= re.NumGroups(); group++ {
+			var capture Value = Undefined
+			if start := captures[2*group]; start >= 0 {
+				capture = String(input[start:captures[2*group+1]])
+			}
+			if add(capture) {
+				return newArrayObject(parts)
+			}
+		}
+		position = previous
+	}
+	parts = append(parts, String(input[previous:]))
+	return newArrayObject(parts)
+}
@@ -0,0 +1,130 @@
+package interpreter
+
+import "strings"
+
+// addString creates the String constructor and the prototype looked up for properties of strings. Its
+// pattern methods call the Symbol.match, Symbol.replace, ... methods of their argument, e.g., a RegExp,
+// and otherwise search for the argument as a string.
+func (i *Interpreter) addString() {
+	i.stringPrototype = NewObject(nil)
+	i.stringPrototype.Set("match", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if method := i.getMethod(argument(args, 0), SymbolMatch); method != Undefined {
+			return i.call(method, argument(args, 0), String(toString(this)))
+		}
+		return i.regExpMatch(i.newRegExp(patternSource(argument(args, 0)), ""), toString(this))
+	}))
+	i.stringPrototype.Set("matchAll", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if object, ok := argument(args, 0).(*Object); ok {
+			if r, ok := object.internal.(*regExp); ok && !r.re.Flags.Global {
+				throwError("TypeError", "String.prototype.matchAll called with a non-global RegExp argument")
+			}
+		}
+		if method := i.getMethod(argument(args, 0), SymbolMatchAll); method != Undefined {
+			return i.call(method, argument(args, 0), String(toString(this)))
+		}
+		return i.regExpMatchAll(i.newRegExp(patternSource(argument(args, 0)), "g"), toString(this))
+	}))
+	i.stringPrototype.Set("replace", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if method := i.getMethod(argument(args, 0), SymbolReplace); method != Undefined {
+			return i.call(method, argument(args, 0), String(toString(this)), argument(args, 1))
+		}
+		return String(i.stringReplace(toString(this), toString(argument(args, 0)), argument(args, 1)))
+	}))
+	i.stringPrototype.Set("split", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		if method := i.getMethod(argument(args, 0), SymbolSplit); method != Undefined {
+			return i.call(method, argument(args, 0), String(toString(this)), argument(args, 1))
+		}
+		return stringSplit(toString(this), argument(args, 0), argument(args, 1))
+	}))
+
+	constructor := &BuiltinConstructor{Object: NewObject(nil), Name: "String"}
+	constructor.Call = func(this Value, args ...Value) Value {
+		if len(args) == 0 {
+			return String("")
+		}
+		return String(toString(args[0]))
+	}
+	constructor.Set("raw", NewBuiltinFunction(func(this Value, args ...Value) Value {
+		// Joins the raw chunks of a template with the substitutions, e.g., String.raw`\n${1}` is \n1
+		var raw Value = Undefined
+		if object, ok := asObject(argument(args, 0)); ok {
+			raw, _ = object.Get("raw")
+		}
+		chunks, ok := listElements(raw)
+		if !ok {
+			throwError("TypeError", "Cannot convert %v to object", raw)
+		}
+		var out strings.Builder
+		for idx, chunk := range chunks {
+			out.WriteString(toString(chunk))
+			if idx+1 < len(chunks) && idx+1 < len(args) {
+				out.WriteString(toString(args[idx+1]))
+			}
+		}
+		return String(out.String())
+	}))
+	constructor.Set("prototype", i.stringPrototype)
+	i.stringPrototype.Set("constructor", constructor)
+	i.Env["String"] = constructor
+}
+
+// listElements returns the elements of an array, or of an object with a length.
+func listElements(value Value) ([]Value, bool) {
+	object, ok := asObject(value)
+	if !ok {
+		return nil, false
+	}
+	return arrayElements(object), true
+}
+
+// patternSource returns the source of a regular expression matching a string argument, undefined
+// matching the empty string.
+func patternSource(value Value) string {
+	if value == Undefined {
+		return "(?:)"
+	}
+	return escapeSource(toString(value))
+}
+
+// stringReplace replaces the first occurrence of a string.
+func (i *Interpreter) stringReplace(input string, search string, replaceValue Value) string {
+	position := strings.Index(input, search)
+	if position < 0 {
+		return input
+	}
+	var replaced string
+	if isCallable(replaceValue) {
+		replaced = toString(i.call(replaceValue, Undefined, String(search), Number(position), String(input)))
+	} else {
+		replaced = substitute(toString(replaceValue), search, input, position, nil, Undefined)
+	}
+	return input[:position] + replaced + input[position+len(search):]
+}
+
+// stringSplit splits a string around a string separator, into characters for the empty separator.
+func stringSplit(input string, separator Value, limit Value) *Object {
+	max := -1
+	if limit != Undefined {
+		max = toIndex(limit)
+	}
+	var parts []string
+	switch {
+	case separator == Undefined:
+		parts = []string{input}
+	case toString(separator) == "":
+		for _, char := range input {
+			parts = append(parts, string(char))
+		}
+	default:
+		parts = strings.Split(input, toString(separator))
+	}
+
+	result := []Value{}
+	for _, part := range parts {
+		if max != -1 && len(result) >= max {
+			break
+		}
+		result = append(result, String(part))
+	}
+	return newArrayObject(result)
+}
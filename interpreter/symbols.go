@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symbol is a unique value that can be used as a property key.
+type Symbol struct {
+	Description string
+	key         string // The property key the symbol stands for in an object
+}
+
+var symbolCount int
+
+func NewSymbol(description string) *Symbol {
+	symbolCount++
+	// Property names are strings, symbols use keys no string literal produces in practice
+	return &Symbol{Description: description, key: fmt.Sprintf("\x00%s\x00%d", description, symbolCount)}
+}
+
+func (s *Symbol) String() string {
+	return fmt.Sprintf("Symbol(%s)", s.Description)
+}
+
+// Well-known symbols
+var (
+	SymbolIterator      = NewSymbol("Symbol.iterator")
+	SymbolHasInstance   = NewSymbol("Symbol.hasInstance")
+	SymbolAsyncIterator = NewSymbol("Symbol.asyncIterator")
+	SymbolMatch         = NewSymbol("Symbol.match")
+	SymbolMatchAll      = NewSymbol("Symbol.matchAll")
+	SymbolReplace       = NewSymbol("Symbol.replace")
+	SymbolSplit         = NewSymbol("Symbol.split")
+)
+
+// isSymbolKey reports whether a property key stands for a symbol.
+func isSymbolKey(key string) bool {
+	return strings.HasPrefix(key, "\x00")
+}
+
+// symbolKeyLabel renders a symbol property key the way it is written in an object literal.
+func symbolKeyLabel(key string) string {
+	description := strings.SplitN(key[1:], "\x00", 2)[0]
+	return fmt.Sprintf("[Symbol(%s)]", description)
+}
+
+func (i *Interpreter) addSymbol() {
+	constructor := &BuiltinConstructor{Object: NewObject(nil), Name: "Symbol"}
+	constructor.Call = func(this Value, args ...Value) Value {
+		description := ""
+		if value := argument(args, 0); value != Undefined {
+			description = toString(value)
+		}
+		return NewSymbol(description)
+	}
+	constructor.Set("iterator", SymbolIterator)
+	constructor.Set("hasInstance", SymbolHasInstance)
+	constructor.Set("asyncIterator", SymbolAsyncIterator)
+	constructor.Set("match", SymbolMatch)
+	constructor.Set("matchAll", SymbolMatchAll)
+	constructor.Set("replace", SymbolReplace)
+	constructor.Set("split", SymbolSplit)
+	i.Env["Symbol"] = constructor
+}
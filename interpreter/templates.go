@@ -0,0 +1,65 @@
+package interpreter
+
+import (
+	"fmt"
+	"gojo/parser"
+	"strings"
+)
+
+// evalTemplateLiteral joins the cooked chunks of a template and its substitutions converted to strings.
+func (i *Interpreter) evalTemplateLiteral(expr *parser.TemplateLiteral) Value {
+	var out strings.Builder
+	for idx, quasi := range expr.Quasis {
+		out.WriteString(*quasi.Cooked)
+		if idx < len(expr.Expressions) {
+			out.WriteString(toString(i.evalExpression(expr.Expressions[idx])))
+		}
+	}
+	return String(out.String())
+}
+
+// evalTaggedTemplate calls the tag with the strings object of the template followed by the values
+// of its substitutions, which are not converted to strings.
+func (i *Interpreter) evalTaggedTemplate(expr *parser.TaggedTemplateExpression) Value {
+	tag, this, ok := i.evalCallee(expr.Tag)
+	if !ok {
+		return Undefined
+	}
+	args := append([]Value{i.templateObject(expr.Quasi)}, i.evalExpressions(expr.Quasi.Expressions)...)
+
+	if !isCallable(tag) {
+		fmt.Printf("Error (Line: %d): '%s' is not a function\n", expr.Token.Line, targetLabel(expr.Tag))
+		return Undefined
+	}
+	return i.call(tag, this, args...)
+}
+
+// templateObject returns the strings object of a tagged template: a frozen array of the cooked chunks,
+// undefined for chunks with invalid escapes, whose raw property is a frozen array of the raw chunks.
+// Every evaluation of the same template gets the same object.
+func (i *Interpreter) templateObject(literal *parser.TemplateLiteral) *Object {
+	if object, ok := i.templates[literal]; ok {
+		return object
+	}
+
+	var cooked, raw []Value
+	for _, quasi := range literal.Quasis {
+		if quasi.Cooked != nil {
+			cooked = append(cooked, String(*quasi.Cooked))
+		} else {
+			cooked = append(cooked, Undefined)
+		}
+		raw = append(raw, String(quasi.Raw))
+	}
+	rawObject := newArrayObject(raw)
+	rawObject.frozen = true
+	object := newArrayObject(cooked)
+	object.Set("raw", rawObject)
+	object.frozen = true
+
+	if i.templates == nil {
+		i.templates = make(map[*parser.TemplateLiteral]*Object)
+	}
+	i.templates[literal] = object
+	return object
+}
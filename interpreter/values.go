@@ -0,0 +1,265 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Value is a JavaScript value. Each type of the language is represented by Go types implementing it:
+// Undefined, Null, Boolean, Number, String, *Symbol, *BigInt, and the objects
+// (*Object, *Function, *BuiltinConstructor and *BuiltinFunction).
+type Value interface {
+	Kind() Kind
+}
+
+// Kind is the type of a value in the language.
+type Kind int
+
+const (
+	UndefinedKind Kind = iota
+	NullKind
+	BooleanKind
+	NumberKind
+	StringKind
+	SymbolKind
+	BigIntKind
+	ObjectKind
+)
+
+var kindNames = [...]string{"undefined", "null", "boolean", "number", "string", "symbol", "bigint", "object"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+type undefined struct{}
+
+func (undefined) Kind() Kind     { return UndefinedKind }
+func (undefined) String() string { return "undefined" }
+
+// Undefined is the value of missing properties, arguments and uninitialized variables.
+var Undefined = undefined{}
+
+type null struct{}
+
+func (null) Kind() Kind     { return NullKind }
+func (null) String() string { return "null" }
+
+// Null is the runtime value of the null literal.
+var Null = null{}
+
+type Boolean bool
+
+func (Boolean) Kind() Kind { return BooleanKind }
+
+// Number is an IEEE-754 double, including NaN, the infinities and -0.
+type Number float64
+
+func (Number) Kind() Kind { return NumberKind }
+
+// String renders the number like console.log, which unlike toString keeps the sign of -0.
+func (n Number) String() string {
+	if n == 0 && math.Signbit(float64(n)) {
+		return "-0"
+	}
+	return formatNumber(float64(n))
+}
+
+type String string
+
+func (String) Kind() Kind { return StringKind }
+
+func (*Symbol) Kind() Kind { return SymbolKind }
+
+// BigInt is an integer of arbitrary precision.
+type BigInt struct {
+	value *big.Int
+}
+
+func NewBigInt(value *big.Int) *BigInt {
+	return &BigInt{value: value}
+}
+
+func (*BigInt) Kind() Kind { return BigIntKind }
+
+func (b *BigInt) String() string {
+	return b.value.String() + "n"
+}
+
+func (*Object) Kind() Kind { return ObjectKind }
+
+func (*BuiltinFunction) Kind() Kind { return ObjectKind }
+
+// isNullish reports whether a value is undefined or null.
+func isNullish(value Value) bool {
+	return value == Undefined || value == Null
+}
+
+// stringOperands returns the operands of an operation when both are Strings.
+func stringOperands(left Value, right Value) (String, String, bool) {
+	leftStr, leftOk := left.(String)
+	rightStr, rightOk := right.(String)
+	return leftStr, rightStr, leftOk && rightOk
+}
+
+// bigIntOperands returns the operands of an operation when both are BigInts.
+func bigIntOperands(left Value, right Value) (*big.Int, *big.Int, bool) {
+	leftBig, leftOk := left.(*BigInt)
+	rightBig, rightOk := right.(*BigInt)
+	if !leftOk || !rightOk {
+		return nil, nil, false
+	}
+	return leftBig.value, rightBig.value, true
+}
+
+// strictEquals implements the === operator: values of different kinds are never equal, numbers and
+// BigInts compare by value, objects by identity.
+func strictEquals(left Value, right Value) bool {
+	if left.Kind() != right.Kind() {
+		return false
+	}
+	switch left := left.(type) {
+	case Number:
+		// NaN is not equal to itself, and 0 is equal to -0
+		return float64(left) == float64(right.(Number))
+	case *BigInt:
+		return left.value.Cmp(right.(*BigInt).value) == 0
+	default:
+		return left == right
+	}
+}
+
+// looselyEquals implements the == operator: null and undefined only equal each other, booleans compare as
+// numbers, strings as the numbers or BigInts they are compared to, and objects as their primitive value.
+func (i *Interpreter) looselyEquals(left Value, right Value) bool {
+	leftKind, rightKind := left.Kind(), right.Kind()
+	switch {
+	case leftKind == rightKind:
+		return strictEquals(left, right)
+	case isNullish(left) || isNullish(right):
+		return isNullish(left) && isNullish(right)
+	case leftKind == BooleanKind:
+		return i.looselyEquals(Number(toNumber(left)), right)
+	case rightKind == BooleanKind:
+		return i.looselyEquals(left, Number(toNumber(right)))
+	case leftKind == ObjectKind:
+		return i.looselyEquals(i.toPrimitive(left), right)
+	case rightKind == ObjectKind:
+		return i.looselyEquals(left, i.toPrimitive(right))
+	case leftKind == StringKind || rightKind == StringKind:
+		if leftKind == StringKind {
+			left, right = right, left
+		}
+		switch left := left.(type) {
+		case Number:
+			return float64(left) == toNumber(right)
+		case *BigInt:
+			integer, ok := stringToBigInt(string(right.(String)))
+			return ok && left.value.Cmp(integer) == 0
+		}
+	case leftKind == BigIntKind || rightKind == BigIntKind:
+		// A BigInt equals a number with the same mathematical value, never NaN or an infinity
+		if leftKind == NumberKind || rightKind == NumberKind {
+			order, ordered := compare(left, right)
+			return ordered && order == 0
+		}
+	}
+	return false
+}
+
+// toPrimitive converts an object to a primitive with its valueOf method, or its toString method when valueOf
+// is missing or returns an object, like ToPrimitive without a hint. An object with neither method converts
+// to its string.
+func (i *Interpreter) toPrimitive(value Value) Value {
+	if value.Kind() != ObjectKind {
+		return value
+	}
+	called := false
+	for _, name := range []string{"valueOf", "toString"} {
+		if method := i.getMethod(value, String(name)); method != Undefined {
+			called = true
+			if result := i.call(method, value); result.Kind() != ObjectKind {
+				return result
+			}
+		}
+	}
+	if called {
+		throwError("TypeError", "Cannot convert object to primitive value")
+	}
+	return String(toString(value))
+}
+
+// isTruthy converts a value to a boolean following the JavaScript truthiness rules.
+func isTruthy(value Value) bool {
+	switch value := value.(type) {
+	case undefined, null:
+		return false
+	case Boolean:
+		return bool(value)
+	case Number:
+		return value != 0 && !math.IsNaN(float64(value))
+	case String:
+		return value != ""
+	case *BigInt:
+		return value.value.Sign() != 0
+	default:
+		return true
+	}
+}
+
+// typeOf returns the result of the typeof operator for a value.
+func typeOf(value Value) string {
+	switch {
+	case isCallable(value):
+		return "function"
+	case value == Null:
+		return "object"
+	default:
+		return value.Kind().String()
+	}
+}
+
+// toString converts a value to a string, undefined and null by name. Arrays join their elements with commas.
+func toString(value Value) string {
+	switch value := value.(type) {
+	case *Object:
+		if _, isArray := value.internal.(arrayObject); isArray {
+			var elements []string
+			for _, element := range arrayElements(value) {
+				if isNullish(element) {
+					element = String("")
+				}
+				elements = append(elements, toString(element))
+			}
+			return strings.Join(elements, ",")
+		}
+		return value.String()
+	case String:
+		return string(value)
+	case Number:
+		return formatNumber(float64(value))
+	case *BigInt:
+		return value.value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// toIndex converts a number to a non-negative index, anything else is 0.
+func toIndex(value Value) int {
+	if number, ok := value.(Number); ok && number > 0 && !math.IsInf(float64(number), 1) {
+		return int(number)
+	}
+	return 0
+}
+
+// goValues converts values to the Go values of fmt arguments.
+func goValues(values []Value) []interface{} {
+	result := make([]interface{}, len(values))
+	for idx, value := range values {
+		result[idx] = value
+	}
+	return result
+}
@@ -3,20 +3,28 @@ package lexer
 import (
 	"fmt"
 	"gojo/config"
-	"os"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf16"
 )
 
 type Lexer struct {
 	input        string
-	position     int  // current position in input (points to current char)
-	nextPosition int  // current reading position in input (after current char)
-	curChar      byte // current char under examination
+	position     int            // current position in input (points to current char)
+	nextPosition int            // current reading position in input (after current char)
+	curChar      byte           // current char under examination
+	lineStart    int            // position of the first char of the current line
+	tokenStart   int            // position of the first char of the token being read
+	tokenLine    int            // line of the first char of the token being read
+	tokenColumn  int            // column of the first char of the token being read
+	previousType *GojoTokenType // type of the previous token, which tells a regular expression from a division
+	templates    []int          // open braces in each template substitution being read, innermost last
 	// Exported
-	Line  int // current line number
-	Start int // start position of the current token
-	End   int // end position of the current token
+	Line     int         // current line number
+	Start    int         // start position of the current token
+	End      int         // end position of the current token
+	Comments []GojoToken // comments skipped so far, in source order
 }
 
 func New(input string) *Lexer {
@@ -27,17 +35,37 @@ func New(input string) *Lexer {
 
 func (l *Lexer) NewToken(tokenType *GojoTokenType, text string) GojoToken {
 	return GojoToken{
-		Text: text,
-		Type: tokenType,
-		Line: l.Line,
+		Text:   text,
+		Type:   tokenType,
+		Line:   l.tokenLine,
+		Column: l.tokenColumn,
+		Start:  l.tokenStart,
 	}
 }
 
+// NewIllegalToken creates a token for source text that could not be tokenized.
+func (l *Lexer) NewIllegalToken(text string) GojoToken {
+	return l.NewToken(TokenText["illegal"], text)
+}
+
 func (l *Lexer) NextToken() GojoToken {
+	token := l.readToken()
+	token.End = l.position
+	token.EndLine = l.Line
+	token.EndColumn = l.position - l.lineStart + 1
+	l.previousType = token.Type
+	return token
+}
+
+func (l *Lexer) readToken() GojoToken {
 	var token GojoToken
 
 	l.skipWhitespace()
 
+	l.tokenStart = l.position
+	l.tokenLine = l.Line
+	l.tokenColumn = l.position - l.lineStart + 1
+
 	if config.LoadConfig().MegaVerbose {
 		fmt.Printf("Current character: %c\n", l.curChar)
 	}
@@ -46,16 +74,18 @@ func (l *Lexer) NextToken() GojoToken {
 	case '/':
 		if l.peekChar() == '/' {
 			l.skipInlineComment()
-			return l.NextToken()
+			l.addComment()
+			return l.readToken()
 		} else if l.peekChar() == '*' {
 			l.skipBlockComment()
-			return l.NextToken()
-		} else if l.peekChar() == '/' || l.peekChar() == '*' || l.peekChar() == '+' || l.peekChar() == '-' {
+			l.addComment()
+			return l.readToken()
+		} else if l.regexAllowed() {
 			return l.readRegex()
 		} else {
-			token = l.NewToken(TokenOperators["/"], string(l.curChar))
+			token = l.readOperator()
 		}
-	case '=', '+', '-', '*', '!', '<', '>', '&', '|', '^', '%', '?':
+	case '=', '+', '-', '*', '!', '~', '<', '>', '&', '|', '^', '%', '?':
 		token = l.readOperator()
 	case '.':
 		token = l.NewToken(TokenPunctuation["."], string(l.curChar))
@@ -75,35 +105,54 @@ func (l *Lexer) NextToken() GojoToken {
 	case ')':
 		token = l.NewToken(TokenPunctuation[")"], string(l.curChar))
 	case '{':
+		if depth := len(l.templates); depth > 0 {
+			l.templates[depth-1]++
+		}
 		token = l.NewToken(TokenPunctuation["{"], string(l.curChar))
 	case '}':
+		// The brace closing a substitution resumes its template
+		if depth := len(l.templates); depth > 0 {
+			if l.templates[depth-1] == 0 {
+				l.templates = l.templates[:depth-1]
+				return l.readTemplate()
+			}
+			l.templates[depth-1]--
+		}
 		token = l.NewToken(TokenPunctuation["}"], string(l.curChar))
 	case '[':
 		token = l.NewToken(TokenPunctuation["["], string(l.curChar))
 	case ']':
 		token = l.NewToken(TokenPunctuation["]"], string(l.curChar))
-	case '"', '\'', '`': // Handle strings with all three quote types
+	case '"', '\'':
 		return l.readString(l.curChar)
+	case '`':
+		return l.readTemplate()
 	case 0:
-		token = GojoToken{Type: TokenText["eof"], Text: ""}
+		token = l.NewToken(TokenText["eof"], "")
 	default:
 		// Note: letters can be a lot! (e.g., keywords, literals and identifiers)
 		if isLetter(l.curChar) {
 			word := l.readWord()
 			tokenType, ok := TokenKeywords[word]
 			if !ok {
-				tokenType, ok = TokenLiterals[word]
-				if !ok {
+				// Only some literals are words, e.g., a variable may be named string or template
+				switch word {
+				case "true", "false", "undefined", "null":
+					tokenType = TokenLiterals[word]
+				default:
 					tokenType = TokenText["identifier"]
 				}
 			}
 			return l.NewToken(tokenType, word)
 		} else if isDigit(l.curChar) {
 			number := l.readNumber()
+			if l.curChar == 'n' && isBigIntText(number) {
+				l.readChar()
+				return l.NewToken(TokenLiterals["bigint"], number+"n")
+			}
 			return l.NewToken(TokenLiterals["number"], number)
 		} else {
-			fmt.Println("Unknown token: ", string(l.curChar))
-			os.Exit(1)
+			token = l.NewIllegalToken(string(l.curChar))
 		}
 	}
 
@@ -117,6 +166,12 @@ func (l *Lexer) NextToken() GojoToken {
  */
 
 func (l *Lexer) readOperator() GojoToken {
+	// The arrow is punctuation, but shares its first character with the operators
+	if l.curChar == '=' && l.peekChar() == '>' {
+		l.readChar()
+		return l.NewToken(TokenPunctuation["=>"], "=>")
+	}
+
 	operatorStr := string(l.curChar)
 	tokenType, validToken := TokenOperators[operatorStr]
 
@@ -142,7 +197,7 @@ func (l *Lexer) readOperator() GojoToken {
 	}
 
 	if !validToken {
-		panic("Invalid operator read: " + operatorStr)
+		return l.NewIllegalToken(operatorStr)
 	}
 
 	return l.NewToken(tokenType, operatorStr)
@@ -150,79 +205,260 @@ func (l *Lexer) readOperator() GojoToken {
 
 func (l *Lexer) readString(quoteType byte) GojoToken {
 	l.readChar() // Consume the opening quote
-	var text string
+	var text strings.Builder
+	octalEscape, invalid := false, false
 
 	for {
 		readChar := l.curChar
-		if readChar == 0 {
-			panic("Unterminated string literal: " + text)
+		// Only template literals may span multiple lines
+		if readChar == 0 || (readChar == '\n' && quoteType != '`') {
+			return l.NewIllegalToken(l.input[l.tokenStart:l.position])
 		} else if readChar == quoteType {
 			l.readChar() // Consume closing quote
 			break
 		} else if readChar == '\\' {
-			text += l.readEscapeSequence(quoteType)
+			escaped, octal, ok := l.readEscapeSequence()
+			text.WriteString(escaped)
+			octalEscape = octalEscape || octal
+			invalid = invalid || !ok
 		} else {
-			text += string(readChar)
+			// The bytes of UTF-8 encoded characters are copied as they are
+			text.WriteByte(readChar)
 			l.readChar()
 		}
 	}
 
-	return l.NewToken(TokenLiterals["string"], text)
+	// An invalid escape (e.g., "\x4") makes the whole string illegal
+	if invalid {
+		token := l.NewIllegalToken(l.input[l.tokenStart:l.position])
+		token.BadEscape = true
+		return token
+	}
+	token := l.NewToken(TokenLiterals["string"], text.String())
+	token.Raw = l.input[l.tokenStart:l.position]
+	token.OctalEscape = octalEscape
+	return token
 }
 
-func (l *Lexer) readEscapeSequence(quoteType byte) string {
+// readEscapeSequence reads an escape sequence of a string and returns its value. It also reports whether
+// it is a legacy octal escape (e.g., "\07") or "\8" or "\9", which strict mode code may not contain, and
+// whether it is valid.
+func (l *Lexer) readEscapeSequence() (string, bool, bool) {
+	digit := l.peekChar()
+	if !isDigit(digit) || (digit == '0' && !isDigit(l.peekCharTwo())) {
+		value, ok := l.readCharacterEscape()
+		return value, false, ok
+	}
 	l.readChar() // Consume escape character
-	switch l.curChar {
-	case 'n':
-		return "\n"
-	case 't':
-		return "\t"
-	case 'r':
-		return "\r"
-	case '\\':
-		return "\\"
-	case '\'':
-		return "'"
-	case '"':
-		return "\""
-	case '`':
-		return "`"
+	l.readChar() // Consume the first digit
+	if digit == '8' || digit == '9' {
+		return string(digit), true, true
+	}
+	// Up to three octal digits, as long as the value fits in a byte
+	value := int(digit - '0')
+	for digits := 1; digits < 3 && isOctalDigit(l.curChar) && value*8+int(l.curChar-'0') <= 0377; digits++ {
+		value = value*8 + int(l.curChar-'0')
+		l.readChar()
+	}
+	return string(rune(value)), true, true
+}
+
+// readTemplate reads a template chunk, from the '`' or the '}' of a substitution to the next substitution
+// or the end of the template. Line terminators are normalized to '\n' in both the raw and cooked text.
+func (l *Lexer) readTemplate() GojoToken {
+	head := l.curChar == '`'
+	l.readChar() // Consume the '`' or '}'
+	var cooked, raw strings.Builder
+	badEscape := false
+
+	for {
+		switch {
+		case l.curChar == 0:
+			return l.NewIllegalToken(l.input[l.tokenStart:l.position])
+		case l.curChar == '`':
+			l.readChar()
+			tokenType := TokenLiterals["templateTail"]
+			if head {
+				tokenType = TokenLiterals["template"]
+			}
+			return l.newTemplateToken(tokenType, cooked.String(), raw.String(), badEscape)
+		case l.curChar == '$' && l.peekChar() == '{':
+			l.readChar()
+			l.readChar()
+			l.templates = append(l.templates, 0)
+			tokenType := TokenLiterals["templateMiddle"]
+			if head {
+				tokenType = TokenLiterals["templateHead"]
+			}
+			return l.newTemplateToken(tokenType, cooked.String(), raw.String(), badEscape)
+		case l.curChar == '\\':
+			start := l.position
+			value, ok := l.readCharacterEscape()
+			raw.WriteString(strings.ReplaceAll(strings.ReplaceAll(l.input[start:l.position], "\r\n", "\n"),
+				"\r", "\n"))
+			cooked.WriteString(value)
+			badEscape = badEscape || !ok
+		case l.curChar == '\r':
+			if l.peekChar() == '\n' {
+				l.readChar()
+			}
+			l.readChar()
+			cooked.WriteByte('\n')
+			raw.WriteByte('\n')
+		default:
+			cooked.WriteByte(l.curChar)
+			raw.WriteByte(l.curChar)
+			l.readChar()
+		}
+	}
+}
+
+func (l *Lexer) newTemplateToken(tokenType *GojoTokenType, cooked string, raw string, badEscape bool) GojoToken {
+	token := l.NewToken(tokenType, cooked)
+	token.Raw = raw
+	token.BadEscape = badEscape
+	if badEscape {
+		token.Text = ""
+	}
+	return token
+}
+
+// readCharacterEscape reads an escape sequence of a template or a string and returns its cooked value.
+// Invalid escapes (e.g., \unicode or \01) are only allowed in tagged templates, which see them as
+// undefined. Strings read their legacy octal escapes with readEscapeSequence instead.
+func (l *Lexer) readCharacterEscape() (string, bool) {
+	l.readChar() // Consume the backslash
+	escaped := l.curChar
+	switch escaped {
+	case 0:
+		return "", false
 	case 'x':
+		l.readChar()
 		hex := l.readHex(2)
-		return fmt.Sprintf("\\x%s", hex)
+		if len(hex) != 2 {
+			return "", false
+		}
+		value, _ := strconv.ParseUint(hex, 16, 32)
+		return string(rune(value)), true
 	case 'u':
-		hex := l.readHex(4)
-		return fmt.Sprintf("\\u%s", hex)
-	case quoteType:
-		return string(quoteType)
+		l.readChar()
+		value, ok := l.readUnicodeEscape()
+		if !ok {
+			return "", false
+		}
+		// A surrogate pair escaped as two \u escapes is one character
+		if utf16.IsSurrogate(value) && l.curChar == '\\' && l.peekChar() == 'u' {
+			position, nextPosition, curChar := l.position, l.nextPosition, l.curChar
+			l.readChar()
+			l.readChar()
+			if low, ok := l.readUnicodeEscape(); ok && utf16.DecodeRune(value, low) != unicode.ReplacementChar {
+				return string(utf16.DecodeRune(value, low)), true
+			}
+			l.position, l.nextPosition, l.curChar = position, nextPosition, curChar
+		}
+		return string(value), true
+	case '0':
+		l.readChar()
+		if isDigit(l.curChar) {
+			return "", false
+		}
+		return "\x00", true
+	case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		l.readChar()
+		return "", false
+	case '\r':
+		// A line continuation is not part of the cooked value
+		if l.peekChar() == '\n' {
+			l.readChar()
+		}
+		l.readChar()
+		return "", true
+	case '\n':
+		l.readChar()
+		return "", true
+	}
+
+	l.readChar()
+	switch escaped {
+	case 'n':
+		return "\n", true
+	case 't':
+		return "\t", true
+	case 'r':
+		return "\r", true
+	case 'b':
+		return "\b", true
+	case 'f':
+		return "\f", true
+	case 'v':
+		return "\v", true
 	default:
-		fmt.Println("Invalid escape sequence in string: \\", string(l.curChar))
-		os.Exit(1)
-		return ""
+		// Any other character escapes to itself (e.g., \` is `), the rest of a UTF-8 sequence follows
+		return string([]byte{escaped}), true
+	}
+}
+
+// readUnicodeEscape reads the digits of a \u escape, either four hex digits or a code point in braces.
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	if l.curChar != '{' {
+		hex := l.readHex(4)
+		if len(hex) != 4 {
+			return 0, false
+		}
+		value, _ := strconv.ParseUint(hex, 16, 32)
+		return rune(value), true
+	}
+	l.readChar()
+	hex := l.readHex(len(l.input))
+	if l.curChar != '}' || hex == "" {
+		return 0, false
 	}
+	l.readChar()
+	value, _ := strconv.ParseUint(hex, 16, 32)
+	if value > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(value), true
+}
+
+// regexAllowed reports whether a '/' starts a regular expression rather than a division, which is
+// the case where an expression can start: at the beginning of the input or after a token that can be
+// followed by an expression (e.g., '(', '=' or return).
+func (l *Lexer) regexAllowed() bool {
+	return l.previousType == nil || l.previousType.BeforeExpr
 }
 
+// readRegex reads a regular expression literal, including its flags (e.g., /a[/]b/gi). A '/' only ends
+// the pattern outside of a character class and when it is not escaped. The pattern cannot span lines.
 func (l *Lexer) readRegex() GojoToken {
 	startPos := l.position
+	inClass := false
 	for {
 		l.readChar()
-		if l.curChar == '/' && l.input[l.position-1] != '\\' {
-			break
-		}
-		if l.curChar == 0 {
-			fmt.Println("Unterminated regex literal")
-			os.Exit(1)
+		switch {
+		case l.curChar == 0 || l.curChar == '\n' || l.curChar == '\r':
+			return l.NewIllegalToken(l.input[startPos:l.position])
+		case l.curChar == '\\' && l.peekChar() != 0 && l.peekChar() != '\n' && l.peekChar() != '\r':
+			l.readChar() // An escaped character never ends the pattern
+		case l.curChar == '[':
+			inClass = true
+		case l.curChar == ']':
+			inClass = false
+		case l.curChar == '/' && !inClass:
+			l.readChar() // Move past the closing '/'
+			for isLetter(l.curChar) || isDigit(l.curChar) {
+				l.readChar()
+			}
+			return l.NewToken(TokenLiterals["regexp"], l.input[startPos:l.position])
 		}
 	}
-	l.readChar() // Move past the closing '/'
-	return l.NewToken(TokenLiterals["regexp"], l.input[startPos:l.position])
 }
 
 func (l *Lexer) readHex(length int) string {
 	var hex string
-	for i := 0; i < length; i++ {
-		l.readChar()
+	for i := 0; i < length && isHexDigit(l.curChar); i++ {
 		hex += string(l.curChar)
+		l.readChar()
 	}
 	return hex
 }
@@ -236,6 +472,7 @@ func (l *Lexer) readChar() {
 			fmt.Println("░ Newline detected")
 		}
 		l.Line++
+		l.lineStart = l.nextPosition
 	}
 	l.curChar = l.peekChar()
 	l.Start = l.position
@@ -316,17 +553,24 @@ func (l *Lexer) readNumber() string {
 	return l.input[startPos:l.position]
 }
 
+// isBigIntText reports whether a number can be made a BigInt literal by an n suffix: only integers can,
+// except for legacy octal literals and decimals with leading zeros (e.g., 017 or 019).
+func isBigIntText(number string) bool {
+	if len(number) > 1 && number[0] == '0' && isDigit(number[1]) {
+		return false
+	}
+	if len(number) > 1 && strings.ContainsAny(number[1:2], "xXoObB") {
+		return true
+	}
+	return !strings.ContainsAny(number, ".eE")
+}
+
 func (l *Lexer) readWord() string {
 	pos := l.position
 	for isLetter(l.curChar) || isDigit(l.curChar) {
-		// TODO: Unicode escape sequences in identifiers are not supported
-		if isHexDigit(l.peekCharTwo()) && isHexDigit(l.input[l.position+3]) &&
-			isHexDigit(l.input[l.position+4]) && isHexDigit(l.input[l.position+5]) {
-			fmt.Println("Unicode escape sequences in identifiers are not supported.")
-			os.Exit(1)
-		}
 		l.readChar()
 	}
+	// TODO: Unicode escape sequences in identifiers are not supported, the '\\' is lexed as an illegal token
 	return l.input[pos:l.position]
 }
 
@@ -370,6 +614,15 @@ func (l *Lexer) skipInlineComment() {
 	}
 }
 
+// addComment records the comment just skipped, it started where the token being read did.
+func (l *Lexer) addComment() {
+	comment := l.NewToken(TokenText["comment"], l.input[l.tokenStart:l.position])
+	comment.End = l.position
+	comment.EndLine = l.Line
+	comment.EndColumn = l.position - l.lineStart + 1
+	l.Comments = append(l.Comments, comment)
+}
+
 func (l *Lexer) skipBlockComment() {
 	l.readChar() // consume the '*'
 	for {
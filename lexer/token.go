@@ -3,9 +3,20 @@ package lexer
 import "fmt"
 
 type GojoToken struct {
-	Type *GojoTokenType // The type of the token
-	Text string         // The Text of the token
-	Line int            // The line number of the token
+	Type   *GojoTokenType // The type of the token
+	Text   string         // The Text of the token
+	Line   int            // The line number of the token
+	Column int            // The column of the first character of the token
+	Start  int            // The offset of the first character of the token
+	End    int            // The offset after the last character of the token
+	// The line and column of the position after the last character of the token
+	EndLine   int
+	EndColumn int
+	// Strings, with their quotes, and template chunks keep their source text in Raw, Text is the cooked
+	// value with escapes interpreted
+	Raw         string
+	BadEscape   bool // Whether a template chunk or a string has an invalid escape, a chunk then has no cooked value
+	OctalEscape bool // Whether a string has a legacy octal escape (e.g., "\07"), or "\8" or "\9"
 }
 
 func (t GojoToken) String() string {
@@ -80,9 +91,9 @@ var TokenPunctuation = map[string]*GojoTokenType{
 	"}":   {Label: "}", BeforeExpr: false},
 	"[":   {Label: "[", BeforeExpr: true, StartsExpr: true},
 	"]":   {Label: "]", BeforeExpr: false},
-	",":   {Label: ",", BeforeExpr: false},
-	";":   {Label: ";", BeforeExpr: false},
-	":":   {Label: ":", BeforeExpr: false},
+	",":   {Label: ",", BeforeExpr: true},
+	";":   {Label: ";", BeforeExpr: true},
+	":":   {Label: ":", BeforeExpr: true},
 	".":   {Label: ".", BeforeExpr: true, StartsExpr: true},
 	"=>":  {Label: "=>", BeforeExpr: true},
 	"...": {Label: "...", BeforeExpr: true},
@@ -96,8 +107,8 @@ var TokenOperators = map[string]*GojoTokenType{
 	"/":    {Label: "/", BeforeExpr: true, StartsExpr: true},
 	"!":    {Label: "!", BeforeExpr: true, StartsExpr: true},
 	"~":    {Label: "~", BeforeExpr: true, StartsExpr: true},
-	"++":   {Label: "++", BeforeExpr: true, StartsExpr: true},
-	"--":   {Label: "--", BeforeExpr: true, StartsExpr: true},
+	"++":   {Label: "++", StartsExpr: true}, // A '/' after x++ is a division
+	"--":   {Label: "--", StartsExpr: true},
 	"+=":   {Label: "+=", BeforeExpr: true},
 	"-=":   {Label: "-=", BeforeExpr: true},
 	"*=":   {Label: "*=", BeforeExpr: true},
@@ -109,6 +120,10 @@ var TokenOperators = map[string]*GojoTokenType{
 	"<<=":  {Label: "<<=", BeforeExpr: true},
 	">>=":  {Label: ">>=", BeforeExpr: true},
 	">>>=": {Label: ">>>=", BeforeExpr: true},
+	"**=":  {Label: "**=", BeforeExpr: true},
+	"&&=":  {Label: "&&=", BeforeExpr: true},
+	"||=":  {Label: "||=", BeforeExpr: true},
+	"??=":  {Label: "??=", BeforeExpr: true},
 	"==":   {Label: "==", BeforeExpr: true},  // Equality
 	"!=":   {Label: "!=", BeforeExpr: true},  // Equality
 	"!==":  {Label: "!==", BeforeExpr: true}, // Equality
@@ -128,6 +143,7 @@ var TokenOperators = map[string]*GojoTokenType{
 	"%":    {Label: "%", BeforeExpr: true},   // Modulo
 	"**":   {Label: "**", BeforeExpr: true},  // Exponentiation
 	"??":   {Label: "??", BeforeExpr: true},  // Coalesce
+	"?":    {Label: "?", BeforeExpr: true},   // Conditional
 	"?.":   {Label: "?.", BeforeExpr: true},  // Optional chaining
 }
 
@@ -135,15 +151,21 @@ var TokenText = map[string]*GojoTokenType{
 	"identifier": {Label: "identifier", StartsExpr: true}, // Needs lexer function
 	"sof":        {Label: "sof"},
 	"eof":        {Label: "eof"},
+	"illegal":    {Label: "illegal"}, // Source text the lexer could not tokenize
+	"comment":    {Label: "comment"}, // Only in the comments of the lexer, never a token of the stream
 }
 
 var TokenLiterals = map[string]*GojoTokenType{
-	"number":    {Label: "number", StartsExpr: true},   // Needs lexer function
-	"string":    {Label: "string", StartsExpr: true},   // Needs lexer function
-	"template":  {Label: "template", StartsExpr: true}, // Needs lexer function
-	"regexp":    {Label: "regexp", StartsExpr: true},   // Needs lexer function
-	"true":      {Label: "boolean", StartsExpr: true},
-	"false":     {Label: "boolean", StartsExpr: true},
-	"undefined": {Label: "undefined", StartsExpr: true},
-	"null":      {Label: "null", StartsExpr: true},
+	"number":         {Label: "number", StartsExpr: true},                         // Needs lexer function
+	"bigint":         {Label: "bigint", StartsExpr: true},                         // Needs lexer function, e.g. 10n
+	"string":         {Label: "string", StartsExpr: true},                         // Needs lexer function
+	"template":       {Label: "template", StartsExpr: true},                       // Needs lexer function, a template without substitutions
+	"templateHead":   {Label: "templateHead", BeforeExpr: true, StartsExpr: true}, // `a${
+	"templateMiddle": {Label: "templateMiddle", BeforeExpr: true},                 // }b${
+	"templateTail":   {Label: "templateTail"},                                     // }c`
+	"regexp":         {Label: "regexp", StartsExpr: true},                         // Needs lexer function
+	"true":           {Label: "boolean", StartsExpr: true},
+	"false":          {Label: "boolean", StartsExpr: true},
+	"undefined":      {Label: "undefined", StartsExpr: true},
+	"null":           {Label: "null", StartsExpr: true},
 }
@@ -0,0 +1,132 @@
+// Package lint reports likely bugs of a program, found by rules checking its AST and its scopes.
+//
+// A diagnostic is left out when its line, or the line before, has a comment disabling its rule:
+//
+//	a == b; // gojo-lint-disable-line eqeqeq
+//	// gojo-lint-disable-next-line no-unused-vars, no-shadow
+//
+// A disable comment without rule names disables every rule.
+package lint
+
+import (
+	"fmt"
+	"gojo/parser"
+	"gojo/scope"
+	"sort"
+	"strings"
+)
+
+// Rule checks a program for one kind of problem.
+type Rule interface {
+	Name() string // The name disable comments refer to the rule by, e.g., no-unused-vars
+	Check(pass *Pass)
+}
+
+// Pass is a rule checking a program: what it checks and where it reports.
+type Pass struct {
+	Program     *parser.Program
+	Scopes      *scope.Info
+	rule        Rule
+	diagnostics []Diagnostic
+}
+
+// Report reports a problem positioned at a node.
+func (p *Pass) Report(node parser.Node, format string, args ...any) {
+	loc := node.Location()
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		Rule:      p.rule.Name(),
+		Message:   fmt.Sprintf(format, args...),
+		Line:      loc.Start.Line,
+		Column:    loc.Start.Column,
+		EndLine:   loc.End.Line,
+		EndColumn: loc.End.Column,
+		offset:    loc.Start.Offset,
+	})
+}
+
+// Diagnostic is a problem found by a rule, lines and columns are counted from 1.
+type Diagnostic struct {
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	offset    int
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s (Line: %d, Column: %d): %s", d.Rule, d.Line, d.Column, d.Message)
+}
+
+// DefaultRules are the rules gojo lint checks.
+var DefaultRules = []Rule{
+	unusedVars{},
+	constAssign{},
+	eqeqeq{},
+	unreachable{},
+	duplicateCase{},
+	shadow{},
+}
+
+// Run checks a program with rules, the diagnostics are returned in source order.
+func Run(program *parser.Program, rules []Rule) []Diagnostic {
+	scopes := scope.Analyze(program)
+	disabled := disabledLines(program.Comments)
+
+	var diagnostics []Diagnostic
+	for _, rule := range rules {
+		pass := &Pass{Program: program, Scopes: scopes, rule: rule}
+		rule.Check(pass)
+		for _, diagnostic := range pass.diagnostics {
+			if !disabled.covers(diagnostic) {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(a, b int) bool { return diagnostics[a].offset < diagnostics[b].offset })
+	return diagnostics
+}
+
+// disabled holds the rules disable comments turn off by line, the empty name standing for every rule.
+type disabled map[int]map[string]bool
+
+func (d disabled) covers(diagnostic Diagnostic) bool {
+	return d[diagnostic.Line][""] || d[diagnostic.Line][diagnostic.Rule]
+}
+
+const (
+	disableLine     = "gojo-lint-disable-line"
+	disableNextLine = "gojo-lint-disable-next-line"
+)
+
+func disabledLines(comments []*parser.Comment) disabled {
+	lines := disabled{}
+	for _, comment := range comments {
+		text := strings.TrimPrefix(comment.Text, "//")
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		directive, rest, _ := strings.Cut(strings.TrimSpace(text), " ")
+
+		var line int
+		switch directive {
+		case disableLine:
+			line = comment.Start.Line
+		case disableNextLine:
+			line = comment.End.Line + 1
+		default:
+			continue
+		}
+		if lines[line] == nil {
+			lines[line] = map[string]bool{}
+		}
+		rules := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' })
+		if len(rules) == 0 {
+			rules = []string{""}
+		}
+		for _, rule := range rules {
+			lines[line][rule] = true
+		}
+	}
+	return lines
+}
@@ -0,0 +1,287 @@
+package lint
+
+import (
+	"gojo/parser"
+	"gojo/scope"
+	"strconv"
+)
+
+// unusedVars reports variables which are never read. Parameters are only reported after the last one
+// used, the others keep the position of those.
+type unusedVars struct{}
+
+func (unusedVars) Name() string { return "no-unused-vars" }
+
+func (unusedVars) Check(pass *Pass) {
+	exported := exportedDeclarations(pass)
+	for _, s := range allScopes(pass.Scopes.Root) {
+		lastUsedParameter := -1
+		var parameters []*scope.Declaration
+		for _, declaration := range s.Declarations {
+			if declaration.Kind == scope.Parameter {
+				if isUsed(declaration) {
+					lastUsedParameter = len(parameters)
+				}
+				parameters = append(parameters, declaration)
+			}
+		}
+
+		for _, declaration := range s.Declarations {
+			switch {
+			case isUsed(declaration) || exported[declaration] || declaration.Kind == scope.CatchParameter:
+				continue
+			case declaration.Kind == scope.FunctionName:
+				if _, ok := declaration.Node.(*parser.FunctionExpression); ok {
+					continue
+				}
+			case declaration.Kind == scope.Parameter:
+				if indexOf(parameters, declaration) < lastUsedParameter {
+					continue
+				}
+			}
+			if isAssigned(declaration) {
+				pass.Report(declaration.Identifiers[0], "'%s' is assigned a value but never used", declaration.Name)
+			} else {
+				pass.Report(declaration.Identifiers[0], "'%s' is declared but never used", declaration.Name)
+			}
+		}
+	}
+}
+
+// constAssign reports assignments to constants.
+type constAssign struct{}
+
+func (constAssign) Name() string { return "no-const-assign" }
+
+func (constAssign) Check(pass *Pass) {
+	for _, s := range allScopes(pass.Scopes.Root) {
+		for _, declaration := range s.Declarations {
+			if declaration.Kind != scope.Const {
+				continue
+			}
+			for _, reference := range declaration.References {
+				if reference.Write {
+					pass.Report(reference.Identifier, "cannot assign to '%s' because it is a constant",
+						declaration.Name)
+				}
+			}
+		}
+	}
+}
+
+// eqeqeq reports the comparisons converting their operands, == and !=.
+type eqeqeq struct{}
+
+func (eqeqeq) Name() string { return "eqeqeq" }
+
+func (eqeqeq) Check(pass *Pass) {
+	parser.Inspect(pass.Program, func(node parser.Node) bool {
+		if expr, ok := node.(*parser.BinaryExpression); ok && (expr.Operator == "==" || expr.Operator == "!=") {
+			pass.Report(expr, "expected '%s=' and instead saw '%s'", expr.Operator, expr.Operator)
+		}
+		return true
+	})
+}
+
+// unreachable reports the first statement of a list following one which always jumps away. Function
+// declarations and var declarations without initializers are hoisted, so they are not unreachable.
+type unreachable struct{}
+
+func (unreachable) Name() string { return "no-unreachable" }
+
+func (unreachable) Check(pass *Pass) {
+	parser.Inspect(pass.Program, func(node parser.Node) bool {
+		switch node := node.(type) {
+		case *parser.Program:
+			checkReachable(pass, node.Statements)
+		case *parser.BlockStatement:
+			checkReachable(pass, node.Statements)
+		case *parser.CaseClause:
+			checkReachable(pass, node.Consequent)
+		}
+		return true
+	})
+}
+
+func checkReachable(pass *Pass, statements []parser.Statement) {
+	jumped := false
+	for _, stmt := range statements {
+		if _, empty := stmt.(*parser.EmptyStatement); empty {
+			continue
+		}
+		if jumped && !isHoisted(stmt) {
+			pass.Report(stmt, "unreachable code")
+			return
+		}
+		jumped = jumped || jumps(stmt)
+	}
+}
+
+// jumps reports whether a statement always ends with a return, throw, break or continue statement.
+func jumps(stmt parser.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *parser.ReturnStatement, *parser.ThrowStatement, *parser.BreakStatement, *parser.ContinueStatement:
+		return true
+	case *parser.BlockStatement:
+		for _, s := range stmt.Statements {
+			if jumps(s) {
+				return true
+			}
+		}
+	case *parser.IfStatement:
+		return stmt.Alternative != nil && jumps(stmt.Consequence) && jumps(stmt.Alternative)
+	}
+	return false
+}
+
+func isHoisted(stmt parser.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *parser.FunctionDeclaration:
+		return true
+	case *parser.VariableDeclaration:
+		if stmt.Token.Type.Label != "var" {
+			return false
+		}
+		for _, declarator := range stmt.Declarations {
+			if declarator.Value != nil {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// duplicateCase reports the case clauses of a switch statement testing the same expression as an earlier one.
+type duplicateCase struct{}
+
+func (duplicateCase) Name() string { return "no-duplicate-case" }
+
+func (duplicateCase) Check(pass *Pass) {
+	parser.Inspect(pass.Program, func(node parser.Node) bool {
+		stmt, ok := node.(*parser.SwitchStatement)
+		if !ok {
+			return true
+		}
+		seen := map[string]bool{}
+		for _, clause := range stmt.Cases {
+			if clause.Condition == nil {
+				continue
+			}
+			condition := caseValue(clause.Condition)
+			if seen[condition] {
+				pass.Report(clause, "duplicate case label")
+			}
+			seen[condition] = true
+		}
+		return true
+	})
+}
+
+// caseValue identifies the value a case clause tests: literals by their value (e.g., 0x10 and 16, or "\x41" and
+// "A", are the same), other expressions by their string, which does not depend on their location.
+func caseValue(expr parser.Expression) string {
+	switch expr := expr.(type) {
+	case *parser.IntegerLiteral:
+		return "number " + strconv.FormatFloat(expr.Value, 'g', -1, 64)
+	case *parser.BigIntLiteral:
+		return "bigint " + expr.Value.String()
+	case *parser.StringLiteral:
+		return "string " + expr.Value
+	}
+	return expr.String()
+}
+
+// shadow reports declarations hiding a declaration of an enclosing scope. The name a function
+// expression has in its own scope is left out, it often repeats the variable the function is assigned to.
+type shadow struct{}
+
+func (shadow) Name() string { return "no-shadow" }
+
+func (shadow) Check(pass *Pass) {
+	for _, s := range allScopes(pass.Scopes.Root) {
+		if s.Parent == nil {
+			continue
+		}
+		for _, declaration := range s.Declarations {
+			if _, ok := declaration.Node.(*parser.FunctionExpression); ok && declaration.Kind == scope.FunctionName {
+				continue
+			}
+			if outer := s.Parent.Lookup(declaration.Name); outer != nil {
+				pass.Report(declaration.Identifiers[0], "'%s' is already declared in the upper scope on line %d",
+					declaration.Name, outer.Identifiers[0].Start.Line)
+			}
+		}
+	}
+}
+
+// allScopes returns a scope and the scopes nested in it, parents first.
+func allScopes(root *scope.Scope) []*scope.Scope {
+	scopes := []*scope.Scope{root}
+	for _, child := range root.Children {
+		scopes = append(scopes, allScopes(child)...)
+	}
+	return scopes
+}
+
+// exportedDeclarations returns the declarations a module exports as it declares them, they are used by
+// the modules importing them.
+func exportedDeclarations(pass *Pass) map[*scope.Declaration]bool {
+	exported := map[*scope.Declaration]bool{}
+	for _, stmt := range pass.Program.Statements {
+		var declaration parser.Node
+		switch stmt := stmt.(type) {
+		case *parser.ExportNamedDeclaration:
+			declaration = stmt.Declaration
+		case *parser.ExportDefaultDeclaration:
+			declaration = stmt.Declaration
+		}
+		switch declaration := declaration.(type) {
+		case *parser.VariableDeclaration:
+			for _, declarator := range declaration.Declarations {
+				exported[pass.Scopes.Declarations[declarator.Name]] = true
+			}
+		case *parser.FunctionDeclaration:
+			if declaration.Name != nil {
+				exported[pass.Scopes.Declarations[declaration.Name]] = true
+			}
+		}
+	}
+	return exported
+}
+
+// isUsed reports whether a variable is read, assigning it alone does not use it.
+func isUsed(declaration *scope.Declaration) bool {
+	for _, reference := range declaration.References {
+		if reference.Read {
+			return true
+		}
+	}
+	return false
+}
+
+// isAssigned reports whether a variable is initialized or assigned.
+func isAssigned(declaration *scope.Declaration) bool {
+	if stmt, ok := declaration.Node.(*parser.VariableDeclaration); ok {
+		for _, declarator := range stmt.Declarations {
+			if declarator.Name.Value == declaration.Name && declarator.Value != nil {
+				return true
+			}
+		}
+	}
+	for _, reference := range declaration.References {
+		if reference.Write {
+			return true
+		}
+	}
+	return false
+}
+
+func indexOf(declarations []*scope.Declaration, declaration *scope.Declaration) int {
+	for idx, d := range declarations {
+		if d == declaration {
+			return idx
+		}
+	}
+	return -1
+}
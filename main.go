@@ -1,16 +1,27 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"gojo/config"
+	"gojo/estree"
 	"gojo/interpreter"
 	"gojo/lexer"
+	"gojo/lint"
 	"gojo/parser"
+	"gojo/printer"
 	"gojo/repl"
 	"os"
+	"strings"
 )
 
 func main() {
+	// Commands, e.g., gojo ast --json file.js
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	env := config.LoadConfig()
 
 	// Repl mode
@@ -34,19 +45,24 @@ func main() {
 	}
 	printInput(input)
 
-	// Initialize lexer, parser, and interpreter
-	// ✋ Note: keep all instances of these as l, p and i
-	l := lexer.New(input)
-	p := parser.New(l)
-	program := p.ParseProgram()
+	program, errors, err := parseFile(inputFile, input)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		return
+	}
 
 	if config.LoadConfig().Verbose {
 		printProgramDetails(program)
 	}
 
+	// Early errors are only checked once the program parsed without syntax errors
+	if len(errors) == 0 {
+		errors = parser.CheckEarlyErrors(program)
+	}
+
 	// Check for parser errors
-	if len(p.Errors()) != 0 {
-		printParserErrors(p)
+	if len(errors) != 0 {
+		printParserErrors(errors)
 		return
 	}
 
@@ -54,6 +70,235 @@ func main() {
 	i.Interpret(program)
 }
 
+// commands are the subcommands of gojo, each returns the exit status.
+var commands = map[string]func(args []string) int{
+	"ast":  astCommand,
+	"fmt":  fmtCommand,
+	"lint": lintCommand,
+}
+
+func runCommand(args []string) int {
+	command, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+		return 2
+	}
+	return command(args[1:])
+}
+
+// astCommand prints the AST of a file, as ESTree JSON with --json.
+func astCommand(args []string) int {
+	flags := flag.NewFlagSet("ast", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the AST as ESTree JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gojo ast [--json] file.js")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	filename := flags.Arg(0)
+	input, err := readFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+		return 1
+	}
+	program, errors, err := parseFile(filename, input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(errors) != 0 {
+		printParserErrors(errors)
+		return 1
+	}
+
+	if !*asJSON {
+		fmt.Println(program.String())
+		return 0
+	}
+	data, err := estree.MarshalIndent(program, "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+// fmtCommand formats files, printing them, writing them back with -w or listing the unformatted ones with --check.
+func fmtCommand(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	check := flags.Bool("check", false, "list the files that are not formatted and fail if there are any")
+	write := flags.Bool("w", false, "write the formatted source back to the files")
+	indent := flags.Int("indent", 2, "number of spaces per indentation level, 0 for tabs")
+	quote := flags.String("quote", "double", "preferred string quote, double or single")
+	semicolons := flags.Bool("semicolons", true, "end statements with semicolons")
+	width := flags.Int("width", 80, "line width to fit lists in")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gojo fmt [--check] [-w] [flags] file.js...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || *indent < 0 || *width <= 0 || (*quote != "double" && *quote != "single") {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := printer.Config{
+		Indent:         strings.Repeat(" ", *indent),
+		SingleQuote:    *quote == "single",
+		OmitSemicolons: !*semicolons,
+		Width:          *width,
+	}
+	if *indent == 0 {
+		cfg.Indent = "\t"
+	}
+
+	status := 0
+	for _, filename := range flags.Args() {
+		if strings.HasSuffix(filename, ".json") {
+			fmt.Fprintf(os.Stderr, "%s: ESTree JSON files have no source to format\n", filename)
+			status = 1
+			continue
+		}
+		input, err := readFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+			status = 1
+			continue
+		}
+		program, errors, _ := parseFile(filename, input)
+		if len(errors) != 0 {
+			for _, err := range errors {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			}
+			status = 1
+			continue
+		}
+
+		formatted := cfg.Print(program)
+		switch {
+		case *check:
+			if formatted != input {
+				fmt.Println(filename)
+				status = 1
+			}
+		case *write:
+			if formatted != input {
+				// The file keeps its permissions
+				info, err := os.Stat(filename)
+				if err == nil {
+					err = os.WriteFile(filename, []byte(formatted), info.Mode().Perm())
+				}
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					status = 1
+				}
+			}
+		default:
+			fmt.Print(formatted)
+		}
+	}
+	return status
+}
+
+// lintCommand reports the problems the lint rules find in files, and fails if there are any.
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	format := flags.String("format", "human", "output format, human or json")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: gojo lint [--format json] file.js...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || (*format != "human" && *format != "json") {
+		flags.Usage()
+		return 2
+	}
+
+	type fileDiagnostic struct {
+		File string `json:"file"`
+		lint.Diagnostic
+	}
+	diagnostics := []fileDiagnostic{}
+	status := 0
+	for _, filename := range flags.Args() {
+		input, err := readFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+			status = 1
+			continue
+		}
+		program, errors, err := parseFile(filename, input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			status = 1
+			continue
+		}
+		if len(errors) == 0 {
+			errors = parser.CheckEarlyErrors(program)
+		}
+		if len(errors) != 0 {
+			for _, err := range errors {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+			}
+			status = 1
+			continue
+		}
+
+		for _, diagnostic := range lint.Run(program, lint.DefaultRules) {
+			diagnostics = append(diagnostics, fileDiagnostic{filename, diagnostic})
+		}
+	}
+
+	if *format == "json" {
+		data, err := json.MarshalIndent(diagnostics, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, diagnostic := range diagnostics {
+			fmt.Printf("%s: %s\n", diagnostic.File, diagnostic.Diagnostic)
+		}
+	}
+	if len(diagnostics) != 0 {
+		status = 1
+	}
+	return status
+}
+
+// parseFile parses the content of a file: .mjs files are ES modules, .json files are ESTree ASTs and
+// anything else is a classic script.
+func parseFile(filename string, input string) (*parser.Program, []*parser.ParseError, error) {
+	if strings.HasSuffix(filename, ".json") {
+		program, err := estree.Unmarshal([]byte(input))
+		return program, nil, err
+	}
+
+	// Initialize lexer and parser
+	// ✋ Note: keep all instances of these as l, p and i
+	l := lexer.New(input)
+	p := parser.New(l)
+	if strings.HasSuffix(filename, ".mjs") {
+		program, errors := p.ParseModule()
+		return program, errors, nil
+	}
+	program, errors := p.ParseProgram()
+	return program, errors, nil
+}
+
 func printInput(input string) {
 	fmt.Println("╔═══ Input:")
 	fmt.Println(input)
@@ -65,13 +310,13 @@ func printProgramDetails(program *parser.Program) {
 	for _, stmt := range program.Statements {
 		fmt.Printf("    - %s\n", stmt)
 	}
-	fmt.Printf("  Start: %d\n", program.Start)
-	fmt.Printf("  End: %d\n", program.End)
+	fmt.Printf("  Start: %v\n", program.Start)
+	fmt.Printf("  End: %v\n", program.End)
 }
 
-func printParserErrors(p *parser.Parser) {
+func printParserErrors(errors []*parser.ParseError) {
 	fmt.Println("⚠️ Parser Errors:")
-	for _, err := range p.Errors() {
+	for _, err := range errors {
 		fmt.Println(err)
 	}
 }
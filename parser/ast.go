@@ -3,7 +3,9 @@ package parser
 import (
 	"fmt"
 	"gojo/lexer"
+	"math/big"
 	"strings"
+	"unicode"
 )
 
 /**
@@ -13,6 +15,7 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Location() Loc
 }
 
 type Statement interface {
@@ -25,15 +28,63 @@ type Expression interface {
 	expressionNode() // Placeholder method to distinguish expressions from statements
 }
 
+/**
+ * Source locations
+ */
+
+// Position is a point in the source: its offset, and its line and column counted from 1.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Loc is the source range of a node, from its first character to the position after its last one. It is
+// embedded in every node.
+type Loc struct {
+	Start Position
+	End   Position
+}
+
+// Location returns the source range of the node.
+func (l Loc) Location() Loc { return l }
+
+func (l *Loc) setLocation(loc Loc) { *l = loc }
+
+func (l Loc) String() string {
+	return l.Start.String() + "-" + l.End.String()
+}
+
 /**
  * AST Nodes
  */
 
+// SourceType is the goal a program is parsed with: a classic script or an ES module.
+type SourceType int
+
+const (
+	Script SourceType = iota
+	Module
+)
+
+func (st SourceType) String() string {
+	if st == Module {
+		return "module"
+	}
+	return "script"
+}
+
 // Program represents the root of the AST.
 type Program struct {
 	Statements []Statement
-	Start      int
-	End        int
+	SourceType SourceType
+	Strict     bool       // Whether the program is strict mode code, as modules always are
+	Comments   []*Comment // The comments of the source, in source order
+	Loc
 }
 
 func (p *Program) TokenLiteral() string {
@@ -52,35 +103,65 @@ func (p *Program) String() string {
 	return "Program(" + out.String() + ")"
 }
 
-// VariableDeclaration represents a variable declaration.
+// Comment represents a line or block comment (e.g., // note or /* note */), it is not a node of the tree.
+type Comment struct {
+	Loc
+	Token lexer.GojoToken
+	Text  string // The source text of the comment, including the delimiters
+}
+
+// VariableDeclaration represents a variable declaration (e.g., let a, b = 2).
 type VariableDeclaration struct {
-	Token      lexer.GojoToken
-	Name       *Identifier
-	Value      Expression
-	IsConstant bool // Whether the variable is a "const"
+	Loc
+	Token        lexer.GojoToken
+	Declarations []*VariableDeclarator
+	IsConstant   bool // Whether the variable is a "const"
 }
 
 func (vd *VariableDeclaration) statementNode()       {}
 func (vd *VariableDeclaration) TokenLiteral() string { return vd.Token.Text }
 func (vd *VariableDeclaration) String() string {
-	return fmt.Sprintf("VariableDeclaration(%s %s = %s)", vd.Token.Type.Label, vd.Name.String(), vd.Value.String())
+	var declarations []string
+	for _, d := range vd.Declarations {
+		declarations = append(declarations, d.String())
+	}
+	return fmt.Sprintf("VariableDeclaration(%s %s)", vd.Token.Type.Label, strings.Join(declarations, ", "))
 }
 
-// AssignmentExpression represents a variable assignment.
-type AssignmentExpression struct {
-	Token lexer.GojoToken // The token (=)
+// VariableDeclarator represents a single binding of a variable declaration (e.g., b = 2).
+type VariableDeclarator struct {
+	Loc
+	Token lexer.GojoToken // The identifier token
 	Name  *Identifier
-	Value Expression
+	Value Expression // nil when the binding has no initializer
+}
+
+func (vd *VariableDeclarator) TokenLiteral() string { return vd.Token.Text }
+func (vd *VariableDeclarator) String() string {
+	if vd.Value == nil {
+		return vd.Name.String()
+	}
+	return fmt.Sprintf("%s = %s", vd.Name.String(), vd.Value.String())
+}
+
+// AssignmentExpression represents an assignment to a variable or property (e.g., x = 1, obj.x += 1).
+type AssignmentExpression struct {
+	Loc
+	Token    lexer.GojoToken // The token (=, +=, ...)
+	Operator string
+	Left     Expression // An *Identifier or *MemberExpression
+	Value    Expression
 }
 
 func (ae *AssignmentExpression) expressionNode()      {}
 func (ae *AssignmentExpression) TokenLiteral() string { return ae.Token.Text }
 func (ae *AssignmentExpression) String() string {
-	return fmt.Sprintf("AssignmentExpression(%s = %s)", ae.Name.String(), ae.Value.String())
+	return fmt.Sprintf("AssignmentExpression(%s %s %s)", ae.Left.String(), ae.Operator, ae.Value.String())
 }
 
 // Identifier represents a variable name.
 type Identifier struct {
+	Loc
 	Token lexer.GojoToken
 	Value string
 }
@@ -91,20 +172,35 @@ func (i *Identifier) String() string {
 	return fmt.Sprintf("Identifier(%s)", i.Value)
 }
 
-// IntegerLiteral represents an integer.
+// IntegerLiteral represents a number literal, whose value is a double like any number's.
 type IntegerLiteral struct {
+	Loc
 	Token lexer.GojoToken
-	Value int64
+	Value float64
 }
 
 func (il *IntegerLiteral) expressionNode()      {}
 func (il *IntegerLiteral) TokenLiteral() string { return il.Token.Text }
 func (il *IntegerLiteral) String() string {
-	return fmt.Sprintf("IntegerLiteral(%d)", il.Value)
+	return fmt.Sprintf("IntegerLiteral(%v)", il.Value)
+}
+
+// BigIntLiteral represents a BigInt (e.g., 10n or 0xffn), its token text keeps the n suffix.
+type BigIntLiteral struct {
+	Loc
+	Token lexer.GojoToken
+	Value *big.Int
+}
+
+func (bl *BigIntLiteral) expressionNode()      {}
+func (bl *BigIntLiteral) TokenLiteral() string { return bl.Token.Text }
+func (bl *BigIntLiteral) String() string {
+	return fmt.Sprintf("BigIntLiteral(%s)", bl.Value)
 }
 
 // StringLiteral represents a string.
 type StringLiteral struct {
+	Loc
 	Token lexer.GojoToken
 	Value string
 }
@@ -115,8 +211,73 @@ func (sl *StringLiteral) String() string {
 	return fmt.Sprintf("StringLiteral(\"%s\")", sl.Value)
 }
 
+// RegExpLiteral represents a regular expression literal (e.g., /ab+c/gi).
+type RegExpLiteral struct {
+	Loc
+	Token   lexer.GojoToken
+	Pattern string // The source text between the slashes
+	Flags   string
+}
+
+func (rl *RegExpLiteral) expressionNode()      {}
+func (rl *RegExpLiteral) TokenLiteral() string { return rl.Token.Text }
+func (rl *RegExpLiteral) String() string {
+	return fmt.Sprintf("RegExpLiteral(/%s/%s)", rl.Pattern, rl.Flags)
+}
+
+// TemplateLiteral represents a template literal (e.g., `a${b}c`), its quasis surround the substitutions.
+type TemplateLiteral struct {
+	Loc
+	Token       lexer.GojoToken
+	Quasis      []*TemplateElement // One more than the expressions
+	Expressions []Expression
+}
+
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Text }
+func (tl *TemplateLiteral) String() string {
+	var out strings.Builder
+	for idx, quasi := range tl.Quasis {
+		out.WriteString(quasi.Raw)
+		if idx < len(tl.Expressions) {
+			out.WriteString("${" + tl.Expressions[idx].String() + "}")
+		}
+	}
+	return "TemplateLiteral(`" + out.String() + "`)"
+}
+
+// TemplateElement represents a chunk of a template literal. Its cooked value is nil when it has an invalid
+// escape, which only tagged templates allow.
+type TemplateElement struct {
+	Loc
+	Token  lexer.GojoToken
+	Raw    string
+	Cooked *string
+	Tail   bool // Whether it is the last chunk
+}
+
+func (te *TemplateElement) TokenLiteral() string { return te.Token.Text }
+func (te *TemplateElement) String() string {
+	return fmt.Sprintf("TemplateElement(%q)", te.Raw)
+}
+
+// TaggedTemplateExpression represents a template literal following a tag function (e.g., sql`a${b}`).
+type TaggedTemplateExpression struct {
+	Loc
+	Token lexer.GojoToken
+	Tag   Expression
+	Quasi *TemplateLiteral
+}
+
+func (tt *TaggedTemplateExpression) expressionNode()      {}
+func (tt *TaggedTemplateExpression) TokenLiteral() string { return tt.Token.Text }
+func (tt *TaggedTemplateExpression) String() string {
+	return fmt.Sprintf("TaggedTemplateExpression(%s %s)", tt.Tag.String(), tt.Quasi.String())
+}
+
 // BooleanLiteral represents a boolean.
 type BooleanLiteral struct {
+	Loc
 	Token lexer.GojoToken
 	Value bool
 }
@@ -129,6 +290,7 @@ func (bl *BooleanLiteral) String() string {
 
 // NullLiteral represents a null value.
 type NullLiteral struct {
+	Loc
 	Token lexer.GojoToken
 }
 
@@ -140,6 +302,7 @@ func (nl *NullLiteral) String() string {
 
 // UndefinedLiteral represents an undefined value.
 type UndefinedLiteral struct {
+	Loc
 	Token lexer.GojoToken
 }
 
@@ -151,6 +314,7 @@ func (ul *UndefinedLiteral) String() string {
 
 // ArrayLiteral represents an array.
 type ArrayLiteral struct {
+	Loc
 	Token    lexer.GojoToken
 	Elements []Expression
 }
@@ -165,21 +329,51 @@ func (al *ArrayLiteral) String() string {
 	return fmt.Sprintf("ArrayLiteral(%s)", strings.Join(elements, ", "))
 }
 
-// ArrayAccessExpression represents an array access expression (e.g., arr[0]).
-type ArrayAccessExpression struct {
-	Token lexer.GojoToken
-	Left  Expression // The array being accessed
-	Index Expression // The index being accessed
+// ObjectLiteral represents an object initializer (e.g., { a: 1, "b": 2, [c]: 3, d }).
+type ObjectLiteral struct {
+	Loc
+	Token      lexer.GojoToken // The token "{"
+	Properties []*Property
 }
 
-func (aae *ArrayAccessExpression) expressionNode()      {}
-func (aae *ArrayAccessExpression) TokenLiteral() string { return aae.Token.Text }
-func (aae *ArrayAccessExpression) String() string {
-	return fmt.Sprintf("ArrayAccessExpression(%s[%s])", aae.Left.String(), aae.Index.String())
+func (ol *ObjectLiteral) expressionNode()      {}
+func (ol *ObjectLiteral) TokenLiteral() string { return ol.Token.Text }
+func (ol *ObjectLiteral) String() string {
+	var properties []string
+	for _, property := range ol.Properties {
+		properties = append(properties, property.String())
+	}
+	return fmt.Sprintf("ObjectLiteral(%s)", strings.Join(properties, ", "))
+}
+
+// Property represents a single property of an object literal.
+type Property struct {
+	Loc
+	Token     lexer.GojoToken // The first token of the key
+	Key       Expression      // An *Identifier, *StringLiteral, *IntegerLiteral or *BigIntLiteral unless computed
+	Value     Expression
+	Computed  bool // Whether the key is a bracketed expression (e.g., [key]: value)
+	Shorthand bool // Whether the value is implied by the key (e.g., { a } for { a: a })
+	Method    bool // Whether the value is a *FunctionExpression written as a method (e.g., { *gen() {} })
+}
+
+func (pr *Property) TokenLiteral() string { return pr.Token.Text }
+func (pr *Property) String() string {
+	if pr.Shorthand {
+		return pr.Key.String()
+	}
+	if pr.Method {
+		return fmt.Sprintf("method %s: %s", pr.Key.String(), pr.Value.String())
+	}
+	if pr.Computed {
+		return fmt.Sprintf("[%s]: %s", pr.Key.String(), pr.Value.String())
+	}
+	return fmt.Sprintf("%s: %s", pr.Key.String(), pr.Value.String())
 }
 
 // BinaryExpression represents a binary operation.
 type BinaryExpression struct {
+	Loc
 	Token    lexer.GojoToken
 	Left     Expression
 	Operator string
@@ -192,21 +386,72 @@ func (be *BinaryExpression) String() string {
 	return fmt.Sprintf("BinaryExpression(%s %s %s)", be.Left.String(), be.Operator, be.Right.String())
 }
 
-// MemberAccessExpression represents a member access expression (e.g., obj.property).
-type MemberAccessExpression struct {
-	Token    lexer.GojoToken // The token (e.g., ".")
+// MemberExpression represents a property access, either static (e.g., obj.property) or computed
+// (e.g., obj["property"], arr[0]).
+type MemberExpression struct {
+	Loc
+	Token    lexer.GojoToken // The token (e.g., "." or "[")
 	Object   Expression      // The object being accessed
-	Property *Identifier     // The property being accessed
+	Property Expression      // An *Identifier when not computed, otherwise any expression
+	Computed bool            // Whether the property is a bracketed expression
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Text }
+func (me *MemberExpression) String() string {
+	if me.Computed {
+		return fmt.Sprintf("MemberExpression(%s[%s])", me.Object.String(), me.Property.String())
+	}
+	return fmt.Sprintf("MemberExpression(%s.%s)", me.Object.String(), me.Property.String())
+}
+
+// NewExpression represents a constructor call (e.g., new a.b.C(x)), the arguments are optional.
+type NewExpression struct {
+	Loc
+	Token     lexer.GojoToken // The token "new"
+	Callee    Expression
+	Arguments []Expression
+}
+
+func (ne *NewExpression) expressionNode()      {}
+func (ne *NewExpression) TokenLiteral() string { return ne.Token.Text }
+func (ne *NewExpression) String() string {
+	var args []string
+	for _, arg := range ne.Arguments {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("NewExpression(%s(args=%s))", ne.Callee.String(), strings.Join(args, ", "))
+}
+
+// ThisExpression represents the this keyword.
+type ThisExpression struct {
+	Loc
+	Token lexer.GojoToken
+}
+
+func (te *ThisExpression) expressionNode()      {}
+func (te *ThisExpression) TokenLiteral() string { return te.Token.Text }
+func (te *ThisExpression) String() string {
+	return "ThisExpression()"
 }
 
-func (mae *MemberAccessExpression) expressionNode()      {}
-func (mae *MemberAccessExpression) TokenLiteral() string { return mae.Token.Text }
-func (mae *MemberAccessExpression) String() string {
-	return fmt.Sprintf("MemberAccessExpression(%s.%s)", mae.Object.String(), mae.Property.String())
+// MetaProperty represents a keyword followed by a property (e.g., new.target).
+type MetaProperty struct {
+	Loc
+	Token    lexer.GojoToken // The keyword token, e.g., "new"
+	Meta     *Identifier
+	Property *Identifier
+}
+
+func (mp *MetaProperty) expressionNode()      {}
+func (mp *MetaProperty) TokenLiteral() string { return mp.Token.Text }
+func (mp *MetaProperty) String() string {
+	return fmt.Sprintf("MetaProperty(%s.%s)", mp.Meta.Value, mp.Property.Value)
 }
 
 // CallExpression represents a function call.
 type CallExpression struct {
+	Loc
 	Token     lexer.GojoToken
 	Function  Expression
 	Arguments []Expression
@@ -224,6 +469,7 @@ func (ce *CallExpression) String() string {
 
 // BlockStatement represents a block of statements.
 type BlockStatement struct {
+	Loc
 	Token      lexer.GojoToken
 	Statements []Statement
 }
@@ -240,24 +486,177 @@ func (bs *BlockStatement) String() string {
 
 // FunctionDeclaration represents a function declaration.
 type FunctionDeclaration struct {
+	Loc
 	Token      lexer.GojoToken
 	Name       *Identifier
 	Parameters []*Identifier
 	Body       *BlockStatement
+	Generator  bool // Whether it is declared with function*
+	Async      bool // Whether it is declared with async function
+	Strict     bool // Whether the function is strict mode code
 }
 
 func (fd *FunctionDeclaration) statementNode()       {}
 func (fd *FunctionDeclaration) TokenLiteral() string { return fd.Token.Text }
 func (fd *FunctionDeclaration) String() string {
+	return fmt.Sprintf("FunctionDeclaration(%s)", functionString(fd.Name, fd.Parameters, fd.Body, fd.Generator,
+		fd.Async))
+}
+
+// FunctionExpression represents a function used as a value, the name is optional (e.g., function* (a) {}).
+type FunctionExpression struct {
+	Loc
+	Token      lexer.GojoToken
+	Name       *Identifier // nil for anonymous functions
+	Parameters []*Identifier
+	Body       *BlockStatement
+	Generator  bool // Whether it is declared with function*
+	Async      bool // Whether it is declared with async function
+	Strict     bool // Whether the function is strict mode code
+}
+
+func (fe *FunctionExpression) expressionNode()      {}
+func (fe *FunctionExpression) TokenLiteral() string { return fe.Token.Text }
+func (fe *FunctionExpression) String() string {
+	return fmt.Sprintf("FunctionExpression(%s)", functionString(fe.Name, fe.Parameters, fe.Body, fe.Generator,
+		fe.Async))
+}
+
+// ArrowFunctionExpression represents an arrow function (e.g., (a, b) => a + b or async x => { ... }).
+type ArrowFunctionExpression struct {
+	Loc
+	Token      lexer.GojoToken // The token "=>"
+	Parameters []*Identifier
+	Body       Node // A *BlockStatement, or an Expression for concise bodies
+	Async      bool
+	Strict     bool // Whether the function is strict mode code
+}
+
+func (af *ArrowFunctionExpression) expressionNode()      {}
+func (af *ArrowFunctionExpression) TokenLiteral() string { return af.Token.Text }
+func (af *ArrowFunctionExpression) String() string {
+	var params []string
+	for _, param := range af.Parameters {
+		params = append(params, param.String())
+	}
+	async := ""
+	if af.Async {
+		async = "async "
+	}
+	return fmt.Sprintf("ArrowFunctionExpression(%s(%s) => %s)", async, strings.Join(params, ", "),
+		af.Body.String())
+}
+
+// AwaitExpression represents an await inside an async function.
+type AwaitExpression struct {
+	Loc
+	Token    lexer.GojoToken // The token "await"
+	Argument Expression
+}
+
+func (ae *AwaitExpression) expressionNode()      {}
+func (ae *AwaitExpression) TokenLiteral() string { return ae.Token.Text }
+func (ae *AwaitExpression) String() string {
+	return fmt.Sprintf("AwaitExpression(%s)", ae.Argument.String())
+}
+
+func functionString(name *Identifier, parameters []*Identifier, body *BlockStatement, generator bool,
+	async bool) string {
+	var out strings.Builder
+	if async {
+		out.WriteString("async ")
+	}
+	if generator {
+		out.WriteString("*")
+	}
+	if name != nil {
+		out.WriteString(name.String())
+	}
 	var params []string
-	for _, param := range fd.Parameters {
+	for _, param := range parameters {
 		params = append(params, param.String())
 	}
-	return fmt.Sprintf("FunctionDeclaration(%s(%s) %s)", fd.Name.String(), strings.Join(params, ", "), fd.Body.String())
+	out.WriteString(fmt.Sprintf("(%s) %s", strings.Join(params, ", "), body.String()))
+	return out.String()
+}
+
+// YieldExpression represents a yield inside a generator, delegating to another iterable with yield*.
+type YieldExpression struct {
+	Loc
+	Token    lexer.GojoToken // The token "yield"
+	Argument Expression      // nil when nothing is yielded explicitly
+	Delegate bool            // Whether it is a yield*
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Text }
+func (ye *YieldExpression) String() string {
+	keyword := "yield"
+	if ye.Delegate {
+		keyword = "yield*"
+	}
+	if ye.Argument == nil {
+		return fmt.Sprintf("YieldExpression(%s)", keyword)
+	}
+	return fmt.Sprintf("YieldExpression(%s %s)", keyword, ye.Argument.String())
+}
+
+// ThrowStatement represents a throw statement.
+type ThrowStatement struct {
+	Loc
+	Token    lexer.GojoToken
+	Argument Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Text }
+func (ts *ThrowStatement) String() string {
+	return fmt.Sprintf("ThrowStatement(%s)", ts.Argument.String())
+}
+
+// TryStatement represents a try statement with a catch clause, a finally block, or both.
+type TryStatement struct {
+	Loc
+	Token     lexer.GojoToken
+	Block     *BlockStatement
+	Handler   *CatchClause    // nil without a catch clause
+	Finalizer *BlockStatement // nil without a finally block
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Text }
+func (ts *TryStatement) String() string {
+	var out strings.Builder
+	out.WriteString("TryStatement(" + ts.Block.String())
+	if ts.Handler != nil {
+		out.WriteString(" " + ts.Handler.String())
+	}
+	if ts.Finalizer != nil {
+		out.WriteString(" finally " + ts.Finalizer.String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
+// CatchClause represents the catch clause of a try statement, the parameter is optional.
+type CatchClause struct {
+	Loc
+	Token lexer.GojoToken
+	Param *Identifier // nil for catch clauses without a binding (e.g., catch { ... })
+	Body  *BlockStatement
+}
+
+func (cc *CatchClause) TokenLiteral() string { return cc.Token.Text }
+func (cc *CatchClause) String() string {
+	if cc.Param == nil {
+		return "catch " + cc.Body.String()
+	}
+	return fmt.Sprintf("catch (%s) %s", cc.Param.String(), cc.Body.String())
 }
 
 // IfStatement represents an if-else statement.
 type IfStatement struct {
+	Loc
 	Token       lexer.GojoToken
 	Condition   Expression
 	Consequence *BlockStatement
@@ -276,6 +675,7 @@ func (is *IfStatement) String() string {
 
 // WhileStatement represents a while loop.
 type WhileStatement struct {
+	Loc
 	Token     lexer.GojoToken
 	Condition Expression
 	Body      *BlockStatement
@@ -289,8 +689,10 @@ func (ws *WhileStatement) String() string {
 
 // ExpressionStatement represents a statement consisting of a single expression.
 type ExpressionStatement struct {
+	Loc
 	Token      lexer.GojoToken // The first token of the expression
 	Expression Expression
+	Directive  string // The text of the string literal for a directive of a prologue (e.g., use strict)
 }
 
 func (es *ExpressionStatement) statementNode()       {}
@@ -301,6 +703,7 @@ func (es *ExpressionStatement) String() string {
 
 // PrefixExpression represents a prefix operation (e.g., !true).
 type PrefixExpression struct {
+	Loc
 	Token    lexer.GojoToken // The prefix token, e.g., "!"
 	Operator string          // The operator, e.g., "!"
 	Right    Expression      // The expression to the right of the operator
@@ -309,15 +712,70 @@ type PrefixExpression struct {
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Text }
 func (pe *PrefixExpression) String() string {
+	// Keyword operators (typeof, delete, ...) need a separator
+	if unicode.IsLetter(rune(pe.Operator[0])) {
+		return fmt.Sprintf("(%s %s)", pe.Operator, pe.Right.String())
+	}
 	return fmt.Sprintf("(%s%s)", pe.Operator, pe.Right.String())
 }
 
-// SwitchStatement represents a switch statement.
+// UpdateExpression represents an increment or decrement (e.g., ++x, x--).
+type UpdateExpression struct {
+	Loc
+	Token    lexer.GojoToken // The operator token, e.g., "++"
+	Operator string
+	Prefix   bool
+	Argument Expression
+}
+
+func (ue *UpdateExpression) expressionNode()      {}
+func (ue *UpdateExpression) TokenLiteral() string { return ue.Token.Text }
+func (ue *UpdateExpression) String() string {
+	if ue.Prefix {
+		return fmt.Sprintf("UpdateExpression(%s%s)", ue.Operator, ue.Argument.String())
+	}
+	return fmt.Sprintf("UpdateExpression(%s%s)", ue.Argument.String(), ue.Operator)
+}
+
+// ConditionalExpression represents a ternary operation (e.g., a ? b : c).
+type ConditionalExpression struct {
+	Loc
+	Token      lexer.GojoToken // The token "?"
+	Test       Expression
+	Consequent Expression
+	Alternate  Expression
+}
+
+func (ce *ConditionalExpression) expressionNode()      {}
+func (ce *ConditionalExpression) TokenLiteral() string { return ce.Token.Text }
+func (ce *ConditionalExpression) String() string {
+	return fmt.Sprintf("ConditionalExpression(%s ? %s : %s)", ce.Test.String(), ce.Consequent.String(),
+		ce.Alternate.String())
+}
+
+// SequenceExpression represents comma separated expressions (e.g., a, b).
+type SequenceExpression struct {
+	Loc
+	Token       lexer.GojoToken // The first "," token
+	Expressions []Expression
+}
+
+func (se *SequenceExpression) expressionNode()      {}
+func (se *SequenceExpression) TokenLiteral() string { return se.Token.Text }
+func (se *SequenceExpression) String() string {
+	var expressions []string
+	for _, e := range se.Expressions {
+		expressions = append(expressions, e.String())
+	}
+	return fmt.Sprintf("SequenceExpression(%s)", strings.Join(expressions, ", "))
+}
+
+// SwitchStatement represents a switch statement, its case clauses share one block scope.
 type SwitchStatement struct {
-	Token       lexer.GojoToken
-	Expression  Expression
-	Cases       []*CaseClause
-	DefaultCase *CaseClause
+	Loc
+	Token      lexer.GojoToken
+	Expression Expression
+	Cases      []*CaseClause // In source order, including the default clause
 }
 
 func (ss *SwitchStatement) statementNode()       {}
@@ -330,19 +788,16 @@ func (ss *SwitchStatement) String() string {
 	for _, cc := range ss.Cases {
 		out.WriteString(cc.String())
 	}
-	if ss.DefaultCase != nil {
-		out.WriteString("Default: ")
-		out.WriteString(ss.DefaultCase.String())
-	}
 	out.WriteString(")")
 	return out.String()
 }
 
-// CaseClause represents a case clause in a switch statement.
+// CaseClause represents a case clause in a switch statement, or the default clause when it has no condition.
 type CaseClause struct {
-	Token     lexer.GojoToken
-	Condition Expression
-	Body      *BlockStatement
+	Loc
+	Token      lexer.GojoToken
+	Condition  Expression // nil for the default clause
+	Consequent []Statement
 }
 
 func (cc *CaseClause) statementNode()       {}
@@ -356,28 +811,150 @@ func (cc *CaseClause) String() string {
 		out.WriteString("DefaultCaseClause(")
 	}
 	out.WriteString("Body(")
-	if cc.Body != nil {
-		for _, stmt := range cc.Body.Statements {
-			out.WriteString(stmt.String())
-		}
+	for _, stmt := range cc.Consequent {
+		out.WriteString(stmt.String())
 	}
 	out.WriteString(")) ")
 	return out.String()
 }
 
-// BreakStatement represents a switch break statement.
+// ForOfStatement represents a for...of loop, or a for await...of loop consuming an async iterable.
+type ForOfStatement struct {
+	Loc
+	Token lexer.GojoToken // The token "for"
+	Left  Node            // A *VariableDeclaration without initializer, or an assignment target
+	Right Expression
+	Body  Statement
+	Await bool
+}
+
+func (fs *ForOfStatement) statementNode()       {}
+func (fs *ForOfStatement) TokenLiteral() string { return fs.Token.Text }
+func (fs *ForOfStatement) String() string {
+	await := ""
+	if fs.Await {
+		await = "await "
+	}
+	return fmt.Sprintf("ForOfStatement(%s%s of %s %s)", await, fs.Left.String(), fs.Right.String(),
+		fs.Body.String())
+}
+
+// ForStatement represents a for loop with an initialization, a test and an update, any of which may be missing.
+type ForStatement struct {
+	Loc
+	Token  lexer.GojoToken // The token "for"
+	Init   Node            // A *VariableDeclaration or an expression
+	Test   Expression
+	Update Expression
+	Body   Statement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Text }
+func (fs *ForStatement) String() string {
+	var init, test, update string
+	if fs.Init != nil {
+		init = fs.Init.String()
+	}
+	if fs.Test != nil {
+		test = fs.Test.String()
+	}
+	if fs.Update != nil {
+		update = fs.Update.String()
+	}
+	return fmt.Sprintf("ForStatement(%s; %s; %s %s)", init, test, update, fs.Body.String())
+}
+
+// ForInStatement represents a for...in loop over the enumerable property names of an object.
+type ForInStatement struct {
+	Loc
+	Token lexer.GojoToken // The token "for"
+	Left  Node            // A *VariableDeclaration without initializer, or an assignment target
+	Right Expression
+	Body  Statement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Text }
+func (fs *ForInStatement) String() string {
+	return fmt.Sprintf("ForInStatement(%s in %s %s)", fs.Left.String(), fs.Right.String(), fs.Body.String())
+}
+
+// WithStatement represents a with statement, which adds the properties of an object to the scope of its body.
+type WithStatement struct {
+	Loc
+	Token  lexer.GojoToken // The token "with"
+	Object Expression
+	Body   Statement
+}
+
+func (ws *WithStatement) statementNode()       {}
+func (ws *WithStatement) TokenLiteral() string { return ws.Token.Text }
+func (ws *WithStatement) String() string {
+	return fmt.Sprintf("WithStatement(%s %s)", ws.Object.String(), ws.Body.String())
+}
+
+// EmptyStatement represents a lone semicolon, e.g., the body of for (;;);.
+type EmptyStatement struct {
+	Loc
+	Token lexer.GojoToken
+}
+
+func (es *EmptyStatement) statementNode()       {}
+func (es *EmptyStatement) TokenLiteral() string { return es.Token.Text }
+func (es *EmptyStatement) String() string {
+	return "EmptyStatement()"
+}
+
+// BreakStatement represents a break out of a loop or a switch, or out of a labeled statement.
 type BreakStatement struct {
+	Loc
 	Token lexer.GojoToken
+	Label *Identifier // nil unless the break targets a label
 }
 
 func (bs *BreakStatement) statementNode()       {}
 func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Text }
 func (bs *BreakStatement) String() string {
-	return "BreakStatement()"
+	if bs.Label == nil {
+		return "BreakStatement()"
+	}
+	return fmt.Sprintf("BreakStatement(%s)", bs.Label.String())
+}
+
+// ContinueStatement represents a continue with the next iteration of a loop, possibly a labeled one.
+type ContinueStatement struct {
+	Loc
+	Token lexer.GojoToken
+	Label *Identifier // nil unless the continue targets a label
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Text }
+func (cs *ContinueStatement) String() string {
+	if cs.Label == nil {
+		return "ContinueStatement()"
+	}
+	return fmt.Sprintf("ContinueStatement(%s)", cs.Label.String())
+}
+
+// LabeledStatement represents a statement with a label that break and continue can target (e.g., outer: while ...).
+type LabeledStatement struct {
+	Loc
+	Token lexer.GojoToken // The label token
+	Label *Identifier
+	Body  Statement
+}
+
+func (ls *LabeledStatement) statementNode()       {}
+func (ls *LabeledStatement) TokenLiteral() string { return ls.Token.Text }
+func (ls *LabeledStatement) String() string {
+	return fmt.Sprintf("LabeledStatement(%s: %s)", ls.Label.String(), ls.Body.String())
 }
 
 // ReturnStatement represents a function/body return statement.
 type ReturnStatement struct {
+	Loc
 	Token lexer.GojoToken
 	Value Expression
 }
@@ -385,5 +962,159 @@ type ReturnStatement struct {
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Text }
 func (rs *ReturnStatement) String() string {
+	if rs.Value == nil {
+		return "ReturnStatement()"
+	}
 	return fmt.Sprintf("ReturnStatement(%s)", rs.Value.String())
 }
+
+// ImportDeclaration represents an import declaration (e.g., import a, { b as c } from "mod").
+type ImportDeclaration struct {
+	Loc
+	Token      lexer.GojoToken
+	Specifiers []Node // *ImportDefaultSpecifier, *ImportNamespaceSpecifier or *ImportSpecifier
+	Source     *StringLiteral
+}
+
+func (id *ImportDeclaration) statementNode()       {}
+func (id *ImportDeclaration) TokenLiteral() string { return id.Token.Text }
+func (id *ImportDeclaration) String() string {
+	if len(id.Specifiers) == 0 {
+		return fmt.Sprintf("ImportDeclaration(%s)", id.Source.String())
+	}
+	return fmt.Sprintf("ImportDeclaration(%s from %s)", joinNodes(id.Specifiers), id.Source.String())
+}
+
+// ImportDefaultSpecifier represents the default binding of an import (e.g., a in import a from "mod").
+type ImportDefaultSpecifier struct {
+	Loc
+	Token lexer.GojoToken
+	Local *Identifier
+}
+
+func (ids *ImportDefaultSpecifier) TokenLiteral() string { return ids.Token.Text }
+func (ids *ImportDefaultSpecifier) String() string {
+	return fmt.Sprintf("ImportDefaultSpecifier(%s)", ids.Local.String())
+}
+
+// ImportNamespaceSpecifier represents a namespace import (e.g., * as ns).
+type ImportNamespaceSpecifier struct {
+	Loc
+	Token lexer.GojoToken // The "*" token
+	Local *Identifier
+}
+
+func (ins *ImportNamespaceSpecifier) TokenLiteral() string { return ins.Token.Text }
+func (ins *ImportNamespaceSpecifier) String() string {
+	return fmt.Sprintf("ImportNamespaceSpecifier(%s)", ins.Local.String())
+}
+
+// ImportSpecifier represents a named import (e.g., b or b as c).
+type ImportSpecifier struct {
+	Loc
+	Token    lexer.GojoToken
+	Imported *Identifier // The name exported by the module, any identifier name (e.g., default)
+	Local    *Identifier // The binding, the same identifier as Imported when there is no "as"
+}
+
+func (is *ImportSpecifier) TokenLiteral() string { return is.Token.Text }
+func (is *ImportSpecifier) String() string {
+	return fmt.Sprintf("ImportSpecifier(%s)", specifierString(is.Imported, is.Local))
+}
+
+// ExportNamedDeclaration represents the export of a declaration (e.g., export const a = 1) or of
+// a list of names, possibly re-exported from another module (e.g., export { a as b } from "mod").
+type ExportNamedDeclaration struct {
+	Loc
+	Token       lexer.GojoToken
+	Declaration Statement // nil when names are exported
+	Specifiers  []*ExportSpecifier
+	Source      *StringLiteral // nil unless the names are re-exported
+}
+
+func (en *ExportNamedDeclaration) statementNode()       {}
+func (en *ExportNamedDeclaration) TokenLiteral() string { return en.Token.Text }
+func (en *ExportNamedDeclaration) String() string {
+	if en.Declaration != nil {
+		return fmt.Sprintf("ExportNamedDeclaration(%s)", en.Declaration.String())
+	}
+	var specifiers []Node
+	for _, specifier := range en.Specifiers {
+		specifiers = append(specifiers, specifier)
+	}
+	if en.Source == nil {
+		return fmt.Sprintf("ExportNamedDeclaration({%s})", joinNodes(specifiers))
+	}
+	return fmt.Sprintf("ExportNamedDeclaration({%s} from %s)", joinNodes(specifiers), en.Source.String())
+}
+
+// ExportSpecifier represents a name in an export list (e.g., a or a as b).
+type ExportSpecifier struct {
+	Loc
+	Token    lexer.GojoToken
+	Local    *Identifier // The exported binding, or the name exported by the source module of a re-export
+	Exported *Identifier // The name the module exports it as, the same identifier as Local when there is no "as"
+}
+
+func (es *ExportSpecifier) TokenLiteral() string { return es.Token.Text }
+func (es *ExportSpecifier) String() string {
+	return fmt.Sprintf("ExportSpecifier(%s)", specifierString(es.Local, es.Exported))
+}
+
+// ExportDefaultDeclaration represents the default export of a module (e.g., export default a + 1).
+type ExportDefaultDeclaration struct {
+	Loc
+	Token       lexer.GojoToken
+	Declaration Node // A *FunctionDeclaration, possibly without a name, or an Expression
+}
+
+func (ed *ExportDefaultDeclaration) statementNode()       {}
+func (ed *ExportDefaultDeclaration) TokenLiteral() string { return ed.Token.Text }
+func (ed *ExportDefaultDeclaration) String() string {
+	return fmt.Sprintf("ExportDefaultDeclaration(%s)", ed.Declaration.String())
+}
+
+// ExportAllDeclaration represents the re-export of all names of a module (e.g., export * as ns from "mod").
+type ExportAllDeclaration struct {
+	Loc
+	Token    lexer.GojoToken
+	Exported *Identifier // nil unless the names are exported as a namespace
+	Source   *StringLiteral
+}
+
+func (ea *ExportAllDeclaration) statementNode()       {}
+func (ea *ExportAllDeclaration) TokenLiteral() string { return ea.Token.Text }
+func (ea *ExportAllDeclaration) String() string {
+	if ea.Exported == nil {
+		return fmt.Sprintf("ExportAllDeclaration(* from %s)", ea.Source.String())
+	}
+	return fmt.Sprintf("ExportAllDeclaration(* as %s from %s)", ea.Exported.String(), ea.Source.String())
+}
+
+// ImportExpression represents a dynamic import (e.g., import("./mod.js")).
+type ImportExpression struct {
+	Loc
+	Token  lexer.GojoToken
+	Source Expression
+}
+
+func (ie *ImportExpression) expressionNode()      {}
+func (ie *ImportExpression) TokenLiteral() string { return ie.Token.Text }
+func (ie *ImportExpression) String() string {
+	return fmt.Sprintf("ImportExpression(%s)", ie.Source.String())
+}
+
+func specifierString(name *Identifier, alias *Identifier) string {
+	if name.Value == alias.Value {
+		return name.String()
+	}
+	return fmt.Sprintf("%s as %s", name.String(), alias.String())
+}
+
+func joinNodes(nodes []Node) string {
+	var parts []string
+	for _, node := range nodes {
+		parts = append(parts, node.String())
+	}
+	return strings.Join(parts, ", ")
+}
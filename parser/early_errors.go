@@ -0,0 +1,580 @@
+package parser
+
+import (
+	"fmt"
+	"gojo/lexer"
+	"sort"
+)
+
+// CheckEarlyErrors reports the early errors of a program: the errors the ECMAScript specification
+// requires to be reported before any code runs, but which depend on more than the tokens around them,
+// e.g., redeclarations, labels and misplaced break, continue or return statements.
+// The program is expected to be free of syntax errors, the errors are returned in source order.
+func CheckEarlyErrors(program *Program) []*ParseError {
+	c := &earlyErrorChecker{strict: program.Strict, module: program.SourceType == Module}
+
+	scope := c.enterScope(true, nil)
+	c.declareStatements(program.Statements, !c.module)
+	for _, stmt := range program.Statements {
+		c.checkStatement(stmt)
+	}
+	if c.module {
+		c.checkExports(program.Statements, scope)
+	}
+
+	sort.SliceStable(c.errors, func(a, b int) bool { return c.errors[a].Offset < c.errors[b].Offset })
+	return c.errors
+}
+
+// declarationScope holds the names declared by a block, a function body or the program. It is not the
+// scope package, which imports this one, and which merges a name declared twice into one declaration
+// where the checker must tell the kinds of the two apart to report the conflict.
+type declarationScope struct {
+	parent     *declarationScope
+	function   bool                   // Whether var declarations stop at this scope
+	lexical    map[string]*Identifier // let, const, import and block-level function bindings
+	functions  map[string]bool        // The lexical names bound by plain function declarations
+	vars       map[string]*Identifier // var bindings declared in this scope or in a nested block
+	parameters map[string]bool        // Function parameters or the catch parameter, they conflict with lexical names
+}
+
+// label is a label of an enclosing labeled statement.
+type label struct {
+	name      string
+	iteration bool // Whether it labels a loop, so continue can target it
+}
+
+// jumpContext is what break, continue, return and new.target depend on, functions start a fresh one.
+type jumpContext struct {
+	labels     []label
+	iterations int  // The number of enclosing loops
+	breakables int  // The number of enclosing loops and switch statements
+	function   bool // Whether the code is in a function body, so return is allowed
+	newTarget  bool // Whether the code is in a non-arrow function body, so new.target is allowed
+}
+
+type earlyErrorChecker struct {
+	errors []*ParseError
+	strict bool
+	module bool
+	scope  *declarationScope
+	jumps  jumpContext
+}
+
+func (c *earlyErrorChecker) errorAt(token lexer.GojoToken, message string) {
+	c.errors = append(c.errors, newParseError(token, "", message))
+}
+
+func (c *earlyErrorChecker) enterScope(function bool, parameters []*Identifier) *declarationScope {
+	c.scope = &declarationScope{
+		parent:     c.scope,
+		function:   function,
+		lexical:    map[string]*Identifier{},
+		functions:  map[string]bool{},
+		vars:       map[string]*Identifier{},
+		parameters: map[string]bool{},
+	}
+	for _, parameter := range parameters {
+		c.scope.parameters[parameter.Value] = true
+	}
+	return c.scope
+}
+
+func (c *earlyErrorChecker) leaveScope() {
+	c.scope = c.scope.parent
+}
+
+// declareStatements declares the lexical bindings of a list of statements in the current scope. Function
+// declarations bind var-like names at the top level of scripts and function bodies, lexical ones elsewhere.
+func (c *earlyErrorChecker) declareStatements(statements []Statement, varFunctions bool) {
+	var functions []*Identifier
+	for _, stmt := range statements {
+		switch stmt := stmt.(type) {
+		case *ExportNamedDeclaration:
+			if stmt.Declaration != nil {
+				c.declareStatements([]Statement{stmt.Declaration}, varFunctions)
+			}
+		case *ExportDefaultDeclaration:
+			if function, ok := stmt.Declaration.(*FunctionDeclaration); ok && function.Name != nil {
+				c.declareLexical(function.Name, false)
+			}
+		case *ImportDeclaration:
+			for _, specifier := range stmt.Specifiers {
+				switch specifier := specifier.(type) {
+				case *ImportDefaultSpecifier:
+					c.declareLexical(specifier.Local, false)
+				case *ImportNamespaceSpecifier:
+					c.declareLexical(specifier.Local, false)
+				case *ImportSpecifier:
+					c.declareLexical(specifier.Local, false)
+				}
+			}
+		case *VariableDeclaration:
+			if stmt.Token.Type.Label != "var" {
+				for _, declarator := range stmt.Declarations {
+					c.declareLexical(declarator.Name, false)
+				}
+			}
+		case *FunctionDeclaration, *LabeledStatement:
+			// A labeled function declaration is declared like an unlabeled one
+			function := labeledFunction(stmt)
+			if function == nil {
+				continue
+			}
+			if varFunctions {
+				functions = append(functions, function.Name)
+			} else {
+				c.declareLexical(function.Name, !function.Generator && !function.Async)
+			}
+		}
+	}
+
+	// Var-like functions conflict with the lexical names of the whole list, declared first
+	for _, name := range functions {
+		c.declareVar(name)
+	}
+}
+
+// declareLexical declares a let, const, import or block-level function binding, only a
+// plain function declaration in a block of sloppy mode code may bind the same name twice.
+func (c *earlyErrorChecker) declareLexical(name *Identifier, plainFunction bool) {
+	scope := c.scope
+	if previous, ok := scope.lexical[name.Value]; ok {
+		sloppyFunctions := plainFunction && scope.functions[name.Value] && !c.strict && !scope.function
+		if !sloppyFunctions {
+			c.redeclarationError(previous, name)
+		}
+		return
+	}
+	if previous, ok := scope.vars[name.Value]; ok {
+		c.redeclarationError(previous, name)
+		return
+	}
+	if scope.parameters[name.Value] {
+		c.errorAt(name.Token, fmt.Sprintf("identifier '%s' has already been declared", name.Value))
+		return
+	}
+	scope.lexical[name.Value] = name
+	scope.functions[name.Value] = plainFunction
+}
+
+// declareVar declares a var binding, in every scope up to the enclosing function, where
+// it conflicts with the lexical bindings.
+func (c *earlyErrorChecker) declareVar(name *Identifier) {
+	for scope := c.scope; scope != nil; scope = scope.parent {
+		if previous, ok := scope.lexical[name.Value]; ok {
+			c.redeclarationError(previous, name)
+			return
+		}
+		if _, ok := scope.vars[name.Value]; !ok {
+			scope.vars[name.Value] = name
+		}
+		if scope.function {
+			return
+		}
+	}
+}
+
+// redeclarationError reports the later of two conflicting declarations.
+func (c *earlyErrorChecker) redeclarationError(first *Identifier, second *Identifier) {
+	if first.Token.Start > second.Token.Start {
+		first, second = second, first
+	}
+	c.errorAt(second.Token, fmt.Sprintf("identifier '%s' has already been declared", second.Value))
+}
+
+func (c *earlyErrorChecker) checkBlock(statements []Statement) {
+	c.enterScope(false, nil)
+	c.declareStatements(statements, false)
+	for _, stmt := range statements {
+		c.checkStatement(stmt)
+	}
+	c.leaveScope()
+}
+
+func (c *earlyErrorChecker) checkStatement(stmt Statement) {
+	switch stmt := stmt.(type) {
+	case *VariableDeclaration:
+		for _, declarator := range stmt.Declarations {
+			if stmt.Token.Type.Label == "var" {
+				c.declareVar(declarator.Name)
+			}
+			c.checkExpression(declarator.Value)
+		}
+	case *FunctionDeclaration:
+		c.checkFunction(stmt.Parameters, stmt.Body, false)
+	case *BlockStatement:
+		c.checkBlock(stmt.Statements)
+	case *ExpressionStatement:
+		c.checkExpression(stmt.Expression)
+	case *IfStatement:
+		c.checkExpression(stmt.Condition)
+		c.checkBlock(stmt.Consequence.Statements)
+		if stmt.Alternative != nil {
+			c.checkBlock(stmt.Alternative.Statements)
+		}
+	case *WhileStatement:
+		c.checkExpression(stmt.Condition)
+		c.checkLoopBody(stmt.Body)
+	case *ForStatement:
+		c.enterScope(false, nil)
+		switch init := stmt.Init.(type) {
+		case *VariableDeclaration:
+			c.checkForHead(stmt.Token, init)
+		case Expression:
+			c.checkExpression(init)
+		}
+		c.checkExpression(stmt.Test)
+		c.checkExpression(stmt.Update)
+		c.checkLoopBody(stmt.Body)
+		c.leaveScope()
+	case *ForInStatement:
+		c.enterScope(false, nil)
+		c.checkForHead(stmt.Token, stmt.Left)
+		c.checkExpression(stmt.Right)
+		c.checkLoopBody(stmt.Body)
+		c.leaveScope()
+	case *ForOfStatement:
+		c.enterScope(false, nil)
+		c.checkForHead(stmt.Token, stmt.Left)
+		c.checkExpression(stmt.Right)
+		c.checkLoopBody(stmt.Body)
+		c.leaveScope()
+	case *SwitchStatement:
+		c.checkSwitchStatement(stmt)
+	case *TryStatement:
+		c.checkBlock(stmt.Block.Statements)
+		if stmt.Handler != nil {
+			var parameters []*Identifier
+			if stmt.Handler.Param != nil {
+				parameters = append(parameters, stmt.Handler.Param)
+			}
+			c.enterScope(false, parameters)
+			c.declareStatements(stmt.Handler.Body.Statements, false)
+			for _, s := range stmt.Handler.Body.Statements {
+				c.checkStatement(s)
+			}
+			c.leaveScope()
+		}
+		if stmt.Finalizer != nil {
+			c.checkBlock(stmt.Finalizer.Statements)
+		}
+	case *ThrowStatement:
+		c.checkExpression(stmt.Argument)
+	case *ReturnStatement:
+		if !c.jumps.function {
+			c.errorAt(stmt.Token, "illegal return statement")
+		}
+		c.checkExpression(stmt.Value)
+	case *WithStatement:
+		c.checkExpression(stmt.Object)
+		c.checkSingleStatement(stmt.Body, false)
+	case *LabeledStatement:
+		c.checkLabeledStatement(stmt)
+	case *BreakStatement:
+		c.checkBreakStatement(stmt)
+	case *ContinueStatement:
+		c.checkContinueStatement(stmt)
+	case *ExportNamedDeclaration:
+		if stmt.Declaration != nil {
+			c.checkStatement(stmt.Declaration)
+		}
+	case *ExportDefaultDeclaration:
+		switch declaration := stmt.Declaration.(type) {
+		case *FunctionDeclaration:
+			c.checkFunction(declaration.Parameters, declaration.Body, false)
+		case Expression:
+			c.checkExpression(declaration)
+		}
+	}
+}
+
+// checkSingleStatement checks the body of a statement that is not a block, where declarations are
+// not allowed: only sloppy mode code may label a plain function declaration.
+func (c *earlyErrorChecker) checkSingleStatement(stmt Statement, labeled bool) {
+	switch body := stmt.(type) {
+	case *VariableDeclaration:
+		if body.Token.Type.Label != "var" {
+			c.errorAt(body.Token, "lexical declaration cannot appear in a single-statement context")
+			return
+		}
+	case *FunctionDeclaration:
+		if !labeled || c.strict || body.Generator || body.Async {
+			c.errorAt(body.Token, "function declarations are not allowed in a single-statement context")
+			return
+		}
+	}
+	c.checkStatement(stmt)
+}
+
+func (c *earlyErrorChecker) checkLoopBody(body Statement) {
+	c.jumps.iterations++
+	c.jumps.breakables++
+	if block, ok := body.(*BlockStatement); ok {
+		c.checkBlock(block.Statements)
+	} else {
+		c.checkSingleStatement(body, false)
+	}
+	c.jumps.iterations--
+	c.jumps.breakables--
+}
+
+// checkForHead checks the declaration of a for loop, or the target of a for...in or for...of loop, in the scope of
+// the loop where its let and const bindings go.
+func (c *earlyErrorChecker) checkForHead(token lexer.GojoToken, left Node) {
+	switch left := left.(type) {
+	case *VariableDeclaration:
+		for _, declarator := range left.Declarations {
+			if left.Token.Type.Label == "var" {
+				c.declareVar(declarator.Name)
+			} else {
+				c.declareLexical(declarator.Name, false)
+			}
+			c.checkExpression(declarator.Value)
+		}
+	case Expression:
+		c.checkAssignmentTarget(token, left)
+		c.checkExpression(left)
+	}
+}
+
+// checkSwitchStatement checks a switch statement, its case clauses form a single block.
+func (c *earlyErrorChecker) checkSwitchStatement(stmt *SwitchStatement) {
+	c.checkExpression(stmt.Expression)
+
+	var statements []Statement
+	for _, clause := range stmt.Cases {
+		statements = append(statements, clause.Consequent...)
+	}
+
+	c.jumps.breakables++
+	c.enterScope(false, nil)
+	c.declareStatements(statements, false)
+	for _, clause := range stmt.Cases {
+		c.checkExpression(clause.Condition)
+		for _, s := range clause.Consequent {
+			c.checkStatement(s)
+		}
+	}
+	c.leaveScope()
+	c.jumps.breakables--
+}
+
+func (c *earlyErrorChecker) checkLabeledStatement(stmt *LabeledStatement) {
+	for _, l := range c.jumps.labels {
+		if l.name == stmt.Label.Value {
+			c.errorAt(stmt.Label.Token, fmt.Sprintf("label '%s' has already been declared", stmt.Label.Value))
+		}
+	}
+
+	labels := c.jumps.labels
+	c.jumps.labels = append(labels[:len(labels):len(labels)], label{
+		name:      stmt.Label.Value,
+		iteration: isIterationStatement(stmt.Body),
+	})
+	c.checkSingleStatement(stmt.Body, true)
+	c.jumps.labels = labels
+}
+
+func (c *earlyErrorChecker) checkBreakStatement(stmt *BreakStatement) {
+	if stmt.Label != nil {
+		if _, ok := c.findLabel(stmt.Label.Value); !ok {
+			c.errorAt(stmt.Label.Token, fmt.Sprintf("undefined label '%s'", stmt.Label.Value))
+		}
+		return
+	}
+	if c.jumps.breakables == 0 {
+		c.errorAt(stmt.Token, "illegal break statement")
+	}
+}
+
+func (c *earlyErrorChecker) checkContinueStatement(stmt *ContinueStatement) {
+	if stmt.Label != nil {
+		l, ok := c.findLabel(stmt.Label.Value)
+		switch {
+		case !ok:
+			c.errorAt(stmt.Label.Token, fmt.Sprintf("undefined label '%s'", stmt.Label.Value))
+		case !l.iteration:
+			c.errorAt(stmt.Token, fmt.Sprintf("illegal continue statement: '%s' does not denote an "+
+				"iteration statement", stmt.Label.Value))
+		}
+		return
+	}
+	if c.jumps.iterations == 0 {
+		c.errorAt(stmt.Token, "illegal continue statement: no surrounding iteration statement")
+	}
+}
+
+func (c *earlyErrorChecker) findLabel(name string) (label, bool) {
+	for _, l := range c.jumps.labels {
+		if l.name == name {
+			return l, true
+		}
+	}
+	return label{}, false
+}
+
+// checkFunction checks a function body, where labels and loops of the enclosing code cannot be targeted.
+func (c *earlyErrorChecker) checkFunction(parameters []*Identifier, body Node, arrow bool) {
+	jumps := c.jumps
+	c.jumps = jumpContext{function: true, newTarget: jumps.newTarget || !arrow}
+
+	c.enterScope(true, parameters)
+	switch body := body.(type) {
+	case *BlockStatement:
+		c.declareStatements(body.Statements, true)
+		for _, stmt := range body.Statements {
+			c.checkStatement(stmt)
+		}
+	case Expression:
+		c.checkExpression(body)
+	}
+	c.leaveScope()
+
+	c.jumps = jumps
+}
+
+func (c *earlyErrorChecker) checkExpression(expr Expression) {
+	switch expr := expr.(type) {
+	case *ArrayLiteral:
+		for _, element := range expr.Elements {
+			c.checkExpression(element)
+		}
+	case *ObjectLiteral:
+		c.checkObjectLiteral(expr)
+	case *AssignmentExpression:
+		c.checkAssignmentTarget(expr.Token, expr.Left)
+		c.checkExpression(expr.Left)
+		c.checkExpression(expr.Value)
+	case *UpdateExpression:
+		c.checkAssignmentTarget(expr.Token, expr.Argument)
+		c.checkExpression(expr.Argument)
+	case *BinaryExpression:
+		c.checkExpression(expr.Left)
+		c.checkExpression(expr.Right)
+	case *PrefixExpression:
+		c.checkExpression(expr.Right)
+	case *ConditionalExpression:
+		c.checkExpression(expr.Test)
+		c.checkExpression(expr.Consequent)
+		c.checkExpression(expr.Alternate)
+	case *SequenceExpression:
+		for _, e := range expr.Expressions {
+			c.checkExpression(e)
+		}
+	case *MemberExpression:
+		c.checkExpression(expr.Object)
+		if expr.Computed {
+			c.checkExpression(expr.Property)
+		}
+	case *CallExpression:
+		c.checkExpression(expr.Function)
+		for _, argument := range expr.Arguments {
+			c.checkExpression(argument)
+		}
+	case *TemplateLiteral:
+		for _, e := range expr.Expressions {
+			c.checkExpression(e)
+		}
+	case *TaggedTemplateExpression:
+		c.checkExpression(expr.Tag)
+		c.checkExpression(expr.Quasi)
+	case *NewExpression:
+		c.checkExpression(expr.Callee)
+		for _, argument := range expr.Arguments {
+			c.checkExpression(argument)
+		}
+	case *MetaProperty:
+		if expr.Meta.Value == "new" && !c.jumps.newTarget {
+			c.errorAt(expr.Token, "new.target expression is not allowed here")
+		}
+	case *FunctionExpression:
+		c.checkFunction(expr.Parameters, expr.Body, false)
+	case *ArrowFunctionExpression:
+		c.checkFunction(expr.Parameters, expr.Body, true)
+	case *AwaitExpression:
+		c.checkExpression(expr.Argument)
+	case *YieldExpression:
+		c.checkExpression(expr.Argument)
+	case *ImportExpression:
+		c.checkExpression(expr.Source)
+	}
+}
+
+// checkObjectLiteral reports a __proto__ property defined twice, it would set the prototype twice.
+func (c *earlyErrorChecker) checkObjectLiteral(expr *ObjectLiteral) {
+	proto := false
+	for _, property := range expr.Properties {
+		if property.Computed {
+			c.checkExpression(property.Key)
+		} else if !property.Shorthand && !property.Method && propertyKeyName(property.Key) == "__proto__" {
+			if proto {
+				c.errorAt(property.Token, "duplicate __proto__ fields are not allowed in object literals")
+			}
+			proto = true
+		}
+		c.checkExpression(property.Value)
+	}
+}
+
+// checkAssignmentTarget reports targets that cannot be assigned, only variables and properties can.
+// The error is positioned at the token of the assignment.
+func (c *earlyErrorChecker) checkAssignmentTarget(token lexer.GojoToken, target Expression) {
+	switch target.(type) {
+	case *Identifier, *MemberExpression:
+	default:
+		c.errorAt(token, fmt.Sprintf("invalid assignment target '%s'", target.String()))
+	}
+}
+
+// checkExports reports the names a module exports without declaring them.
+func (c *earlyErrorChecker) checkExports(statements []Statement, scope *declarationScope) {
+	for _, stmt := range statements {
+		export, ok := stmt.(*ExportNamedDeclaration)
+		if !ok || export.Source != nil {
+			continue
+		}
+		for _, specifier := range export.Specifiers {
+			name := specifier.Local.Value
+			if _, ok := scope.lexical[name]; ok {
+				continue
+			}
+			if _, ok := scope.vars[name]; ok {
+				continue
+			}
+			c.errorAt(specifier.Local.Token, fmt.Sprintf("export '%s' is not defined", name))
+		}
+	}
+}
+
+// labeledFunction returns the function declaration of a statement, possibly labeled, or nil.
+func labeledFunction(stmt Statement) *FunctionDeclaration {
+	switch stmt := stmt.(type) {
+	case *FunctionDeclaration:
+		return stmt
+	case *LabeledStatement:
+		return labeledFunction(stmt.Body)
+	}
+	return nil
+}
+
+// isIterationStatement reports whether a statement, possibly labeled, is a loop.
+func isIterationStatement(stmt Statement) bool {
+	switch stmt := stmt.(type) {
+	case *WhileStatement, *ForStatement, *ForInStatement, *ForOfStatement:
+		return true
+	case *LabeledStatement:
+		return isIterationStatement(stmt.Body)
+	}
+	return false
+}
+
+func propertyKeyName(key Expression) string {
+	switch key := key.(type) {
+	case *Identifier:
+		return key.Value
+	case *StringLiteral:
+		return key.Value
+	}
+	return ""
+}
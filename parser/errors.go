@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"fmt"
+	"gojo/lexer"
+)
+
+// ParseError describes a syntax error and where it was found in the source.
+type ParseError struct {
+	Line     int    // Line of the offending token
+	Column   int    // Column of the offending token
+	Offset   int    // Offset of the offending token in the input
+	Expected string // The token that was expected, empty if any token could have followed
+	Actual   string // The token that was found instead
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("SyntaxError (Line: %d, Column: %d): %s", e.Line, e.Column, e.Message)
+}
+
+// errorAt records a syntax error positioned at the given token.
+func (p *Parser) errorAt(token lexer.GojoToken, expected string, message string) {
+	p.errors = append(p.errors, newParseError(token, expected, message))
+}
+
+func newParseError(token lexer.GojoToken, expected string, message string) *ParseError {
+	return &ParseError{
+		Line:     token.Line,
+		Column:   token.Column,
+		Offset:   token.Start,
+		Expected: expected,
+		Actual:   describeToken(token),
+		Message:  message,
+	}
+}
+
+// unexpectedToken records an error for a token that cannot appear at its position.
+func (p *Parser) unexpectedToken(token lexer.GojoToken) {
+	switch {
+	case token.Type.Label == "illegal" && token.BadEscape:
+		p.errorAt(token, "", "invalid escape sequence")
+	case token.Type.Label == "illegal" && len(token.Text) > 1 && (token.Text[0] == '"' ||
+		token.Text[0] == '\''):
+		p.errorAt(token, "", "unterminated string literal")
+	case token.Type.Label == "illegal" && len(token.Text) > 0 && token.Text[0] == '`':
+		p.errorAt(token, "", "unterminated template literal")
+	case token.Type.Label == "illegal" && len(token.Text) > 1 && token.Text[0] == '/':
+		p.errorAt(token, "", "unterminated regular expression literal")
+	case token.Type.Label == "illegal":
+		p.errorAt(token, "", fmt.Sprintf("invalid or unexpected token %q", token.Text))
+	case token.Type.Label == "eof":
+		p.errorAt(token, "", "unexpected end of input")
+	default:
+		p.errorAt(token, "", fmt.Sprintf("unexpected token %s", describeToken(token)))
+	}
+}
+
+// synchronize skips tokens after an error until a statement boundary, so parsing can resume and
+// report further errors. It stops on a ';', right before a token that starts a statement, or on a '}'
+// closing an enclosing block, in which case it returns true. Skipped blocks are skipped as a whole.
+// The failed statement, which began with the start token, may also have run into a statement keyword such
+// as for on a later line, parsing then resumes on that statement and it returns true.
+func (p *Parser) synchronize(start lexer.GojoToken) bool {
+	if p.curToken.Start != start.Start && p.curToken.Line != p.prevToken.Line && startsStatement(p.curToken) {
+		return true
+	}
+	depth := 0
+	for {
+		switch p.curToken.Type.Label {
+		case "{":
+			depth++
+		case "}":
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case ";":
+			if depth == 0 {
+				return false
+			}
+		case "eof":
+			return false
+		}
+		if depth == 0 && (p.peekTokenIs("}") || p.peekTokenIs("eof") || startsStatement(p.peekToken)) {
+			return false
+		}
+		p.nextToken()
+	}
+}
+
+// startsStatement reports whether a token is a keyword starting a statement, or a clause of a switch.
+func startsStatement(token lexer.GojoToken) bool {
+	switch token.Type.Label {
+	case "var", "let", "const", "function", "import", "export", "if", "switch", "case", "default", "while", "for",
+		"do", "break", "continue", "return", "throw", "try", "with":
+		return true
+	}
+	return false
+}
+
+func describeToken(token lexer.GojoToken) string {
+	switch token.Type.Label {
+	case "eof":
+		return "end of input"
+	case "identifier", "number", "bigint", "string", "illegal":
+		return fmt.Sprintf("%s '%s'", token.Type.Label, token.Text)
+	default:
+		return fmt.Sprintf("'%s'", token.Text)
+	}
+}
+
+/**
+ * Error handling
+ */
+
+func (p *Parser) Errors() []*ParseError {
+	return p.errors
+}
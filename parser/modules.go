@@ -0,0 +1,391 @@
+package parser
+
+import (
+	"fmt"
+	"gojo/lexer"
+)
+
+// parseModuleItem parses a statement at the top level of a module, where import and export
+// declarations are allowed.
+func (p *Parser) parseModuleItem() Statement {
+	start := p.curToken
+	var stmt Statement
+	switch {
+	case p.curTokenIs("import") && !p.peekTokenIs("(") && !p.peekTokenIs("."):
+		stmt = asStatement(p.parseImportDeclaration())
+	case p.curTokenIs("export"):
+		stmt = p.parseExportDeclaration()
+	default:
+		return p.parseStatement()
+	}
+	if stmt != nil {
+		p.finish(stmt, start)
+	}
+	return stmt
+}
+
+// parseImportDeclaration parses the import forms: import "mod", import a from "mod",
+// import * as ns from "mod" and import { b, c as d } from "mod", a default binding may come
+// before a namespace import or a list of named imports.
+func (p *Parser) parseImportDeclaration() *ImportDeclaration {
+	decl := &ImportDeclaration{Token: p.curToken}
+
+	// An import without bindings only evaluates the module
+	if p.peekTokenIs("string") {
+		p.nextToken()
+		decl.Source = p.parseStringLiteral()
+		if p.peekTokenIs(";") {
+			p.nextToken()
+		}
+		return decl
+	}
+
+	namedBindings := true
+	if p.peekTokenIs("identifier") {
+		p.nextToken()
+		local := p.parseIdentifier()
+		p.checkBinding(local)
+		decl.Specifiers = append(decl.Specifiers, &ImportDefaultSpecifier{Token: local.Token, Local: local,
+			Loc: local.Loc})
+		namedBindings = p.peekTokenIs(",")
+		if namedBindings {
+			p.nextToken()
+		}
+	}
+
+	if namedBindings {
+		switch p.peekToken.Type.Label {
+		case "*":
+			p.nextToken()
+			token := p.curToken
+			if !p.expectContextual("as") || !p.expectPeek("identifier") {
+				return nil
+			}
+			local := p.parseIdentifier()
+			p.checkBinding(local)
+			specifier := &ImportNamespaceSpecifier{Token: token, Local: local}
+			p.finish(specifier, token)
+			decl.Specifiers = append(decl.Specifiers, specifier)
+		case "{":
+			p.nextToken()
+			specifiers, ok := p.parseImportSpecifiers()
+			if !ok {
+				return nil
+			}
+			decl.Specifiers = append(decl.Specifiers, specifiers...)
+		default:
+			p.errorAt(p.peekToken, "", fmt.Sprintf("expected import bindings, got %s instead",
+				describeToken(p.peekToken)))
+			return nil
+		}
+	}
+
+	source, ok := p.parseFromClause()
+	if !ok {
+		return nil
+	}
+	decl.Source = source
+
+	return decl
+}
+
+// parseImportSpecifiers parses the list of named imports, starting at "{".
+func (p *Parser) parseImportSpecifiers() ([]Node, bool) {
+	var specifiers []Node
+
+	for !p.peekTokenIs("}") {
+		if !isIdentifierName(p.peekToken) {
+			p.expectPeek("identifier")
+			return nil, false
+		}
+		p.nextToken()
+
+		specifier := &ImportSpecifier{Token: p.curToken, Imported: p.parseIdentifier()}
+		if p.peekContextual("as") {
+			p.nextToken()
+			if !p.expectPeek("identifier") {
+				return nil, false
+			}
+			specifier.Local = p.parseIdentifier()
+		} else {
+			// Without "as", the imported name is also the binding, so it cannot be a reserved word
+			if !p.curTokenIs("identifier") {
+				p.errorAt(p.curToken, "as", fmt.Sprintf("unexpected reserved word '%s' in import, "+
+					"use '%s as name'", p.curToken.Text, p.curToken.Text))
+				return nil, false
+			}
+			specifier.Local = specifier.Imported
+		}
+		p.checkBinding(specifier.Local)
+		p.finish(specifier, specifier.Token)
+		specifiers = append(specifiers, specifier)
+
+		if !p.peekTokenIs(",") {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek("}") {
+		return nil, false
+	}
+	return specifiers, true
+}
+
+// parseExportDeclaration parses the export forms: export of a declaration, of a list of names,
+// re-exports from another module and the default export.
+func (p *Parser) parseExportDeclaration() Statement {
+	token := p.curToken
+
+	switch p.peekToken.Type.Label {
+	case "default":
+		return asStatement(p.parseExportDefaultDeclaration(token))
+	case "*":
+		return asStatement(p.parseExportAllDeclaration(token))
+	case "{":
+		return asStatement(p.parseExportList(token))
+	}
+
+	p.nextToken()
+	start := p.curToken
+	decl := &ExportNamedDeclaration{Token: token}
+	switch {
+	case p.curTokenIs("var"), p.curTokenIs("let"), p.curTokenIs("const"):
+		declaration := p.parseVariableDeclarationStatement()
+		if declaration == nil {
+			return nil
+		}
+		for _, declarator := range declaration.Declarations {
+			p.addExport(declarator.Name.Token, declarator.Name.Value)
+		}
+		p.finish(declaration, start)
+		decl.Declaration = declaration
+	case p.curTokenIs("function"), p.isAsyncFunction():
+		async := p.isAsyncFunction()
+		if async {
+			p.nextToken()
+		}
+		declaration := p.parseFunctionDeclaration(async)
+		if declaration == nil {
+			return nil
+		}
+		p.addExport(declaration.Name.Token, declaration.Name.Value)
+		p.finish(declaration, start)
+		decl.Declaration = declaration
+	default:
+		p.errorAt(p.curToken, "", fmt.Sprintf("expected a declaration or '{' after export, got %s instead",
+			describeToken(p.curToken)))
+		return nil
+	}
+
+	return decl
+}
+
+// parseExportDefaultDeclaration parses export default followed by a function declaration, which may be
+// anonymous, or by an expression.
+func (p *Parser) parseExportDefaultDeclaration(token lexer.GojoToken) *ExportDefaultDeclaration {
+	decl := &ExportDefaultDeclaration{Token: token}
+	p.nextToken()
+	p.addExport(p.curToken, "default")
+	p.nextToken()
+
+	start := p.curToken
+	if p.curTokenIs("function") || p.isAsyncFunction() {
+		async := p.isAsyncFunction()
+		if async {
+			p.nextToken()
+		}
+		function, ok := p.parseFunctionExpression(async).(*FunctionExpression)
+		if !ok {
+			return nil
+		}
+		decl.Declaration = &FunctionDeclaration{
+			Token:      function.Token,
+			Name:       function.Name,
+			Parameters: function.Parameters,
+			Body:       function.Body,
+			Generator:  function.Generator,
+			Async:      function.Async,
+		}
+		p.finish(decl.Declaration, start)
+		return decl
+	}
+
+	expression := p.parseExpression(SEQUENCE)
+	if expression == nil {
+		return nil
+	}
+	decl.Declaration = expression
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return decl
+}
+
+// parseExportAllDeclaration parses export * from "mod" and export * as ns from "mod".
+func (p *Parser) parseExportAllDeclaration(token lexer.GojoToken) *ExportAllDeclaration {
+	decl := &ExportAllDeclaration{Token: token}
+	p.nextToken()
+
+	if p.peekContextual("as") {
+		p.nextToken()
+		if !isIdentifierName(p.peekToken) {
+			p.expectPeek("identifier")
+			return nil
+		}
+		p.nextToken()
+		decl.Exported = p.parseIdentifier()
+		p.addExport(p.curToken, decl.Exported.Value)
+	}
+
+	source, ok := p.parseFromClause()
+	if !ok {
+		return nil
+	}
+	decl.Source = source
+
+	return decl
+}
+
+// parseExportList parses export { a, b as c }, optionally followed by a module to re-export the names from.
+func (p *Parser) parseExportList(token lexer.GojoToken) *ExportNamedDeclaration {
+	decl := &ExportNamedDeclaration{Token: token}
+	p.nextToken()
+
+	for !p.peekTokenIs("}") {
+		if !isIdentifierName(p.peekToken) {
+			p.expectPeek("identifier")
+			return nil
+		}
+		p.nextToken()
+
+		specifier := &ExportSpecifier{Token: p.curToken, Local: p.parseIdentifier()}
+		specifier.Exported = specifier.Local
+		if p.peekContextual("as") {
+			p.nextToken()
+			if !isIdentifierName(p.peekToken) {
+				p.expectPeek("identifier")
+				return nil
+			}
+			p.nextToken()
+			specifier.Exported = p.parseIdentifier()
+		}
+		p.finish(specifier, specifier.Token)
+		p.addExport(specifier.Exported.Token, specifier.Exported.Value)
+		decl.Specifiers = append(decl.Specifiers, specifier)
+
+		if !p.peekTokenIs(",") {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek("}") {
+		return nil
+	}
+
+	if p.peekContextual("from") {
+		source, ok := p.parseFromClause()
+		if !ok {
+			return nil
+		}
+		decl.Source = source
+		return decl
+	}
+
+	// Names exported from the module itself must be bindings, re-exports may name anything
+	for _, specifier := range decl.Specifiers {
+		if specifier.Local.Token.Type.Label != "identifier" {
+			p.errorAt(specifier.Local.Token, "", fmt.Sprintf("unexpected reserved word '%s' in export list",
+				specifier.Local.Value))
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return decl
+}
+
+// parseFromClause parses the from "mod" ending an import or a re-export.
+func (p *Parser) parseFromClause() (*StringLiteral, bool) {
+	if !p.expectContextual("from") || !p.expectPeek("string") {
+		return nil, false
+	}
+	source := p.parseStringLiteral()
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return source, true
+}
+
+// parseImportExpression parses a dynamic import, import("mod"), or import.meta.
+func (p *Parser) parseImportExpression() Expression {
+	token := p.curToken
+
+	if p.peekTokenIs(".") {
+		p.nextToken()
+		if !p.expectPeek("identifier") {
+			return nil
+		}
+		if p.curToken.Text != "meta" {
+			p.errorAt(p.curToken, "meta", fmt.Sprintf("the only valid meta property for import is import.meta, "+
+				"got import.%s", p.curToken.Text))
+			return nil
+		}
+		if p.sourceType != Module {
+			p.errorAt(token, "", "import.meta may only appear in module code")
+			return nil
+		}
+		return p.parseMetaProperty(token)
+	}
+
+	if !p.expectPeek("(") {
+		return nil
+	}
+	p.nextToken()
+
+	expr := &ImportExpression{Token: token}
+	expr.Source = p.parseExpression(SEQUENCE)
+	if expr.Source == nil {
+		return nil
+	}
+	if p.peekTokenIs(",") {
+		p.nextToken()
+	}
+	if !p.expectPeek(")") {
+		return nil
+	}
+
+	return expr
+}
+
+// addExport records a name exported by the module, each name may only be exported once.
+func (p *Parser) addExport(token lexer.GojoToken, name string) {
+	if p.exports[name] {
+		p.errorAt(token, "", fmt.Sprintf("duplicate export '%s'", name))
+		return
+	}
+	p.exports[name] = true
+}
+
+// peekContextual reports whether the peek token is an identifier with a meaning in this position only,
+// e.g., as and from in import declarations.
+func (p *Parser) peekContextual(word string) bool {
+	return p.peekTokenIs("identifier") && p.peekToken.Text == word
+}
+
+func (p *Parser) expectContextual(word string) bool {
+	if p.peekContextual(word) {
+		p.nextToken()
+		return true
+	}
+	p.errorAt(p.peekToken, word, fmt.Sprintf("expected '%s', got %s instead", word, describeToken(p.peekToken)))
+	return false
+}
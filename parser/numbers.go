@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decimalLiteral matches the decimal numbers strings convert to, e.g. 1, -2.5, .5 or 1e3.
+var decimalLiteral = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// StringToNumber converts a string to a number as the StringToNumber operation does: surrounding whitespace
+// is ignored, and the string is NaN unless it is entirely a decimal, a hexadecimal, octal or binary integer,
+// or an infinity.
+func StringToNumber(text string) float64 {
+	text = strings.TrimSpace(text)
+	switch text {
+	case "":
+		return 0
+	case "Infinity", "+Infinity":
+		return math.Inf(1)
+	case "-Infinity":
+		return math.Inf(-1)
+	}
+	if len(text) > 2 && text[0] == '0' && strings.ContainsRune("xXoObB", rune(text[1])) {
+		if integer, ok := new(big.Int).SetString(text, 0); ok && !strings.Contains(text, "_") {
+			number, _ := new(big.Float).SetInt(integer).Float64()
+			return number
+		}
+		return math.NaN()
+	}
+	if !decimalLiteral.MatchString(text) {
+		return math.NaN()
+	}
+	// Numbers too large for a double are infinite, strconv reports them as out of range
+	number, _ := strconv.ParseFloat(text, 64)
+	return number
+}
+
+// NumberValue returns the value of the source text of a number literal. It is the string's number, except for
+// the legacy octal integers (e.g., 017), which are decimal when they contain an 8 or a 9 (e.g., 019).
+func NumberValue(text string) float64 {
+	if isLegacyOctalLike(text) && !strings.ContainsAny(text, "89") {
+		integer, _ := new(big.Int).SetString(text, 8)
+		number, _ := new(big.Float).SetInt(integer).Float64()
+		return number
+	}
+	return StringToNumber(text)
+}
@@ -4,25 +4,32 @@ import (
 	"fmt"
 	"gojo/config"
 	"gojo/lexer"
+	"gojo/regex"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
-// Precedence Levels
+// Precedence Levels (lowest to highest), following the ECMAScript expression grammar
 const (
 	LOWEST      = iota
-	ASSIGN      // =
-	CONDITIONAL // ?:
+	SEQUENCE    // ,
+	ASSIGN      // =, +=, -=, **=, &&=, ... (right-associative)
+	CONDITIONAL // ?: (right-associative)
+	COALESCE    // ??
 	LOGICAL_OR  // ||
 	LOGICAL_AND // &&
 	BITWISE_OR  // |
 	BITWISE_XOR // ^
 	BITWISE_AND // &
-	EQUALS      // ==, !=
-	COMPARISON  // <, >, <=, >=
-	SHIFT       // <<, >>
+	EQUALS      // ==, !=, ===, !==
+	COMPARISON  // <, >, <=, >=, in, instanceof
+	SHIFT       // <<, >>, >>>
 	SUM         // +, -
 	PRODUCT     // *, /, %
-	PREFIX      // -X, !X
+	EXPONENT    // ** (right-associative)
+	PREFIX      // -X, !X, ~X, typeof X, ++X
+	POSTFIX     // X++, X--
 	CALL        // myFunction(X)
 	MEMBER      // obj.property
 	INDEX       // array[index]
@@ -30,19 +37,28 @@ const (
 
 type Parser struct {
 	l             *lexer.Lexer
-	errors        []string
+	errors        []*ParseError
 	curLine       int
+	prevToken     lexer.GojoToken // The token before the current one, which ends nodes left on the next token
 	curToken      lexer.GojoToken
 	curTokenStart int
 	curTokenEnd   int
 	peekToken     lexer.GojoToken
+	inGenerator   bool // Whether the body of a generator function is being parsed
+	inAsync       bool // Whether the body of an async function is being parsed
+	sourceType    SourceType
+	strict        bool            // Whether strict mode code is being parsed
+	exports       map[string]bool // The names exported so far by the module being parsed
+	noIn          bool            // Whether "in" ends expressions, as it does in the head of a for loop
+	// The first directive with an octal escape in the prologue being parsed, an error if it turns strict
+	octalDirective *StringLiteral
 }
 
 func New(l *lexer.Lexer) *Parser {
 	sofToken := lexer.GojoToken{Type: lexer.TokenText["sof"], Text: "sof", Line: 0}
 	p := &Parser{
 		l:      l,
-		errors: []string{},
+		errors: []*ParseError{},
 		// Placeholders Tokens
 		curToken:  sofToken,
 		peekToken: sofToken,
@@ -57,10 +73,11 @@ func New(l *lexer.Lexer) *Parser {
 
 func (p *Parser) nextToken() {
 	var token = p.peekToken
+	p.prevToken = p.curToken
 	p.curToken = token
-	p.curTokenStart = p.l.Start
-	p.curTokenEnd = p.l.End
-	p.curLine = p.l.Line
+	p.curTokenStart = token.Start
+	p.curTokenEnd = token.End
+	p.curLine = token.Line
 
 	if config.LoadConfig().Verbose {
 		fmt.Println("╔═══ nextToken() ")
@@ -81,48 +98,165 @@ func (p *Parser) nextToken() {
  * Parsing functions
  */
 
-func (p *Parser) ParseProgram() *Program {
-	program := &Program{Start: 0}
+// ParseProgram parses the whole input as a script. Parsing resumes after syntax errors, so the returned
+// program may be partial: statements that failed to parse are left out and every error is returned.
+func (p *Parser) ParseProgram() (*Program, []*ParseError) {
+	return p.parseProgram(Script)
+}
+
+// ParseModule parses the whole input as an ES module: import and export declarations are allowed at the
+// top level, the code is strict and await may be used outside of async functions.
+func (p *Parser) ParseModule() (*Program, []*ParseError) {
+	p.strict = true
+	p.inAsync = true
+	p.exports = map[string]bool{}
+	return p.parseProgram(Module)
+}
+
+func (p *Parser) parseProgram(sourceType SourceType) (*Program, []*ParseError) {
+	p.sourceType = sourceType
+	program := &Program{SourceType: sourceType}
 	program.Statements = []Statement{}
 
+	prologue := true
+	p.startPrologue()
 	for p.curToken.Type.Label != "eof" {
-		stmt := p.parseStatement()
+		errorCount := len(p.errors)
+		start := p.curToken
+		var stmt Statement
+		if sourceType == Module {
+			stmt = p.parseModuleItem()
+		} else {
+			stmt = p.parseStatement()
+		}
+		prologue = prologue && p.applyDirective(stmt)
 		if stmt != nil {
 			if config.LoadConfig().Verbose {
 				fmt.Println("╚══ parseStatement():", stmt)
 			}
 			program.Statements = append(program.Statements, stmt)
 		}
+		// A statement that parsed despite errors inside of it ends where it should, only failed ones are skipped.
+		// A stray '}' is skipped too.
+		if len(p.errors) > errorCount && stmt == nil && p.synchronize(start) && !p.curTokenIs("}") {
+			continue
+		}
 		p.nextToken()
 	}
 
-	program.End = p.curTokenEnd
+	program.Loc = Loc{Start: Position{Offset: 0, Line: 1, Column: 1}, End: endPosition(p.curToken)}
+	program.Strict = p.strict
+	for _, token := range p.l.Comments {
+		program.Comments = append(program.Comments, &Comment{
+			Loc:   Loc{Start: startPosition(token), End: endPosition(token)},
+			Token: token,
+			Text:  token.Text,
+		})
+	}
 
-	return program
+	return program, p.errors
 }
 
+// parseStatement parses a statement and locates it from its first token to its last one.
 func (p *Parser) parseStatement() Statement {
+	start := p.curToken
+	stmt := p.parseStatementKind()
+	if stmt != nil {
+		p.finish(stmt, start)
+	}
+	return stmt
+}
+
+func (p *Parser) parseStatementKind() Statement {
 	switch p.curToken.Type.Label {
 	case "var", "let", "const":
-		return p.parseVariableDeclarationStatement()
+		return asStatement(p.parseVariableDeclarationStatement())
 	case "function":
-		return p.parseFunctionDeclaration()
+		return asStatement(p.parseFunctionDeclaration(false))
 	case "if":
-		return p.parseIfStatement()
+		return asStatement(p.parseIfStatement())
 	case "switch":
-		return p.parseSwitchStatement()
+		return asStatement(p.parseSwitchStatement())
 	case "while":
-		return p.parseWhileStatement()
+		return asStatement(p.parseWhileStatement())
+	case "for":
+		return p.parseForStatement()
 	case "break":
-		return p.parseBreakStatement()
+		return asStatement(p.parseBreakStatement())
+	case "continue":
+		return asStatement(p.parseContinueStatement())
+	case "throw":
+		return asStatement(p.parseThrowStatement())
+	case "try":
+		return asStatement(p.parseTryStatement())
+	case "with":
+		return asStatement(p.parseWithStatement())
+	case "return":
+		return asStatement(p.parseReturnStatement())
+	case "{":
+		return asStatement(p.parseBlockStatement())
+	case "import", "export":
+		// import( and import. start expressions, declarations belong to the top level of modules
+		if p.curTokenIs("import") && (p.peekTokenIs("(") || p.peekTokenIs(".")) {
+			return asStatement(p.parseExpressionStatement())
+		}
+		p.errorAt(p.curToken, "", fmt.Sprintf("%s declarations may only appear at top level of a module",
+			p.curToken.Text))
+		return nil
+	case ";":
+		return &EmptyStatement{Token: p.curToken}
 	default:
-		return p.parseExpressionStatement()
+		if p.isAsyncFunction() {
+			p.nextToken()
+			return asStatement(p.parseFunctionDeclaration(true))
+		}
+		if p.curTokenIs("identifier") && p.peekTokenIs(":") {
+			return asStatement(p.parseLabeledStatement())
+		}
+		return asStatement(p.parseExpressionStatement())
+	}
+}
+
+// asStatement avoids wrapping a nil node (from a failed parse) in a non-nil Statement.
+func asStatement[T any, PT interface {
+	*T
+	Statement
+}](stmt PT) Statement {
+	if stmt == nil {
+		return nil
+	}
+	return stmt
+}
+
+// finish locates a node from its start token to the current token, which is its last one. Nodes keep the
+// range they were first given, e.g., a parenthesized expression is located inside the parentheses.
+func (p *Parser) finish(node Node, start lexer.GojoToken) {
+	p.locate(node, start, p.curToken)
+}
+
+// locate sets the range of a node from its first token to its last token, if it has none yet.
+func (p *Parser) locate(node Node, start lexer.GojoToken, end lexer.GojoToken) {
+	located, ok := node.(interface{ setLocation(Loc) })
+	if !ok || node.Location().End.Line != 0 {
+		return
 	}
+	located.setLocation(Loc{Start: startPosition(start), End: endPosition(end)})
+}
+
+func startPosition(token lexer.GojoToken) Position {
+	return Position{Offset: token.Start, Line: token.Line, Column: token.Column}
+}
+
+func endPosition(token lexer.GojoToken) Position {
+	return Position{Offset: token.End, Line: token.EndLine, Column: token.EndColumn}
 }
 
 func (p *Parser) parseExpressionStatement() *ExpressionStatement {
 	stmt := &ExpressionStatement{Token: p.curToken}
 	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
 
 	if p.peekTokenIs(";") {
 		p.nextToken()
@@ -132,82 +266,283 @@ func (p *Parser) parseExpressionStatement() *ExpressionStatement {
 }
 
 func (p *Parser) parseVariableDeclarationStatement() *VariableDeclaration {
+	stmt := p.parseVariableDeclarationList(true)
+	if stmt == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseVariableDeclarationList parses the declarators of a declaration, stopping before the token following
+// them. const declarators must be initialized unless requireInitializer is false, as in the head of a for loop
+// where it depends on the kind of the loop.
+func (p *Parser) parseVariableDeclarationList(requireInitializer bool) *VariableDeclaration {
 	stmt := &VariableDeclaration{Token: p.curToken}
 
 	if p.curToken.Type.Label == "const" {
 		stmt.IsConstant = true
 	}
 
+	for {
+		declarator := p.parseVariableDeclarator(stmt.IsConstant && requireInitializer)
+		if declarator == nil {
+			return nil
+		}
+		stmt.Declarations = append(stmt.Declarations, declarator)
+
+		if !p.peekTokenIs(",") {
+			break
+		}
+		p.nextToken() // consume ','
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseVariableDeclarator(isConstant bool) *VariableDeclarator {
 	if !p.expectPeek("identifier") {
 		return nil
 	}
 
-	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Text}
+	declarator := &VariableDeclarator{Token: p.curToken}
+	declarator.Name = p.parseIdentifier()
+	p.checkBinding(declarator.Name)
 
-	if !p.expectPeek("=") {
-		return nil
+	// Uninitialized bindings are allowed for var and let only
+	if !p.peekTokenIs("=") {
+		if isConstant {
+			p.errorAt(p.peekToken, "=", fmt.Sprintf("missing initializer in const declaration '%s'",
+				declarator.Name.Value))
+			return nil
+		}
+		p.finish(declarator, declarator.Token)
+		return declarator
 	}
 
+	p.nextToken() // consume '='
 	p.nextToken()
 
-	stmt.Value = p.parseExpression(LOWEST)
-
-	if p.peekTokenIs(";") {
-		p.nextToken()
+	declarator.Value = p.parseExpression(SEQUENCE)
+	if declarator.Value == nil {
+		return nil
 	}
 
-	return stmt
+	p.finish(declarator, declarator.Token)
+	return declarator
+}
+
+// isAsyncFunction reports whether the current token is the async of an async function.
+// async is not a keyword, and no line terminator is allowed between async and function.
+func (p *Parser) isAsyncFunction() bool {
+	return p.curTokenIs("identifier") && p.curToken.Text == "async" && p.peekTokenIs("function") &&
+		p.peekToken.Line == p.curToken.Line
 }
 
-func (p *Parser) parseFunctionDeclaration() *FunctionDeclaration {
-	stmt := &FunctionDeclaration{Token: p.curToken}
+func (p *Parser) parseFunctionDeclaration(async bool) *FunctionDeclaration {
+	stmt := &FunctionDeclaration{Token: p.curToken, Async: async}
+
+	if p.peekTokenIs("*") {
+		p.nextToken()
+		stmt.Generator = true
+	}
 
 	if !p.expectPeek("identifier") {
 		return nil
 	}
 
-	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Text}
+	stmt.Name = p.parseIdentifier()
 
 	if !p.expectPeek("(") {
 		return nil
 	}
 
-	stmt.Parameters = p.parseFunctionParameters()
+	parameters, ok := p.parseFunctionParameters()
+	if !ok {
+		return nil
+	}
+	stmt.Parameters = parameters
 
 	if !p.expectPeek("{") {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	stmt.Body, stmt.Strict = p.parseFunctionBody(stmt.Generator, stmt.Async)
+	if stmt.Body == nil {
+		return nil
+	}
+	p.checkFunction(stmt.Name, stmt.Parameters, stmt.Strict, false)
 
 	return stmt
 }
 
-func (p *Parser) parseFunctionParameters() []*Identifier {
+func (p *Parser) parseFunctionExpression(async bool) Expression {
+	expr := &FunctionExpression{Token: p.curToken, Async: async}
+
+	if p.peekTokenIs("*") {
+		p.nextToken()
+		expr.Generator = true
+	}
+
+	if p.peekTokenIs("identifier") {
+		p.nextToken()
+		expr.Name = p.parseIdentifier()
+	}
+
+	if !p.expectPeek("(") {
+		return nil
+	}
+	return p.parseFunctionRest(expr)
+}
+
+// parseFunctionRest parses the parameters and body of a function expression or method, starting at "(".
+func (p *Parser) parseFunctionRest(expr *FunctionExpression) Expression {
+	parameters, ok := p.parseFunctionParameters()
+	if !ok {
+		return nil
+	}
+	expr.Parameters = parameters
+
+	if !p.expectPeek("{") {
+		return nil
+	}
+
+	expr.Body, expr.Strict = p.parseFunctionBody(expr.Generator, expr.Async)
+	if expr.Body == nil {
+		return nil
+	}
+	p.checkFunction(expr.Name, expr.Parameters, expr.Strict, false)
+
+	return expr
+}
+
+// parseFunctionBody parses the body of a function, yield is only allowed in the body of a generator
+// and await in the body of an async function. It also reports whether the function is strict: functions
+// in strict code are strict, others are made strict by a "use strict" directive starting their body.
+func (p *Parser) parseFunctionBody(generator bool, async bool) (*BlockStatement, bool) {
+	inGenerator, inAsync, strict := p.inGenerator, p.inAsync, p.strict
+	p.inGenerator, p.inAsync = generator, async
+	defer p.allowIn()()
+	body := p.parseBlock(true)
+	bodyStrict := p.strict
+	p.inGenerator, p.inAsync, p.strict = inGenerator, inAsync, strict
+	return body, bodyStrict
+}
+
+// parseArrowFunction parses the body of an arrow function, starting at "=>".
+func (p *Parser) parseArrowFunction(parameters []*Identifier, async bool) Expression {
+	expr := &ArrowFunctionExpression{Token: p.curToken, Parameters: parameters, Async: async}
+
+	if p.peekTokenIs("{") {
+		p.nextToken()
+		body, strict := p.parseFunctionBody(false, async)
+		if body == nil {
+			return nil
+		}
+		expr.Body, expr.Strict = body, strict
+		p.checkFunction(nil, expr.Parameters, expr.Strict, true)
+		return expr
+	}
+
+	// A concise body is a single expression, yield is never allowed in it
+	inGenerator, inAsync := p.inGenerator, p.inAsync
+	p.inGenerator, p.inAsync = false, async
+	p.nextToken()
+	body := p.parseExpression(SEQUENCE)
+	p.inGenerator, p.inAsync = inGenerator, inAsync
+	if body == nil {
+		return nil
+	}
+	expr.Body, expr.Strict = body, p.strict
+	p.checkFunction(nil, expr.Parameters, expr.Strict, true)
+	return expr
+}
+
+// arrowParameters converts the expressions parsed before an "=>" into the parameters of an arrow function.
+func (p *Parser) arrowParameters(expressions []Expression) ([]*Identifier, bool) {
+	parameters := []*Identifier{}
+	for _, expression := range expressions {
+		identifier, ok := expression.(*Identifier)
+		if !ok {
+			p.errorAt(p.curToken, "", fmt.Sprintf("invalid arrow function parameter %s", expression.String()))
+			return nil, false
+		}
+		parameters = append(parameters, identifier)
+	}
+	return parameters, true
+}
+
+// parseAsyncExpression parses the expressions starting with async: async functions and async arrows.
+// Otherwise async is an ordinary identifier, e.g. a call to a function named async.
+func (p *Parser) parseAsyncExpression() Expression {
+	async := p.parseIdentifier()
+	if p.peekToken.Line != p.curToken.Line {
+		return async
+	}
+
+	switch p.peekToken.Type.Label {
+	case "function":
+		p.nextToken()
+		return p.parseFunctionExpression(true)
+	case "identifier":
+		p.nextToken()
+		parameter := p.parseIdentifier()
+		if !p.expectPeek("=>") {
+			return nil
+		}
+		return p.parseArrowFunction([]*Identifier{parameter}, true)
+	case "(":
+		p.nextToken()
+		call := &CallExpression{Token: p.curToken, Function: async}
+		arguments, ok := p.parseExpressionList(")")
+		if !ok {
+			return nil
+		}
+		if !p.peekTokenIs("=>") {
+			call.Arguments = arguments
+			return call
+		}
+		parameters, ok := p.arrowParameters(arguments)
+		if !ok {
+			return nil
+		}
+		p.nextToken()
+		return p.parseArrowFunction(parameters, true)
+	}
+	return async
+}
+
+func (p *Parser) parseFunctionParameters() ([]*Identifier, bool) {
 	var identifiers []*Identifier
 
 	if p.peekTokenIs(")") {
 		p.nextToken()
-		return identifiers
+		return identifiers, true
 	}
 
-	p.nextToken()
+	if !p.expectPeek("identifier") {
+		return nil, false
+	}
 
-	identifier := &Identifier{Token: p.curToken, Value: p.curToken.Text}
-	identifiers = append(identifiers, identifier)
+	identifiers = append(identifiers, p.parseIdentifier())
 
 	for p.peekTokenIs(",") {
 		p.nextToken()
-		p.nextToken()
-		identifier := &Identifier{Token: p.curToken, Value: p.curToken.Text}
-		identifiers = append(identifiers, identifier)
+		if !p.expectPeek("identifier") {
+			return nil, false
+		}
+		identifiers = append(identifiers, p.parseIdentifier())
 	}
 
 	if !p.expectPeek(")") {
-		return nil
+		return nil, false
 	}
 
-	return identifiers
+	return identifiers, true
 }
 
 func (p *Parser) parseIfStatement() *IfStatement {
@@ -219,6 +554,9 @@ func (p *Parser) parseIfStatement() *IfStatement {
 
 	p.nextToken()
 	stmt.Condition = p.parseExpression(LOWEST)
+	if stmt.Condition == nil {
+		return nil
+	}
 
 	if !p.expectPeek(")") {
 		return nil
@@ -230,36 +568,28 @@ func (p *Parser) parseIfStatement() *IfStatement {
 
 	stmt.Consequence = p.parseBlockStatement()
 
-	for p.peekTokenIs("else") {
+	if p.peekTokenIs("else") {
 		p.nextToken() // consume 'else'
 
 		if p.peekTokenIs("if") {
 			p.nextToken() // consume 'if'
-			elseIfStmt := &IfStatement{Token: p.curToken}
-
-			if !p.expectPeek("(") {
-				return nil
-			}
-
-			p.nextToken()
-			elseIfStmt.Condition = p.parseExpression(LOWEST)
-
-			if !p.expectPeek(")") {
-				return nil
-			}
-
-			if !p.expectPeek("{") {
+			// The else if statement takes the rest of the chain, including the final else
+			elseIfStmt := p.parseIfStatement()
+			if elseIfStmt == nil {
 				return nil
 			}
-
-			elseIfStmt.Consequence = p.parseBlockStatement()
+			p.finish(elseIfStmt, elseIfStmt.Token)
 			stmt.Alternative = &BlockStatement{
 				Token:      p.curToken,
 				Statements: []Statement{elseIfStmt},
+				Loc:        elseIfStmt.Loc,
 			}
 		} else if p.peekTokenIs("{") {
 			p.nextToken() // consume '{'
 			stmt.Alternative = p.parseBlockStatement()
+		} else {
+			p.expectPeek("{")
+			return nil
 		}
 	}
 
@@ -267,19 +597,39 @@ func (p *Parser) parseIfStatement() *IfStatement {
 }
 
 func (p *Parser) parseBlockStatement() *BlockStatement {
+	return p.parseBlock(false)
+}
+
+// parseBlock parses the statements between braces. Function bodies start with a directive prologue.
+func (p *Parser) parseBlock(prologue bool) *BlockStatement {
 	block := &BlockStatement{Token: p.curToken}
 	block.Statements = []Statement{}
+	if prologue {
+		p.startPrologue()
+	}
 
 	p.nextToken()
 
 	for !p.curTokenIs("}") && p.curToken.Type.Label != "eof" {
+		errorCount := len(p.errors)
+		start := p.curToken
 		stmt := p.parseStatement()
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
+		prologue = prologue && p.applyDirective(stmt)
+		// The failed statement may have stopped on the closing brace of this block
+		if len(p.errors) > errorCount && stmt == nil && p.synchronize(start) {
+			continue
+		}
 		p.nextToken()
 	}
 
+	if p.curToken.Type.Label == "eof" {
+		p.errorAt(p.curToken, "}", "expected '}' to close the block")
+	}
+
+	p.finish(block, block.Token)
 	return block
 }
 
@@ -292,6 +642,9 @@ func (p *Parser) parseWhileStatement() *WhileStatement {
 
 	p.nextToken()
 	stmt.Condition = p.parseExpression(LOWEST)
+	if stmt.Condition == nil {
+		return nil
+	}
 
 	if !p.expectPeek(")") {
 		return nil
@@ -306,6 +659,171 @@ func (p *Parser) parseWhileStatement() *WhileStatement {
 	return stmt
 }
 
+// parseForStatement parses a for loop: a for...of or a for...in loop, or a loop with an initialization, a test and
+// an update. Outside brackets, "in" is not an operator in the head of the loop since it starts a for...in loop.
+func (p *Parser) parseForStatement() Statement {
+	token := p.curToken
+	await := false
+
+	if p.peekTokenIs("await") {
+		p.nextToken()
+		// The loop itself is well-formed, so parsing goes on after reporting the error
+		if !p.inAsync {
+			p.errorAt(p.curToken, "", "for await is only valid in async functions")
+		}
+		await = true
+	}
+
+	if !p.expectPeek("(") {
+		return nil
+	}
+	p.nextToken()
+
+	if p.curTokenIs(";") && !await {
+		return asStatement(p.parseForLoop(&ForStatement{Token: token}))
+	}
+
+	p.noIn = true
+	left := p.parseForHead()
+	p.noIn = false
+	if left == nil {
+		return nil
+	}
+
+	switch {
+	case p.peekTokenIs("identifier") && p.peekToken.Text == "of":
+		if !p.checkForInOfLeft(left, "for...of") {
+			return nil
+		}
+		p.nextToken()
+		stmt := &ForOfStatement{Token: token, Left: left, Await: await}
+		p.nextToken()
+		stmt.Right = p.parseExpression(SEQUENCE)
+		if stmt.Right == nil {
+			return nil
+		}
+		stmt.Body = p.parseForBody()
+		if stmt.Body == nil {
+			return nil
+		}
+		return stmt
+	case await:
+		p.errorAt(p.peekToken, "of", fmt.Sprintf("expected 'of' in for await loop, got %s",
+			describeToken(p.peekToken)))
+		return nil
+	case p.peekTokenIs("in"):
+		if !p.checkForInOfLeft(left, "for...in") {
+			return nil
+		}
+		p.nextToken()
+		stmt := &ForInStatement{Token: token, Left: left}
+		p.nextToken()
+		stmt.Right = p.parseExpression(LOWEST)
+		if stmt.Right == nil {
+			return nil
+		}
+		stmt.Body = p.parseForBody()
+		if stmt.Body == nil {
+			return nil
+		}
+		return stmt
+	}
+
+	// The loop itself is well-formed, so parsing goes on after reporting a missing initializer
+	if declaration, ok := left.(*VariableDeclaration); ok && declaration.IsConstant {
+		for _, declarator := range declaration.Declarations {
+			if declarator.Value == nil {
+				p.errorAt(declarator.Token, "=", fmt.Sprintf("missing initializer in const declaration '%s'",
+					declarator.Name.Value))
+			}
+		}
+	}
+	if !p.expectPeek(";") {
+		return nil
+	}
+	return asStatement(p.parseForLoop(&ForStatement{Token: token, Init: left}))
+}
+
+// parseForHead parses what precedes the first ";", "of" or "in" in the head of a for loop: a declaration or an
+// expression.
+func (p *Parser) parseForHead() Node {
+	switch p.curToken.Type.Label {
+	case "var", "let", "const":
+		declaration := p.parseVariableDeclarationList(false)
+		if declaration == nil {
+			return nil
+		}
+		p.finish(declaration, declaration.Token)
+		return declaration
+	}
+
+	// Stop before "of", which is an identifier and cannot continue the expression
+	expr := p.parseExpression(LOWEST)
+	if expr == nil {
+		return nil
+	}
+	return expr
+}
+
+// checkForInOfLeft checks the left side of a for...of or for...in loop: a single declaration without
+// initializer, or an assignment target. Parsing goes on after an invalid declaration since the loop is
+// well-formed.
+func (p *Parser) checkForInOfLeft(left Node, loop string) bool {
+	switch left := left.(type) {
+	case *VariableDeclaration:
+		if len(left.Declarations) != 1 {
+			p.errorAt(left.Token, "", fmt.Sprintf("only a single variable can be declared in the head of a %s "+
+				"loop", loop))
+		} else if left.Declarations[0].Value != nil {
+			p.errorAt(left.Declarations[0].Token, "", fmt.Sprintf("the variable of a %s loop may not have an "+
+				"initializer", loop))
+		}
+	case Expression:
+		if !isAssignmentTarget(left) {
+			p.errorAt(p.curToken, "", fmt.Sprintf("invalid left-hand side in %s loop: %s", loop, left.String()))
+			return false
+		}
+		p.checkAssignmentTarget(left)
+	}
+	return true
+}
+
+// parseForLoop parses the rest of a loop with an initialization, a test and an update, starting at the first ";"
+// of its head. Each part may be empty.
+func (p *Parser) parseForLoop(stmt *ForStatement) *ForStatement {
+	if !p.peekTokenIs(";") {
+		p.nextToken()
+		if stmt.Test = p.parseExpression(LOWEST); stmt.Test == nil {
+			return nil
+		}
+	}
+	if !p.expectPeek(";") {
+		return nil
+	}
+
+	if !p.peekTokenIs(")") {
+		p.nextToken()
+		if stmt.Update = p.parseExpression(LOWEST); stmt.Update == nil {
+			return nil
+		}
+	}
+
+	if stmt.Body = p.parseForBody(); stmt.Body == nil {
+		return nil
+	}
+	return stmt
+}
+
+// parseForBody parses the ")" closing the head of a for loop and the body of the loop.
+func (p *Parser) parseForBody() Statement {
+	if !p.expectPeek(")") {
+		return nil
+	}
+
+	p.nextToken()
+	return p.parseStatement()
+}
+
 func (p *Parser) parseSwitchStatement() *SwitchStatement {
 	stmt := &SwitchStatement{Token: p.curToken}
 
@@ -315,6 +833,9 @@ func (p *Parser) parseSwitchStatement() *SwitchStatement {
 
 	p.nextToken()
 	stmt.Expression = p.parseExpression(LOWEST)
+	if stmt.Expression == nil {
+		return nil
+	}
 
 	if !p.expectPeek(")") {
 		return nil
@@ -327,56 +848,203 @@ func (p *Parser) parseSwitchStatement() *SwitchStatement {
 	p.nextToken() // Consume '{'
 
 	stmt.Cases = []*CaseClause{}
+	hasDefault := false
 	for !p.curTokenIs("}") && p.curToken.Type.Label != "eof" {
-		if p.curTokenIs("case") {
-			caseClause := p.parseCaseClause()
-			if caseClause != nil {
-				stmt.Cases = append(stmt.Cases, caseClause)
+		if !p.curTokenIs("case") && !p.curTokenIs("default") {
+			p.errorAt(p.curToken, "case", fmt.Sprintf("expected 'case' or 'default', got %s instead",
+				describeToken(p.curToken)))
+			return nil
+		}
+		caseClause := p.parseCaseClause()
+		if caseClause == nil {
+			return nil
+		}
+		if caseClause.Condition == nil {
+			if hasDefault {
+				p.errorAt(caseClause.Token, "", "more than one default clause in switch statement")
+				return nil
 			}
-		} else if p.curTokenIs("default") {
-			stmt.DefaultCase = p.parseDefaultCaseClause()
+			hasDefault = true
 		}
-		p.nextToken()
+		stmt.Cases = append(stmt.Cases, caseClause)
+	}
+
+	if p.curToken.Type.Label == "eof" {
+		p.errorAt(p.curToken, "}", "expected '}' to close the block")
 	}
 
 	return stmt
 }
 
+// parseCaseClause parses a case or default clause. Its statements run up to the next clause or the end of
+// the switch statement, where the parser is left.
 func (p *Parser) parseCaseClause() *CaseClause {
 	caseClause := &CaseClause{Token: p.curToken}
 
-	p.nextToken() // Move to the expression
-
-	caseClause.Condition = p.parseExpression(LOWEST)
-
-	if !p.expectPeek(":") {
-		return nil
+	if p.curTokenIs("case") {
+		p.nextToken() // Move to the expression
+		caseClause.Condition = p.parseExpression(LOWEST)
+		if caseClause.Condition == nil {
+			return nil
+		}
 	}
 
-	p.nextToken() // Consume ':'
-
-	caseClause.Body = p.parseBlockStatement()
-
-	return caseClause
-}
-
-func (p *Parser) parseDefaultCaseClause() *CaseClause {
-	caseClause := &CaseClause{Token: p.curToken}
-	caseClause.Condition = &BooleanLiteral{Token: p.curToken, Value: true} // Default case
-
 	if !p.expectPeek(":") {
 		return nil
 	}
 
 	p.nextToken() // Consume ':'
 
-	caseClause.Body = p.parseBlockStatement()
+	caseClause.Consequent = []Statement{}
+	for !p.curTokenIs("case") && !p.curTokenIs("default") && !p.curTokenIs("}") &&
+		p.curToken.Type.Label != "eof" {
+		errorCount := len(p.errors)
+		start := p.curToken
+		stmt := p.parseStatement()
+		if stmt != nil {
+			caseClause.Consequent = append(caseClause.Consequent, stmt)
+		}
+		// The failed statement may have stopped on the closing brace of the switch statement
+		if len(p.errors) > errorCount && stmt == nil && p.synchronize(start) {
+			continue
+		}
+		p.nextToken()
+	}
 
+	// The clause ends before the token the parser is left on
+	p.locate(caseClause, caseClause.Token, p.prevToken)
 	return caseClause
 }
 
 func (p *Parser) parseBreakStatement() *BreakStatement {
 	stmt := &BreakStatement{Token: p.curToken}
+	stmt.Label = p.parseJumpLabel()
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ContinueStatement {
+	stmt := &ContinueStatement{Token: p.curToken}
+	stmt.Label = p.parseJumpLabel()
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseJumpLabel parses the optional label of a break or continue, which must be on the same line.
+func (p *Parser) parseJumpLabel() *Identifier {
+	if !p.peekTokenIs("identifier") || p.peekToken.Line != p.curToken.Line {
+		return nil
+	}
+	p.nextToken()
+	return p.parseIdentifier()
+}
+
+func (p *Parser) parseLabeledStatement() *LabeledStatement {
+	stmt := &LabeledStatement{Token: p.curToken, Label: p.parseIdentifier()}
+	p.nextToken() // Move to ':'
+
+	p.nextToken()
+	stmt.Body = p.parseStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ThrowStatement {
+	stmt := &ThrowStatement{Token: p.curToken}
+
+	// Unlike return, the argument is required and may not start on a new line
+	if p.peekToken.Line != p.curToken.Line {
+		p.errorAt(p.peekToken, "", "illegal newline after throw")
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Argument = p.parseExpression(LOWEST)
+	if stmt.Argument == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(";") {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseTryStatement() *TryStatement {
+	stmt := &TryStatement{Token: p.curToken}
+
+	if !p.expectPeek("{") {
+		return nil
+	}
+	stmt.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs("catch") {
+		p.nextToken()
+		handler := &CatchClause{Token: p.curToken}
+		// The binding is optional (e.g., catch { ... })
+		if p.peekTokenIs("(") {
+			p.nextToken()
+			if !p.expectPeek("identifier") {
+				return nil
+			}
+			handler.Param = p.parseIdentifier()
+			p.checkBinding(handler.Param)
+			if !p.expectPeek(")") {
+				return nil
+			}
+		}
+		if !p.expectPeek("{") {
+			return nil
+		}
+		handler.Body = p.parseBlockStatement()
+		p.finish(handler, handler.Token)
+		stmt.Handler = handler
+	}
+
+	if p.peekTokenIs("finally") {
+		p.nextToken()
+		if !p.expectPeek("{") {
+			return nil
+		}
+		stmt.Finalizer = p.parseBlockStatement()
+	}
+
+	if stmt.Handler == nil && stmt.Finalizer == nil {
+		p.errorAt(p.peekToken, "catch", "missing catch or finally after try")
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseReturnStatement() *ReturnStatement {
+	stmt := &ReturnStatement{Token: p.curToken}
+
+	// The value is optional, and may not start on a new line
+	if p.peekTokenIs(";") || p.peekTokenIs("}") || p.peekTokenIs("eof") || p.peekToken.Line != p.curToken.Line {
+		if p.peekTokenIs(";") {
+			p.nextToken()
+		}
+		return stmt
+	}
+
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		return nil
+	}
 
 	if p.peekTokenIs(";") {
 		p.nextToken()
@@ -386,10 +1054,13 @@ func (p *Parser) parseBreakStatement() *BreakStatement {
 }
 
 func (p *Parser) parseExpression(precedence int) Expression {
+	startToken := p.curToken
 	left := p.parseAtomicExpression()
 	if left == nil {
 		return nil
 	}
+	p.finish(left, startToken)
+	atomic := left
 
 	if config.LoadConfig().Verbose {
 		fmt.Println("╔══ parseExpression()")
@@ -407,34 +1078,106 @@ func (p *Parser) parseExpression(precedence int) Expression {
 			left = p.parseCallExpression(left)
 		case ".":
 			p.nextToken()
-			left = p.parseMemberAccessExpression(left)
-		case "=":
-			p.nextToken()
-			left = p.parseAssignmentExpression(left)
+			left = p.parseMemberExpression(left)
 		case "[":
 			p.nextToken()
-			return p.parseArrayAccessExpression(left)
+			left = p.parseComputedMemberExpression(left)
+		case "template", "templateHead":
+			p.nextToken()
+			left = p.parseTaggedTemplate(left)
+		case "++", "--":
+			// No line terminator is allowed between the operand and a postfix operator
+			if p.peekToken.Line != p.curToken.Line {
+				return left
+			}
+			p.nextToken()
+			left = p.parseUpdateExpression(left)
+		case "?":
+			p.nextToken()
+			left = p.parseConditionalExpression(left)
+		case ",":
+			p.nextToken()
+			left = p.parseSequenceExpression(left)
 		default:
-			if infixPrecedence := p.peekPrecedence(); precedence < infixPrecedence {
+			if isAssignmentOperator(p.peekToken.Type.Label) {
 				p.nextToken()
-				left = p.parseInfixExpression(left)
-			} else {
-				return left
+				left = p.parseAssignmentExpression(left)
+				if left == nil {
+					return nil
+				}
+				p.finish(left, startToken)
+				continue
 			}
+
+			// Nodes produced by the loop itself are never parenthesized
+			parenthesized := left == atomic && startToken.Type.Label == "("
+			unary := left == atomic && isUnaryOperator(startToken.Type.Label)
+			if !p.checkInfixOperand(left, parenthesized, unary) {
+				return nil
+			}
+
+			p.nextToken()
+			left = p.parseInfixExpression(left)
+		}
+		if left == nil {
+			return nil
 		}
+		p.finish(left, startToken)
 	}
 
 	return left
 }
 
-func (p *Parser) parseMemberAccessExpression(object Expression) Expression {
-	expr := &MemberAccessExpression{Token: p.curToken, Object: object}
+// checkInfixOperand enforces the grammar restrictions on the left operand of the peeked infix operator.
+func (p *Parser) checkInfixOperand(left Expression, parenthesized bool, unary bool) bool {
+	operator := p.peekToken.Type.Label
 
-	if !p.expectPeek("identifier") {
+	// e.g. -2 ** 2 is ambiguous and a syntax error, (-2) ** 2 is not
+	if operator == "**" && unary {
+		p.errorAt(p.peekToken, "", "unary operator used immediately before exponentiation expression, "+
+			"parentheses must be used to disambiguate operator precedence")
+		return false
+	}
+
+	// e.g. a || b ?? c and a ?? b || c are syntax errors without parentheses
+	if binary, ok := left.(*BinaryExpression); ok && !parenthesized {
+		mixesCoalesce := operator == "??" && (binary.Operator == "||" || binary.Operator == "&&")
+		mixesLogical := (operator == "||" || operator == "&&") && binary.Operator == "??"
+		if mixesCoalesce || mixesLogical {
+			p.errorAt(p.peekToken, "", fmt.Sprintf("cannot mix '%s' and '%s' without parentheses",
+				binary.Operator, operator))
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Parser) parseMemberExpression(object Expression) Expression {
+	expr := &MemberExpression{Token: p.curToken, Object: object}
+
+	// Any identifier name is allowed after the dot, including reserved words (e.g., promise.catch)
+	if !isIdentifierName(p.peekToken) {
+		p.expectPeek("identifier")
 		return nil
 	}
+	p.nextToken()
 
-	expr.Property = &Identifier{Token: p.curToken, Value: p.curToken.Text}
+	expr.Property = p.parseIdentifier()
+	return expr
+}
+
+func (p *Parser) parseComputedMemberExpression(object Expression) Expression {
+	expr := &MemberExpression{Token: p.curToken, Object: object, Computed: true}
+	defer p.allowIn()()
+	p.nextToken()
+	expr.Property = p.parseExpression(LOWEST)
+	if expr.Property == nil {
+		return nil
+	}
+	if !p.expectPeek("]") {
+		return nil
+	}
 	return expr
 }
 
@@ -445,19 +1188,91 @@ func (p *Parser) parseInfixExpression(left Expression) Expression {
 		Operator: p.curToken.Text,
 	}
 	precedence := p.curPrecedence()
+	switch expr.Operator {
+	case "**":
+		// Right-associative: a ** b ** c is a ** (b ** c)
+		precedence--
+	case "??":
+		// The operands of ?? are bitwise OR expressions, so || and && cannot be absorbed
+		precedence = LOGICAL_AND
+	}
 	p.nextToken()
 	expr.Right = p.parseExpression(precedence)
+	if expr.Right == nil {
+		return nil
+	}
+	return expr
+}
+
+func (p *Parser) parseConditionalExpression(test Expression) Expression {
+	expr := &ConditionalExpression{Token: p.curToken, Test: test}
+
+	p.nextToken() // Move past '?'
+	restoreIn := p.allowIn()
+	expr.Consequent = p.parseExpression(SEQUENCE)
+	restoreIn()
+	if expr.Consequent == nil {
+		return nil
+	}
+
+	if !p.expectPeek(":") {
+		return nil
+	}
+
+	p.nextToken() // Move past ':'
+	expr.Alternate = p.parseExpression(SEQUENCE)
+	if expr.Alternate == nil {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseSequenceExpression(first Expression) Expression {
+	expr := &SequenceExpression{Token: p.curToken, Expressions: []Expression{first}}
+
+	for {
+		p.nextToken() // Move past ','
+		next := p.parseExpression(SEQUENCE)
+		if next == nil {
+			return nil
+		}
+		expr.Expressions = append(expr.Expressions, next)
+
+		if !p.peekTokenIs(",") {
+			break
+		}
+		p.nextToken()
+	}
+
 	return expr
 }
 
 func (p *Parser) parseAtomicExpression() Expression {
 	switch p.curToken.Type.Label {
 	case "identifier":
+		if p.curToken.Text == "async" {
+			return p.parseAsyncExpression()
+		}
+		if p.peekTokenIs("=>") {
+			parameter := p.parseIdentifier()
+			p.nextToken()
+			return p.parseArrowFunction([]*Identifier{parameter}, false)
+		}
 		return p.parseIdentifier()
 	case "string":
 		return p.parseStringLiteral()
+	case "regexp":
+		return p.parseRegExpLiteral()
+	case "template", "templateHead":
+		if literal := p.parseTemplateLiteral(false); literal != nil {
+			return literal
+		}
+		return nil
 	case "number":
 		return p.parseIntegerLiteral()
+	case "bigint":
+		return p.parseBigIntLiteral()
 	case "boolean":
 		return p.parseBooleanLiteral()
 	case "null":
@@ -466,13 +1281,138 @@ func (p *Parser) parseAtomicExpression() Expression {
 		return p.parseUndefinedLiteral()
 	case "[":
 		return p.parseArrayLiteral()
+	case "{":
+		return p.parseObjectLiteral()
 	case "(":
 		return p.parseGroupedExpression()
-	case "!":
+	case "this":
+		return &ThisExpression{Token: p.curToken}
+	case "new":
+		return p.parseNewExpression()
+	case "function":
+		return p.parseFunctionExpression(false)
+	case "yield":
+		return p.parseYieldExpression()
+	case "await":
+		return p.parseAwaitExpression()
+	case "import":
+		return p.parseImportExpression()
+	case "!", "~", "+", "-", "typeof", "void", "delete":
 		return p.parsePrefixExpression()
+	case "++", "--":
+		return p.parseUpdateExpression(nil)
 	default:
+		p.unexpectedToken(p.curToken)
+		return nil
+	}
+}
+
+// parseNewExpression parses "new" followed by a member expression and optional arguments, or new.target.
+func (p *Parser) parseNewExpression() Expression {
+	token := p.curToken
+
+	if p.peekTokenIs(".") {
+		p.nextToken()
+		if !p.expectPeek("identifier") {
+			return nil
+		}
+		if p.curToken.Text != "target" {
+			p.errorAt(p.curToken, "target", fmt.Sprintf("the only valid meta property for new is new.target, "+
+				"got new.%s", p.curToken.Text))
+			return nil
+		}
+		return p.parseMetaProperty(token)
+	}
+
+	expr := &NewExpression{Token: token}
+
+	// The callee is a member expression: calls are not part of it, so new a.b() constructs a.b
+	p.nextToken()
+	calleeStart := p.curToken
+	callee := p.parseAtomicExpression()
+	for callee != nil {
+		p.finish(callee, calleeStart)
+		switch p.peekToken.Type.Label {
+		case ".":
+			p.nextToken()
+			callee = p.parseMemberExpression(callee)
+			continue
+		case "[":
+			p.nextToken()
+			callee = p.parseComputedMemberExpression(callee)
+			continue
+		case "template", "templateHead":
+			p.nextToken()
+			callee = p.parseTaggedTemplate(callee)
+			continue
+		}
+		break
+	}
+	if callee == nil {
+		return nil
+	}
+	expr.Callee = callee
+
+	if p.peekTokenIs("(") {
+		p.nextToken()
+		arguments, ok := p.parseExpressionList(")")
+		if !ok {
+			return nil
+		}
+		expr.Arguments = arguments
+	}
+
+	return expr
+}
+
+func (p *Parser) parseYieldExpression() Expression {
+	expr := &YieldExpression{Token: p.curToken}
+
+	if !p.inGenerator {
+		p.errorAt(p.curToken, "", "yield is only valid in generator functions")
+		return nil
+	}
+
+	if p.peekTokenIs("*") {
+		p.nextToken()
+		expr.Delegate = true
+	}
+
+	// The argument is optional, and may not start on a new line
+	switch p.peekToken.Type.Label {
+	case ")", "]", "}", ",", ";", ":", "eof":
+		if !expr.Delegate {
+			return expr
+		}
+	}
+	if p.peekToken.Line != expr.Token.Line && !expr.Delegate {
+		return expr
+	}
+
+	p.nextToken()
+	expr.Argument = p.parseExpression(SEQUENCE)
+	if expr.Argument == nil {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseAwaitExpression() Expression {
+	expr := &AwaitExpression{Token: p.curToken}
+
+	if !p.inAsync {
+		p.errorAt(p.curToken, "", "await is only valid in async functions")
+		return nil
+	}
+
+	p.nextToken()
+	expr.Argument = p.parseExpression(PREFIX)
+	if expr.Argument == nil {
 		return nil
 	}
+
+	return expr
 }
 
 func (p *Parser) parsePrefixExpression() Expression {
@@ -482,81 +1422,265 @@ func (p *Parser) parsePrefixExpression() Expression {
 	}
 	p.nextToken()
 	expression.Right = p.parseExpression(PREFIX)
+	if expression.Right == nil {
+		return nil
+	}
+	if _, ok := expression.Right.(*Identifier); ok && p.strict && expression.Operator == "delete" {
+		p.errorAt(expression.Token, "", "delete of an unqualified identifier in strict mode")
+		return nil
+	}
 	return expression
 }
 
-func (p *Parser) parseAssignmentExpression(left Expression) Expression {
-	exp := &AssignmentExpression{Token: p.curToken, Name: left.(*Identifier)}
+// parseUpdateExpression parses ++/-- in prefix form (argument is nil) or postfix form (argument is the operand).
+func (p *Parser) parseUpdateExpression(argument Expression) Expression {
+	expr := &UpdateExpression{Token: p.curToken, Operator: p.curToken.Text, Prefix: argument == nil}
+
+	if expr.Prefix {
+		p.nextToken()
+		argument = p.parseExpression(PREFIX)
+		if argument == nil {
+			return nil
+		}
+	}
 
-	p.nextToken() // Move past '='
-	exp.Value = p.parseExpression(LOWEST)
+	if !isAssignmentTarget(argument) {
+		p.errorAt(expr.Token, "", fmt.Sprintf("invalid %s operation target '%s'", expr.Operator,
+			argument.String()))
+		return nil
+	}
+	p.checkAssignmentTarget(argument)
+	expr.Argument = argument
 
-	return exp
+	return expr
 }
 
-func (p *Parser) parseArrayAccessExpression(left Expression) *ArrayAccessExpression {
-	expr := &ArrayAccessExpression{Token: p.curToken, Left: left}
-	p.nextToken()
-	expr.Index = p.parseExpression(LOWEST)
-	if !p.expectPeek("]") {
+func (p *Parser) parseAssignmentExpression(left Expression) Expression {
+	if !isAssignmentTarget(left) {
+		p.errorAt(p.curToken, "", fmt.Sprintf("invalid assignment target '%s'", left.String()))
 		return nil
 	}
-	return expr
+	p.checkAssignmentTarget(left)
+
+	exp := &AssignmentExpression{Token: p.curToken, Operator: p.curToken.Text, Left: left}
+
+	p.nextToken() // Move past the operator
+	// Right-associative: a = b = c is a = (b = c)
+	exp.Value = p.parseExpression(SEQUENCE)
+	if exp.Value == nil {
+		return nil
+	}
+
+	return exp
 }
 
 func (p *Parser) parseGroupedExpression() Expression {
+	// Empty parentheses are only valid as the parameters of an arrow function
+	if p.peekTokenIs(")") {
+		p.nextToken()
+		if !p.expectPeek("=>") {
+			return nil
+		}
+		return p.parseArrowFunction([]*Identifier{}, false)
+	}
+
 	p.nextToken() // Consume "("
+	restoreIn := p.allowIn()
 	expr := p.parseExpression(LOWEST)
+	restoreIn()
+	if expr == nil {
+		return nil
+	}
 	if !p.expectPeek(")") {
 		return nil
 	}
+
+	if p.peekTokenIs("=>") {
+		expressions := []Expression{expr}
+		if sequence, ok := expr.(*SequenceExpression); ok {
+			expressions = sequence.Expressions
+		}
+		parameters, ok := p.arrowParameters(expressions)
+		if !ok {
+			return nil
+		}
+		p.nextToken()
+		return p.parseArrowFunction(parameters, false)
+	}
+
 	return expr
 }
 
 func (p *Parser) parseCallExpression(function Expression) Expression {
 	expr := &CallExpression{Token: p.curToken, Function: function}
-	expr.Arguments = p.parseExpressionList(")")
+	arguments, ok := p.parseExpressionList(")")
+	if !ok {
+		return nil
+	}
+	expr.Arguments = arguments
 	return expr
 }
 
-func (p *Parser) parseExpressionList(end string) []Expression {
+func (p *Parser) parseExpressionList(end string) ([]Expression, bool) {
 	var list []Expression
+	defer p.allowIn()()
 
 	if p.peekTokenIs(end) {
 		p.nextToken()
-		return list
+		return list, true
 	}
 
-	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
-
-	for p.peekTokenIs(",") {
+	for {
 		p.nextToken()
+		element := p.parseExpression(SEQUENCE)
+		if element == nil {
+			return nil, false
+		}
+		list = append(list, element)
+
+		if !p.peekTokenIs(",") {
+			break
+		}
 		p.nextToken()
-		list = append(list, p.parseExpression(LOWEST))
 	}
 
-	if !p.peekTokenIs(end) {
-		p.errors = append(p.errors, fmt.Sprintf("expected next token to be %s, got %s instead", end, p.peekToken.Type.Label))
-		return nil
+	if !p.expectPeek(end) {
+		return nil, false
 	}
 
-	p.nextToken() // consume the end token
-
-	return list
+	return list, true
 }
 
 /**
  * Parsing Literals
  */
 
+func (p *Parser) parseObjectLiteral() Expression {
+	object := &ObjectLiteral{Token: p.curToken}
+	defer p.allowIn()()
+
+	for !p.peekTokenIs("}") {
+		p.nextToken()
+		property := p.parseProperty()
+		if property == nil {
+			return nil
+		}
+		object.Properties = append(object.Properties, property)
+
+		// A trailing comma is allowed
+		if !p.peekTokenIs("}") && !p.expectPeek(",") {
+			return nil
+		}
+	}
+	p.nextToken() // Consume '}'
+
+	return object
+}
+
+func (p *Parser) parseProperty() *Property {
+	property := &Property{Token: p.curToken}
+
+	// Async methods start with async, and generator methods with a '*' before the key
+	async := false
+	if p.curTokenIs("identifier") && p.curToken.Text == "async" && p.peekToken.Line == p.curToken.Line {
+		switch p.peekToken.Type.Label {
+		case "(", ":", ",", "}":
+			// A method, property or shorthand property named async
+		default:
+			async = true
+			p.nextToken()
+		}
+	}
+	generator := false
+	if p.curTokenIs("*") {
+		generator = true
+		p.nextToken()
+	}
+
+	switch {
+	case p.curTokenIs("["):
+		property.Computed = true
+		p.nextToken()
+		property.Key = p.parseExpression(SEQUENCE)
+		if property.Key == nil || !p.expectPeek("]") {
+			return nil
+		}
+	case p.curTokenIs("string"):
+		property.Key = p.parseStringLiteral()
+	case p.curTokenIs("number"):
+		property.Key = p.parseIntegerLiteral()
+	case p.curTokenIs("bigint"):
+		property.Key = p.parseBigIntLiteral()
+	case isIdentifierName(p.curToken):
+		property.Key = p.parseIdentifier()
+	default:
+		p.unexpectedToken(p.curToken)
+		return nil
+	}
+
+	if async || generator || p.peekTokenIs("(") {
+		if !p.expectPeek("(") {
+			return nil
+		}
+		property.Method = true
+		start := p.curToken
+		property.Value = p.parseFunctionRest(&FunctionExpression{Token: p.curToken, Generator: generator,
+			Async: async})
+		if property.Value == nil {
+			return nil
+		}
+		p.finish(property.Value, start)
+		p.finish(property, property.Token)
+		return property
+	}
+
+	// Shorthand properties (e.g., { a }) can only be identifiers
+	if identifier, ok := property.Key.(*Identifier); ok && !property.Computed &&
+		p.curTokenIs("identifier") && (p.peekTokenIs(",") || p.peekTokenIs("}")) {
+		property.Shorthand = true
+		property.Value = identifier
+		p.finish(property, property.Token)
+		return property
+	}
+
+	if !p.expectPeek(":") {
+		return nil
+	}
+	p.nextToken()
+	property.Value = p.parseExpression(SEQUENCE)
+	if property.Value == nil {
+		return nil
+	}
+
+	p.finish(property, property.Token)
+	return property
+}
+
 func (p *Parser) parseIdentifier() *Identifier {
-	return &Identifier{Token: p.curToken, Value: p.curToken.Text}
+	identifier := &Identifier{Token: p.curToken, Value: p.curToken.Text}
+	p.finish(identifier, p.curToken)
+	return identifier
+}
+
+// parseMetaProperty parses new.target or import.meta, ending at the property name.
+func (p *Parser) parseMetaProperty(token lexer.GojoToken) *MetaProperty {
+	meta := &Identifier{Token: token, Value: token.Text}
+	p.locate(meta, token, token)
+	return &MetaProperty{Token: token, Meta: meta, Property: p.parseIdentifier()}
 }
 
 func (p *Parser) parseIntegerLiteral() *IntegerLiteral {
-	literal := &IntegerLiteral{Token: p.curToken}
-	literal.Value, _ = strconv.ParseInt(p.curToken.Text, 0, 64)
+	literal := &IntegerLiteral{Token: p.curToken, Value: NumberValue(p.curToken.Text)}
+	p.checkNumber(literal)
+	p.finish(literal, p.curToken)
+	return literal
+}
+
+func (p *Parser) parseBigIntLiteral() *BigIntLiteral {
+	literal := &BigIntLiteral{Token: p.curToken}
+	// The prefix of the digits gives their base, e.g., 0x
+	literal.Value, _ = new(big.Int).SetString(strings.TrimSuffix(p.curToken.Text, "n"), 0)
+	p.finish(literal, p.curToken)
 	return literal
 }
 
@@ -575,12 +1699,89 @@ func (p *Parser) parseUndefinedLiteral() *UndefinedLiteral {
 }
 
 func (p *Parser) parseStringLiteral() *StringLiteral {
-	return &StringLiteral{Token: p.curToken, Value: p.curToken.Text}
+	literal := &StringLiteral{Token: p.curToken, Value: p.curToken.Text}
+	p.checkString(literal)
+	p.finish(literal, p.curToken)
+	return literal
+}
+
+// parseTemplateLiteral parses a template literal from its first chunk. Only tagged templates may have
+// chunks with invalid escapes.
+func (p *Parser) parseTemplateLiteral(tagged bool) *TemplateLiteral {
+	literal := &TemplateLiteral{Token: p.curToken}
+	defer p.allowIn()()
+	for {
+		tail := p.curTokenIs("template") || p.curTokenIs("templateTail")
+		element := &TemplateElement{Token: p.curToken, Raw: p.curToken.Raw, Tail: tail}
+		p.finish(element, p.curToken)
+		if !p.curToken.BadEscape {
+			cooked := p.curToken.Text
+			element.Cooked = &cooked
+		} else if !tagged {
+			p.errorAt(p.curToken, "", "invalid escape sequence in template literal")
+			return nil
+		}
+		literal.Quasis = append(literal.Quasis, element)
+		if element.Tail {
+			p.finish(literal, literal.Token)
+			return literal
+		}
+
+		p.nextToken() // Move past the chunk
+		expr := p.parseExpression(LOWEST)
+		if expr == nil {
+			return nil
+		}
+		literal.Expressions = append(literal.Expressions, expr)
+
+		// The '}' closing the substitution is part of the next chunk
+		switch p.peekToken.Type.Label {
+		case "templateMiddle", "templateTail":
+			p.nextToken()
+		case "illegal":
+			p.errorAt(p.peekToken, "", "unterminated template literal")
+			return nil
+		default:
+			p.expectPeek("}")
+			return nil
+		}
+	}
 }
 
-func (p *Parser) parseArrayLiteral() *ArrayLiteral {
+func (p *Parser) parseTaggedTemplate(tag Expression) Expression {
+	expr := &TaggedTemplateExpression{Token: p.curToken, Tag: tag}
+	expr.Quasi = p.parseTemplateLiteral(true)
+	if expr.Quasi == nil {
+		return nil
+	}
+	return expr
+}
+
+// parseRegExpLiteral parses a regular expression literal, its pattern and flags are checked now since
+// an invalid regular expression is an early error.
+func (p *Parser) parseRegExpLiteral() Expression {
+	text := p.curToken.Text
+	end := strings.LastIndex(text, "/")
+	literal := &RegExpLiteral{Token: p.curToken, Pattern: text[1:end], Flags: text[end+1:]}
+
+	if _, err := regex.ParseFlags(literal.Flags); err != nil {
+		p.errorAt(p.curToken, "", fmt.Sprintf("invalid regular expression flags '%s'", literal.Flags))
+		return nil
+	}
+	if _, err := regex.Compile(literal.Pattern, literal.Flags); err != nil {
+		p.errorAt(p.curToken, "", fmt.Sprintf("invalid regular expression: %s: %s", text, err))
+		return nil
+	}
+	return literal
+}
+
+func (p *Parser) parseArrayLiteral() Expression {
 	array := &ArrayLiteral{Token: p.curToken}
-	array.Elements = p.parseExpressionList("]")
+	elements, ok := p.parseExpressionList("]")
+	if !ok {
+		return nil
+	}
+	array.Elements = elements
 	return array
 }
 
@@ -589,9 +1790,17 @@ func (p *Parser) parseArrayLiteral() *ArrayLiteral {
  */
 
 func getPrecedence(token lexer.GojoToken) int {
-	switch token.Type.Label {
-	case "=":
+	label := token.Type.Label
+	if isAssignmentOperator(label) {
 		return ASSIGN
+	}
+	switch label {
+	case ",":
+		return SEQUENCE
+	case "?":
+		return CONDITIONAL
+	case "??":
+		return COALESCE
 	case "||":
 		return LOGICAL_OR
 	case "&&":
@@ -602,42 +1811,99 @@ func getPrecedence(token lexer.GojoToken) int {
 		return BITWISE_XOR
 	case "&":
 		return BITWISE_AND
-	case "==", "!=":
+	case "==", "!=", "===", "!==":
 		return EQUALS
-	case "<", ">", "<=", ">=":
+	case "<", ">", "<=", ">=", "in", "instanceof":
 		return COMPARISON
-	case "<<", ">>":
+	case "<<", ">>", ">>>":
 		return SHIFT
 	case "+", "-":
 		return SUM
 	case "*", "/", "%":
 		return PRODUCT
+	case "**":
+		return EXPONENT
+	case "++", "--":
+		return POSTFIX
 	case "(":
 		return CALL
-	case ".":
+	case ".", "template", "templateHead":
 		return MEMBER
 	case "[":
 		return INDEX
-	case "!":
-		return PREFIX
 	default:
 		return LOWEST
 	}
 }
 
+func isAssignmentOperator(label string) bool {
+	switch label {
+	case "=", "+=", "-=", "*=", "/=", "%=", "**=", "<<=", ">>=", ">>>=", "&=", "|=", "^=", "&&=", "||=", "??=":
+		return true
+	default:
+		return false
+	}
+}
+
+// isAssignmentTarget reports whether an expression can be assigned to (e.g., x, obj.x, arr[0]).
+func isAssignmentTarget(expr Expression) bool {
+	switch expr.(type) {
+	case *Identifier, *MemberExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdentifierName reports whether a token is an identifier or a reserved word, both of which are
+// valid property names.
+func isIdentifierName(token lexer.GojoToken) bool {
+	if token.Type.Label == "identifier" {
+		return true
+	}
+	if _, ok := lexer.TokenKeywords[token.Text]; ok {
+		return true
+	}
+	_, ok := lexer.TokenLiterals[token.Text]
+	return ok
+}
+
+func isUnaryOperator(label string) bool {
+	switch label {
+	case "!", "~", "+", "-", "typeof", "void", "delete", "await":
+		return true
+	default:
+		return false
+	}
+}
+
 func (p *Parser) curPrecedence() int {
 	return getPrecedence(p.curToken)
 }
 
 func (p *Parser) peekPrecedence() int {
+	// In the head of a for loop, "in" starts a for...in loop rather than continuing the expression
+	if p.noIn && p.peekTokenIs("in") {
+		return LOWEST
+	}
 	return getPrecedence(p.peekToken)
 }
 
+// allowIn makes "in" an operator again until the returned function restores the previous state, e.g., between
+// brackets in the head of a for loop.
+func (p *Parser) allowIn() func() {
+	noIn := p.noIn
+	p.noIn = false
+	return func() { p.noIn = noIn }
+}
+
 func (p *Parser) expectPeek(tokenKey string) bool {
 	if p.peekTokenIs(tokenKey) {
 		p.nextToken()
 		return true
 	}
+	p.errorAt(p.peekToken, tokenKey, fmt.Sprintf("expected '%s', got %s instead", tokenKey,
+		describeToken(p.peekToken)))
 	return false
 }
 
@@ -648,11 +1914,3 @@ func (p *Parser) peekTokenIs(tokenKey string) bool {
 func (p *Parser) curTokenIs(tokenKey string) bool {
 	return p.curToken.Type.Label == tokenKey
 }
-
-/**
- * Error handling
- */
-
-func (p *Parser) Errors() []string {
-	return p.errors
-}
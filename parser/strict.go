@@ -0,0 +1,147 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// applyDirective handles a statement of a directive prologue, the string literal statements starting a
+// script or a function body. "use strict" switches to strict mode for the rest of the code.
+// It reports whether the statement is a directive, so the prologue goes on.
+func (p *Parser) applyDirective(stmt Statement) bool {
+	es, ok := stmt.(*ExpressionStatement)
+	if !ok || es.Token.Type.Label != "string" {
+		return false
+	}
+	literal, ok := es.Expression.(*StringLiteral)
+	if !ok {
+		return false
+	}
+	es.Directive = literal.Value
+	if literal.Token.OctalEscape && p.octalDirective == nil {
+		p.octalDirective = literal
+	}
+
+	// The directive must be written exactly, without escape sequences: the quotes add 2 to its length
+	raw := literal.Token.End - literal.Token.Start
+	if literal.Value == "use strict" && raw == len("use strict")+2 && !p.strict {
+		p.strict = true
+		// The directives before it are strict mode code too
+		if p.octalDirective != nil {
+			p.checkString(p.octalDirective)
+		}
+	}
+	return true
+}
+
+// startPrologue starts the directive prologue of a script or a function body.
+func (p *Parser) startPrologue() {
+	p.octalDirective = nil
+}
+
+// checkBinding reports the names strict mode code may not bind, e.g., var eval.
+func (p *Parser) checkBinding(identifier *Identifier) {
+	if p.strict {
+		p.checkStrictName(identifier)
+	}
+}
+
+// checkAssignmentTarget reports assignments strict mode code may not make, e.g., arguments = 1.
+func (p *Parser) checkAssignmentTarget(target Expression) {
+	if identifier, ok := target.(*Identifier); ok && p.strict {
+		p.checkStrictName(identifier)
+	}
+}
+
+func (p *Parser) checkStrictName(identifier *Identifier) {
+	if identifier.Value == "eval" || identifier.Value == "arguments" {
+		p.errorAt(identifier.Token, "", fmt.Sprintf("unexpected %s in strict mode", identifier.Value))
+	}
+}
+
+// checkFunction reports the errors in the name and parameters of a function that depend on its body:
+// a "use strict" directive in the body applies to them too. Parameter names must be unique in strict
+// functions and in arrow functions.
+func (p *Parser) checkFunction(name *Identifier, parameters []*Identifier, strict bool, arrow bool) {
+	if strict && name != nil {
+		p.checkStrictName(name)
+	}
+
+	seen := map[string]bool{}
+	for _, parameter := range parameters {
+		if strict {
+			p.checkStrictName(parameter)
+		}
+		if seen[parameter.Value] && (strict || arrow) {
+			p.errorAt(parameter.Token, "", fmt.Sprintf("duplicate parameter name '%s' not allowed in this "+
+				"context", parameter.Value))
+		}
+		seen[parameter.Value] = true
+	}
+}
+
+// checkNumber reports the legacy octal literals (e.g., 017) and decimals with leading zeros (e.g., 019)
+// strict mode code may not contain.
+func (p *Parser) checkNumber(literal *IntegerLiteral) {
+	text := literal.Token.Text
+	if !p.strict || !isLegacyOctalLike(text) {
+		return
+	}
+	if strings.ContainsAny(text, "89") {
+		p.errorAt(literal.Token, "", "decimals with leading zeros are not allowed in strict mode")
+	} else {
+		p.errorAt(literal.Token, "", "octal literals are not allowed in strict mode")
+	}
+}
+
+// checkString reports the legacy octal escapes (e.g., "\07") strict mode code may not contain.
+func (p *Parser) checkString(literal *StringLiteral) {
+	if p.strict && literal.Token.OctalEscape {
+		p.errorAt(literal.Token, "", "octal escape sequences are not allowed in strict mode")
+	}
+}
+
+// isLegacyOctalLike reports whether a number literal starts with 0 followed by more digits.
+func isLegacyOctalLike(text string) bool {
+	if len(text) < 2 || text[0] != '0' {
+		return false
+	}
+	for _, char := range text[1:] {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWithStatement parses a with statement, which strict mode code may not contain.
+func (p *Parser) parseWithStatement() *WithStatement {
+	stmt := &WithStatement{Token: p.curToken}
+
+	// The statement is still parsed, so parsing goes on right after it
+	if p.strict {
+		p.errorAt(p.curToken, "", "strict mode code may not include a with statement")
+	}
+
+	if !p.expectPeek("(") {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Object = p.parseExpression(LOWEST)
+	if stmt.Object == nil {
+		return nil
+	}
+
+	if !p.expectPeek(")") {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Body = p.parseStatement()
+	if stmt.Body == nil {
+		return nil
+	}
+
+	return stmt
+}
@@ -0,0 +1,223 @@
+package parser
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk. If the result visitor w is not nil,
+// Walk visits each of the children of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order, visiting the children of a node in the order of its fields. That
+// is the source order, except for template literals whose quasis are all visited before the expressions. Nodes
+// shared by two fields (e.g., the key and value of a shorthand property) are visited twice.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkList(v, n.Statements)
+
+	// Statements
+	case *VariableDeclaration:
+		walkList(v, n.Declarations)
+	case *VariableDeclarator:
+		Walk(v, n.Name)
+		walkOptional(v, n.Value)
+	case *FunctionDeclaration:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		walkList(v, n.Parameters)
+		Walk(v, n.Body)
+	case *BlockStatement:
+		walkList(v, n.Statements)
+	case *ExpressionStatement:
+		Walk(v, n.Expression)
+	case *IfStatement:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+	case *WhileStatement:
+		Walk(v, n.Condition)
+		Walk(v, n.Body)
+	case *ForStatement:
+		walkOptional(v, n.Init)
+		walkOptional(v, n.Test)
+		walkOptional(v, n.Update)
+		Walk(v, n.Body)
+	case *ForInStatement:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+		Walk(v, n.Body)
+	case *ForOfStatement:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+		Walk(v, n.Body)
+	case *SwitchStatement:
+		Walk(v, n.Expression)
+		walkList(v, n.Cases)
+	case *CaseClause:
+		walkOptional(v, n.Condition)
+		walkList(v, n.Consequent)
+	case *TryStatement:
+		Walk(v, n.Block)
+		if n.Handler != nil {
+			Walk(v, n.Handler)
+		}
+		if n.Finalizer != nil {
+			Walk(v, n.Finalizer)
+		}
+	case *CatchClause:
+		if n.Param != nil {
+			Walk(v, n.Param)
+		}
+		Walk(v, n.Body)
+	case *ThrowStatement:
+		Walk(v, n.Argument)
+	case *ReturnStatement:
+		walkOptional(v, n.Value)
+	case *WithStatement:
+		Walk(v, n.Object)
+		Walk(v, n.Body)
+	case *LabeledStatement:
+		Walk(v, n.Label)
+		Walk(v, n.Body)
+	case *EmptyStatement:
+		// Leaf
+	case *BreakStatement:
+		if n.Label != nil {
+			Walk(v, n.Label)
+		}
+	case *ContinueStatement:
+		if n.Label != nil {
+			Walk(v, n.Label)
+		}
+
+	// Modules
+	case *ImportDeclaration:
+		walkList(v, n.Specifiers)
+		Walk(v, n.Source)
+	case *ImportDefaultSpecifier:
+		Walk(v, n.Local)
+	case *ImportNamespaceSpecifier:
+		Walk(v, n.Local)
+	case *ImportSpecifier:
+		Walk(v, n.Imported)
+		Walk(v, n.Local)
+	case *ExportNamedDeclaration:
+		walkOptional(v, n.Declaration)
+		walkList(v, n.Specifiers)
+		if n.Source != nil {
+			Walk(v, n.Source)
+		}
+	case *ExportSpecifier:
+		Walk(v, n.Local)
+		Walk(v, n.Exported)
+	case *ExportDefaultDeclaration:
+		Walk(v, n.Declaration)
+	case *ExportAllDeclaration:
+		if n.Exported != nil {
+			Walk(v, n.Exported)
+		}
+		Walk(v, n.Source)
+
+	// Expressions
+	case *Identifier, *IntegerLiteral, *BigIntLiteral, *StringLiteral, *RegExpLiteral, *TemplateElement,
+		*BooleanLiteral, *NullLiteral, *UndefinedLiteral, *ThisExpression:
+		// Leaves
+	case *AssignmentExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Value)
+	case *TemplateLiteral:
+		walkList(v, n.Quasis)
+		walkList(v, n.Expressions)
+	case *TaggedTemplateExpression:
+		Walk(v, n.Tag)
+		Walk(v, n.Quasi)
+	case *ArrayLiteral:
+		walkList(v, n.Elements)
+	case *ObjectLiteral:
+		walkList(v, n.Properties)
+	case *Property:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+	case *BinaryExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *MemberExpression:
+		Walk(v, n.Object)
+		Walk(v, n.Property)
+	case *NewExpression:
+		Walk(v, n.Callee)
+		walkList(v, n.Arguments)
+	case *MetaProperty:
+		Walk(v, n.Meta)
+		Walk(v, n.Property)
+	case *CallExpression:
+		Walk(v, n.Function)
+		walkList(v, n.Arguments)
+	case *FunctionExpression:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		walkList(v, n.Parameters)
+		Walk(v, n.Body)
+	case *ArrowFunctionExpression:
+		walkList(v, n.Parameters)
+		Walk(v, n.Body)
+	case *AwaitExpression:
+		Walk(v, n.Argument)
+	case *YieldExpression:
+		walkOptional(v, n.Argument)
+	case *PrefixExpression:
+		Walk(v, n.Right)
+	case *UpdateExpression:
+		Walk(v, n.Argument)
+	case *ConditionalExpression:
+		Walk(v, n.Test)
+		Walk(v, n.Consequent)
+		Walk(v, n.Alternate)
+	case *SequenceExpression:
+		walkList(v, n.Expressions)
+	case *ImportExpression:
+		Walk(v, n.Source)
+
+	default:
+		panic(fmt.Sprintf("parser.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+// walkOptional walks a node held by an interface field that is nil when absent.
+func walkOptional(v Visitor, node Node) {
+	if node != nil {
+		Walk(v, node)
+	}
+}
+
+func walkList[N Node](v Visitor, list []N) {
+	for _, node := range list {
+		Walk(v, node)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order like Walk, calling f(node) for each node and then f(nil) after
+// its children. The children of a node are skipped when f returns false.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
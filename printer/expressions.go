@@ -0,0 +1,515 @@
+package printer
+
+import (
+	"fmt"
+	"gojo/parser"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// primary is the precedence of expressions that never need parentheses (e.g., identifiers and literals).
+const primary = parser.INDEX + 1
+
+// allowIn stops parenthesizing in operators until the returned function restores the previous state, between
+// brackets in the head of a for loop.
+func (p *printer) allowIn() func() {
+	noIn := p.noIn
+	p.noIn = false
+	return func() { p.noIn = noIn }
+}
+
+// expression prints an expression, between parentheses when its precedence is lower than the given one.
+func (p *printer) expression(expr parser.Expression, precedence int) {
+	if precedenceOf(expr) < precedence {
+		p.write("(")
+		p.expression(expr, parser.LOWEST)
+		p.write(")")
+		return
+	}
+	// In the head of a for loop, "in" would start a for...in loop
+	if binary, ok := expr.(*parser.BinaryExpression); ok && binary.Operator == "in" && p.noIn {
+		p.noIn = false
+		p.write("(")
+		p.expression(expr, parser.LOWEST)
+		p.write(")")
+		p.noIn = true
+		return
+	}
+
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		p.write(expr.Value)
+	case *parser.IntegerLiteral:
+		p.write(integerText(expr))
+	case *parser.BigIntLiteral:
+		p.write(bigIntText(expr))
+	case *parser.StringLiteral:
+		p.stringLiteral(expr)
+	case *parser.RegExpLiteral:
+		p.write("/" + expr.Pattern + "/" + expr.Flags)
+	case *parser.BooleanLiteral:
+		p.write(strconv.FormatBool(expr.Value))
+	case *parser.NullLiteral:
+		p.write("null")
+	case *parser.UndefinedLiteral:
+		p.write("undefined")
+	case *parser.ThisExpression:
+		p.write("this")
+	case *parser.TemplateLiteral:
+		p.templateLiteral(expr)
+	case *parser.TaggedTemplateExpression:
+		p.expression(expr.Tag, parser.CALL)
+		p.templateLiteral(expr.Quasi)
+	case *parser.ArrayLiteral:
+		p.arguments(expr, "[", "]", expr.Elements)
+	case *parser.ObjectLiteral:
+		p.objectLiteral(expr)
+	case *parser.FunctionExpression:
+		p.function(expr, expr.Async, expr.Generator, expr.Name, expr.Parameters, expr.Body)
+	case *parser.ArrowFunctionExpression:
+		p.arrowFunction(expr)
+	case *parser.MetaProperty:
+		p.write(expr.Meta.Value + "." + expr.Property.Value)
+	case *parser.ImportExpression:
+		p.write("import(")
+		p.expression(expr.Source, parser.ASSIGN)
+		p.write(")")
+	case *parser.MemberExpression:
+		p.memberExpression(expr)
+	case *parser.CallExpression:
+		p.expression(expr.Function, parser.CALL)
+		p.arguments(expr, "(", ")", expr.Arguments)
+	case *parser.NewExpression:
+		p.write("new ")
+		// The arguments of a call in the callee would be taken for those of new
+		if containsCall(expr.Callee) {
+			p.write("(")
+			p.expression(expr.Callee, parser.LOWEST)
+			p.write(")")
+		} else {
+			p.expression(expr.Callee, parser.CALL)
+		}
+		p.arguments(expr, "(", ")", expr.Arguments)
+	case *parser.UpdateExpression:
+		if expr.Prefix {
+			p.write(expr.Operator)
+			p.expression(expr.Argument, parser.CALL)
+		} else {
+			p.expression(expr.Argument, parser.CALL)
+			p.write(expr.Operator)
+		}
+	case *parser.PrefixExpression:
+		p.write(expr.Operator)
+		if unicode.IsLetter(rune(expr.Operator[0])) || startsWithSign(expr.Right, expr.Operator[0]) {
+			p.write(" ")
+		}
+		p.expression(expr.Right, parser.PREFIX)
+	case *parser.AwaitExpression:
+		p.write("await ")
+		p.expression(expr.Argument, parser.PREFIX)
+	case *parser.BinaryExpression:
+		p.binaryExpression(expr)
+	case *parser.ConditionalExpression:
+		p.expression(expr.Test, parser.COALESCE)
+		p.write(" ? ")
+		p.expression(expr.Consequent, parser.ASSIGN)
+		p.write(" : ")
+		p.expression(expr.Alternate, parser.ASSIGN)
+	case *parser.AssignmentExpression:
+		p.expression(expr.Left, parser.CALL)
+		p.write(" " + expr.Operator + " ")
+		p.expression(expr.Value, parser.ASSIGN)
+	case *parser.YieldExpression:
+		p.write("yield")
+		if expr.Delegate {
+			p.write("*")
+		}
+		if expr.Argument != nil {
+			p.write(" ")
+			p.expression(expr.Argument, parser.ASSIGN)
+		}
+	case *parser.SequenceExpression:
+		for idx, element := range expr.Expressions {
+			if idx > 0 {
+				p.write(", ")
+			}
+			p.expression(element, parser.ASSIGN)
+		}
+	default:
+		panic("printer: unexpected expression " + expr.String())
+	}
+}
+
+// precedenceOf gives the precedence of the operator of an expression, following the levels of the parser.
+func precedenceOf(expr parser.Expression) int {
+	switch expr := expr.(type) {
+	case *parser.SequenceExpression:
+		return parser.SEQUENCE
+	case *parser.AssignmentExpression, *parser.ArrowFunctionExpression, *parser.YieldExpression:
+		return parser.ASSIGN
+	case *parser.ConditionalExpression:
+		return parser.CONDITIONAL
+	case *parser.BinaryExpression:
+		return operatorPrecedence(expr.Operator)
+	case *parser.PrefixExpression, *parser.AwaitExpression:
+		return parser.PREFIX
+	case *parser.UpdateExpression:
+		if expr.Prefix {
+			return parser.PREFIX
+		}
+		return parser.POSTFIX
+	case *parser.IntegerLiteral:
+		if strings.HasPrefix(integerText(expr), "-") {
+			return parser.PREFIX
+		}
+		return primary
+	case *parser.BigIntLiteral:
+		if expr.Value.Sign() < 0 {
+			return parser.PREFIX
+		}
+		return primary
+	case *parser.CallExpression, *parser.NewExpression, *parser.MemberExpression,
+		*parser.TaggedTemplateExpression, *parser.ImportExpression:
+		return parser.CALL
+	default:
+		return primary
+	}
+}
+
+func operatorPrecedence(operator string) int {
+	switch operator {
+	case "??":
+		return parser.COALESCE
+	case "||":
+		return parser.LOGICAL_OR
+	case "&&":
+		return parser.LOGICAL_AND
+	case "|":
+		return parser.BITWISE_OR
+	case "^":
+		return parser.BITWISE_XOR
+	case "&":
+		return parser.BITWISE_AND
+	case "==", "!=", "===", "!==":
+		return parser.EQUALS
+	case "<", ">", "<=", ">=", "in", "instanceof":
+		return parser.COMPARISON
+	case "<<", ">>", ">>>":
+		return parser.SHIFT
+	case "+", "-":
+		return parser.SUM
+	case "*", "/", "%":
+		return parser.PRODUCT
+	default:
+		return parser.EXPONENT
+	}
+}
+
+func (p *printer) binaryExpression(expr *parser.BinaryExpression) {
+	precedence := operatorPrecedence(expr.Operator)
+	left, right := precedence, precedence+1
+	if expr.Operator == "**" {
+		// Right-associative, and a unary operand on the left is a syntax error (e.g., -a ** b)
+		left, right = parser.POSTFIX, precedence
+	}
+	p.operand(expr.Left, expr.Operator, left)
+	p.write(" " + expr.Operator + " ")
+	p.operand(expr.Right, expr.Operator, right)
+}
+
+// operand prints the operand of a binary operator. The operands of ?? cannot be || or && expressions
+// without parentheses.
+func (p *printer) operand(expr parser.Expression, operator string, precedence int) {
+	if binary, ok := expr.(*parser.BinaryExpression); ok && operator == "??" &&
+		(binary.Operator == "||" || binary.Operator == "&&") {
+		precedence = primary
+	}
+	p.expression(expr, precedence)
+}
+
+func (p *printer) memberExpression(expr *parser.MemberExpression) {
+	// The dot after a decimal integer would be taken for its decimal point
+	if integer, ok := expr.Object.(*parser.IntegerLiteral); ok && !expr.Computed &&
+		!strings.ContainsAny(integerText(integer), "xXoObB") {
+		p.write("(")
+		p.expression(integer, parser.LOWEST)
+		p.write(")")
+	} else {
+		p.expression(expr.Object, parser.CALL)
+	}
+
+	if expr.Computed {
+		p.write("[")
+		restoreIn := p.allowIn()
+		p.expression(expr.Property, parser.LOWEST)
+		restoreIn()
+		p.write("]")
+		return
+	}
+	p.write(".")
+	p.expression(expr.Property, primary)
+}
+
+// arguments prints the arguments of a call or the elements of an array between brackets.
+func (p *printer) arguments(node parser.Node, open string, close string, arguments []parser.Expression) {
+	defer p.allowIn()()
+	p.group(node, open, close, len(arguments), groupOptions{}, func(p *printer, idx int) {
+		p.expression(arguments[idx], parser.ASSIGN)
+	})
+}
+
+func (p *printer) objectLiteral(expr *parser.ObjectLiteral) {
+	defer p.allowIn()()
+	// Properties spanning several lines (e.g., methods) are each put on their own line
+	options := groupOptions{spaced: true, multiline: true, trailingComma: true}
+	p.group(expr, "{", "}", len(expr.Properties), options, func(p *printer, idx int) {
+		p.property(expr.Properties[idx])
+	})
+}
+
+func (p *printer) property(property *parser.Property) {
+	if property.Shorthand {
+		p.propertyKey(property)
+		return
+	}
+	if method, ok := property.Value.(*parser.FunctionExpression); ok && property.Method {
+		if method.Async {
+			p.write("async ")
+		}
+		if method.Generator {
+			p.write("*")
+		}
+		p.propertyKey(property)
+		p.parameters(method, method.Parameters)
+		p.write(" ")
+		p.block(method.Body)
+		return
+	}
+	p.propertyKey(property)
+	p.write(": ")
+	p.expression(property.Value, parser.ASSIGN)
+}
+
+func (p *printer) propertyKey(property *parser.Property) {
+	if property.Computed {
+		p.write("[")
+		p.expression(property.Key, parser.ASSIGN)
+		p.write("]")
+		return
+	}
+	p.expression(property.Key, primary)
+}
+
+func (p *printer) arrowFunction(expr *parser.ArrowFunctionExpression) {
+	if expr.Async {
+		p.write("async ")
+	}
+	p.parameters(expr, expr.Parameters)
+	p.write(" => ")
+	switch body := expr.Body.(type) {
+	case *parser.BlockStatement:
+		p.block(body)
+	case parser.Expression:
+		// A concise body starting with { would be a block
+		if _, ok := leftmost(body).(*parser.ObjectLiteral); ok {
+			p.write("(")
+			p.expression(body, parser.LOWEST)
+			p.write(")")
+		} else {
+			p.expression(body, parser.ASSIGN)
+		}
+	}
+}
+
+func (p *printer) templateLiteral(expr *parser.TemplateLiteral) {
+	p.write("`")
+	for idx, quasi := range expr.Quasis {
+		p.write(quasi.Raw)
+		if idx < len(expr.Expressions) {
+			p.write("${")
+			p.expression(expr.Expressions[idx], parser.LOWEST)
+			p.write("}")
+		}
+	}
+	p.write("`")
+}
+
+// stringLiteral quotes a string with the configured quote, or the other one when it needs fewer escapes. A string
+// whose value did not change since it was parsed keeps the escapes of its source text.
+func (p *printer) stringLiteral(expr *parser.StringLiteral) {
+	preferred, other := byte('"'), byte('\'')
+	if p.config.SingleQuote {
+		preferred, other = other, preferred
+	}
+	if strings.Count(expr.Value, string(preferred)) > strings.Count(expr.Value, string(other)) {
+		preferred = other
+	}
+	if expr.Token.Raw != "" && expr.Token.Text == expr.Value {
+		p.write(requote(expr.Token.Raw, preferred))
+	} else {
+		p.write(quote(expr.Value, preferred))
+	}
+}
+
+// requote changes the quotes of the source text of a string, escaping the new quote and unescaping the old one.
+func requote(raw string, quoteChar byte) string {
+	var out strings.Builder
+	out.WriteByte(quoteChar)
+	for idx := 1; idx < len(raw)-1; idx++ {
+		switch char := raw[idx]; {
+		case char == '\\':
+			idx++
+			if escaped := raw[idx]; escaped != quoteChar && (escaped == '"' || escaped == '\'') {
+				out.WriteByte(escaped)
+			} else {
+				out.WriteByte(char)
+				out.WriteByte(escaped)
+			}
+		case char == quoteChar:
+			out.WriteByte('\\')
+			out.WriteByte(char)
+		default:
+			out.WriteByte(char)
+		}
+	}
+	out.WriteByte(quoteChar)
+	return out.String()
+}
+
+// quote quotes a string, escaping the quote, backslashes, control characters and line terminators.
+func quote(value string, quoteChar byte) string {
+	var out strings.Builder
+	out.WriteByte(quoteChar)
+	for idx, char := range value {
+		switch char {
+		case rune(quoteChar), '\\':
+			out.WriteByte('\\')
+			out.WriteRune(char)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\b':
+			out.WriteString(`\b`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '\v':
+			out.WriteString(`\v`)
+		case '\u2028', '\u2029':
+			fmt.Fprintf(&out, `\u%04X`, char)
+		case utf8.RuneError:
+			// Bytes that are not UTF-8 are kept as they are
+			_, size := utf8.DecodeRuneInString(value[idx:])
+			out.WriteString(value[idx : idx+size])
+		default:
+			if char < ' ' || char == 0x7F {
+				// \x00 rather than \0, which a digit after it would turn into an octal escape
+				fmt.Fprintf(&out, `\x%02X`, char)
+			} else {
+				out.WriteRune(char)
+			}
+		}
+	}
+	out.WriteByte(quoteChar)
+	return out.String()
+}
+
+// integerText gives the source text of a number, unless the value changed since it was parsed.
+func integerText(expr *parser.IntegerLiteral) string {
+	if expr.Token.Text != "" && parser.NumberValue(expr.Token.Text) == expr.Value {
+		return expr.Token.Text
+	}
+	switch {
+	case math.IsNaN(expr.Value):
+		return "NaN"
+	case math.IsInf(expr.Value, 1):
+		return "Infinity"
+	case math.IsInf(expr.Value, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(expr.Value, 'g', -1, 64)
+}
+
+// bigIntText gives the source text of a BigInt, unless the value changed since it was parsed.
+func bigIntText(expr *parser.BigIntLiteral) string {
+	if value, ok := new(big.Int).SetString(strings.TrimSuffix(expr.Token.Text, "n"), 0); ok &&
+		value.Cmp(expr.Value) == 0 {
+		return expr.Token.Text
+	}
+	return expr.Value.String() + "n"
+}
+
+// leftmost finds the expression printed first, without parentheses, as part of an expression.
+func leftmost(expr parser.Expression) parser.Expression {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		return leftmost(e.Left)
+	case *parser.AssignmentExpression:
+		return leftmost(e.Left)
+	case *parser.ConditionalExpression:
+		return leftmost(e.Test)
+	case *parser.SequenceExpression:
+		return leftmost(e.Expressions[0])
+	case *parser.CallExpression:
+		return leftmost(e.Function)
+	case *parser.MemberExpression:
+		return leftmost(e.Object)
+	case *parser.TaggedTemplateExpression:
+		return leftmost(e.Tag)
+	case *parser.UpdateExpression:
+		if !e.Prefix {
+			return leftmost(e.Argument)
+		}
+	}
+	return expr
+}
+
+// startsAmbiguously tells whether an expression statement would be read as another statement without
+// parentheses.
+func startsAmbiguously(expr parser.Expression) bool {
+	switch leftmost(expr).(type) {
+	case *parser.ObjectLiteral, *parser.FunctionExpression:
+		return true
+	default:
+		return false
+	}
+}
+
+// containsCall tells whether a callee of new has a call outside of parentheses (e.g., new (a().b)()).
+func containsCall(expr parser.Expression) bool {
+	switch e := expr.(type) {
+	case *parser.CallExpression, *parser.ImportExpression:
+		return true
+	case *parser.MemberExpression:
+		return containsCall(e.Object)
+	case *parser.TaggedTemplateExpression:
+		return containsCall(e.Tag)
+	default:
+		return false
+	}
+}
+
+// startsWithSign tells whether the operand of a + or - operator starts with a sign too (e.g., - -a).
+func startsWithSign(expr parser.Expression, sign byte) bool {
+	if sign != '+' && sign != '-' {
+		return false
+	}
+	switch e := expr.(type) {
+	case *parser.PrefixExpression:
+		return e.Operator[0] == sign
+	case *parser.UpdateExpression:
+		return e.Prefix && e.Operator[0] == sign
+	case *parser.IntegerLiteral:
+		return integerText(e)[0] == sign
+	case *parser.BigIntLiteral:
+		return sign == '-' && e.Value.Sign() < 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,608 @@
+// Package printer prints ASTs of the parser package back as JavaScript source.
+package printer
+
+import (
+	"gojo/parser"
+	"strings"
+	"unicode/utf8"
+)
+
+// Config controls the layout of the printed source.
+type Config struct {
+	Indent         string // The indentation of each nesting level, two spaces when empty
+	SingleQuote    bool   // Whether strings are quoted with ' rather than ", unless that needs more escapes
+	OmitSemicolons bool   // Whether to leave out the semicolons automatic semicolon insertion adds back
+	Width          int    // The line width lists are broken to fit in, 80 when zero
+}
+
+// Print prints a node as JavaScript with the default configuration.
+func Print(node parser.Node) string {
+	return (&Config{}).Print(node)
+}
+
+// Print prints a node as JavaScript, only adding the parentheses its structure requires. The comments of
+// a program are printed between its statements, the closest to where they were in the source.
+func (c *Config) Print(node parser.Node) string {
+	p := &printer{config: *c, flatTexts: map[parser.Node]string{}}
+	if p.config.Indent == "" {
+		p.config.Indent = "  "
+	}
+	if p.config.Width == 0 {
+		p.config.Width = 80
+	}
+
+	switch node := node.(type) {
+	case *parser.Program:
+		p.comments = node.Comments
+		p.list(nodesOf(node.Statements), parser.Loc{})
+		p.commentsBefore(-1)
+		if p.out.Len() > 0 {
+			p.out.WriteString("\n")
+		}
+	case parser.Expression:
+		p.expression(node, parser.LOWEST)
+	default:
+		p.item(node)
+	}
+	return p.out.String()
+}
+
+type printer struct {
+	config    Config
+	out       strings.Builder
+	column    int               // The width of the current line so far
+	level     int               // The nesting level of the current line
+	comments  []*parser.Comment // The comments left to print, in source order
+	lastLine  int               // The source line the last printed item of a list ended on, 0 when unknown
+	flat      bool              // Whether lists are kept on one line whatever their width, to measure them
+	noIn      bool              // Whether "in" operators are parenthesized, in the head of a for loop
+	flatTexts map[parser.Node]string
+}
+
+func (p *printer) write(text string) {
+	p.out.WriteString(text)
+	if idx := strings.LastIndexByte(text, '\n'); idx >= 0 {
+		p.column = utf8.RuneCountInString(text[idx+1:])
+	} else {
+		p.column += utf8.RuneCountInString(text)
+	}
+}
+
+// newline starts a new line at the current nesting level.
+func (p *printer) newline() {
+	p.write("\n" + strings.Repeat(p.config.Indent, p.level))
+}
+
+func (p *printer) semicolon() {
+	if !p.config.OmitSemicolons {
+		p.write(";")
+	}
+}
+
+/**
+ * Lists and comments
+ */
+
+// list prints statements or case clauses each on its own line, with the comments before them and the
+// comments left before the end of the enclosing node. Single blank lines of the source are kept between
+// items. It reports whether anything was printed.
+func (p *printer) list(items []parser.Node, end parser.Loc) bool {
+	printed := false
+	p.lastLine = 0
+	for _, item := range items {
+		// Empty statements only matter as the body of a statement
+		if _, ok := item.(*parser.EmptyStatement); ok {
+			continue
+		}
+		loc := item.Location()
+		if located(loc) {
+			printed = p.commentsBefore(loc.Start.Offset) || printed
+			if p.lastLine > 0 && loc.Start.Line > p.lastLine+1 {
+				p.write("\n")
+			}
+		}
+		p.startLine()
+		// Without semicolons, a statement starting with one of these would continue the previous one
+		if _, ok := item.(*parser.ExpressionStatement); ok && p.config.OmitSemicolons &&
+			strings.ContainsAny(p.flatText(item, func(flat *printer) { flat.item(item) })[:1], "([`+-/") {
+			p.write(";")
+		}
+		p.item(item)
+		printed = true
+		p.lastLine = 0
+		if located(loc) {
+			p.lastLine = loc.End.Line
+			p.trailingComments(loc)
+		}
+	}
+	if located(end) {
+		printed = p.commentsBefore(end.End.Offset) || printed
+	}
+	return printed
+}
+
+// startLine starts the line of a list item, unless it is the first line of the output.
+func (p *printer) startLine() {
+	if p.out.Len() > 0 {
+		p.newline()
+	}
+}
+
+// commentsBefore prints the comments starting before an offset each on its own line, or all of them for a
+// negative offset. It reports whether any was printed.
+func (p *printer) commentsBefore(offset int) bool {
+	printed := false
+	for len(p.comments) > 0 && (offset < 0 || p.comments[0].Start.Offset < offset) {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+		if p.lastLine > 0 && comment.Start.Line > p.lastLine+1 {
+			p.write("\n")
+		}
+		p.startLine()
+		p.write(comment.Text)
+		p.lastLine = comment.End.Line
+		printed = true
+	}
+	return printed
+}
+
+// trailingComments prints after an item the comments inside of it that nested lists did not print, and the
+// comments starting on the line it ends on.
+func (p *printer) trailingComments(loc parser.Loc) {
+	for len(p.comments) > 0 {
+		comment := p.comments[0]
+		if comment.Start.Offset >= loc.End.Offset && comment.Start.Line != loc.End.Line {
+			return
+		}
+		p.comments = p.comments[1:]
+		p.write(" " + comment.Text)
+		p.lastLine = comment.End.Line
+	}
+}
+
+// located tells whether a range comes from the source, rather than from a node built by hand.
+func located(loc parser.Loc) bool {
+	return loc.End.Line > 0
+}
+
+// flatText prints a node with a flat printer to measure it, leaving the output and the comments untouched.
+// The text of each node is only computed once.
+func (p *printer) flatText(node parser.Node, print func(flat *printer)) string {
+	if text, ok := p.flatTexts[node]; ok {
+		return text
+	}
+	flat := &printer{config: p.config, comments: p.comments, flat: true, flatTexts: p.flatTexts}
+	print(flat)
+	text := flat.out.String()
+	p.flatTexts[node] = text
+	return text
+}
+
+// group prints the elements of a list between brackets, separated by commas. The list is kept on one line
+// when it fits in the width, and spans several lines otherwise, or when the elements do and multiline is set.
+// Broken lists put each element on its own line, followed by a comma if trailingComma is set.
+func (p *printer) group(node parser.Node, open string, close string, count int, options groupOptions,
+	element func(p *printer, idx int)) {
+	broken := false
+	if !p.flat && count > 0 {
+		text := p.flatText(node, func(flat *printer) { flat.group(node, open, close, count, options, element) })
+		first, _, multiline := strings.Cut(text, "\n")
+		width := utf8.RuneCountInString(first)
+		if !multiline {
+			width += options.tail
+		}
+		broken = p.column+width > p.config.Width || (multiline && options.multiline)
+	}
+
+	if !broken {
+		p.write(open)
+		if options.spaced && count > 0 {
+			p.write(" ")
+		}
+		for idx := 0; idx < count; idx++ {
+			if idx > 0 {
+				p.write(", ")
+			}
+			element(p, idx)
+		}
+		if options.spaced && count > 0 {
+			p.write(" ")
+		}
+		p.write(close)
+		return
+	}
+
+	p.write(open)
+	p.level++
+	for idx := 0; idx < count; idx++ {
+		p.newline()
+		element(p, idx)
+		if idx < count-1 || options.trailingComma {
+			p.write(",")
+		}
+	}
+	p.level--
+	p.newline()
+	p.write(close)
+}
+
+type groupOptions struct {
+	spaced        bool // Whether a list on one line has spaces inside the brackets (e.g., { a })
+	multiline     bool // Whether elements spanning several lines break the list
+	trailingComma bool // Whether the grammar allows a comma after the last element
+	tail          int  // Width of the text following the list on its line
+}
+
+func nodesOf[N parser.Node](list []N) []parser.Node {
+	nodes := make([]parser.Node, len(list))
+	for idx, node := range list {
+		nodes[idx] = node
+	}
+	return nodes
+}
+
+/**
+ * Statements
+ */
+
+// item prints a statement, or any other node that is not an expression.
+func (p *printer) item(node parser.Node) {
+	switch node := node.(type) {
+	case *parser.VariableDeclaration:
+		p.variableDeclaration(node)
+		p.semicolon()
+	case *parser.VariableDeclarator:
+		p.variableDeclarator(node)
+	case *parser.FunctionDeclaration:
+		p.function(node, node.Async, node.Generator, node.Name, node.Parameters, node.Body)
+	case *parser.BlockStatement:
+		p.block(node)
+	case *parser.ExpressionStatement:
+		// A statement starting with { or function would be a block or a declaration
+		if startsAmbiguously(node.Expression) {
+			p.write("(")
+			p.expression(node.Expression, parser.LOWEST)
+			p.write(")")
+		} else {
+			p.expression(node.Expression, parser.LOWEST)
+		}
+		p.semicolon()
+	case *parser.IfStatement:
+		p.ifStatement(node)
+	case *parser.WhileStatement:
+		p.write("while (")
+		p.expression(node.Condition, parser.LOWEST)
+		p.write(") ")
+		p.block(node.Body)
+	case *parser.ForStatement:
+		p.write("for (")
+		if node.Init != nil {
+			p.noIn = true
+			p.forLeft(node.Init)
+			p.noIn = false
+		}
+		p.write(";")
+		if node.Test != nil {
+			p.write(" ")
+			p.expression(node.Test, parser.LOWEST)
+		}
+		p.write(";")
+		if node.Update != nil {
+			p.write(" ")
+			p.expression(node.Update, parser.LOWEST)
+		}
+		p.write(")")
+		p.body(node.Body)
+	case *parser.ForInStatement:
+		p.write("for (")
+		p.forLeft(node.Left)
+		p.write(" in ")
+		p.expression(node.Right, parser.LOWEST)
+		p.write(")")
+		p.body(node.Body)
+	case *parser.ForOfStatement:
+		p.write("for ")
+		if node.Await {
+			p.write("await ")
+		}
+		p.write("(")
+		p.forLeft(node.Left)
+		p.write(" of ")
+		p.expression(node.Right, parser.ASSIGN)
+		p.write(")")
+		p.body(node.Body)
+	case *parser.SwitchStatement:
+		p.write("switch (")
+		p.expression(node.Expression, parser.LOWEST)
+		p.write(") {")
+		p.level++
+		printed := p.list(nodesOf(node.Cases), node.Loc)
+		p.level--
+		p.closeBrace(printed)
+	case *parser.CaseClause:
+		if node.Condition == nil {
+			p.write("default:")
+		} else {
+			p.write("case ")
+			p.expression(node.Condition, parser.LOWEST)
+			p.write(":")
+		}
+		p.level++
+		p.list(nodesOf(node.Consequent), node.Loc)
+		p.level--
+	case *parser.TryStatement:
+		p.write("try ")
+		p.block(node.Block)
+		if node.Handler != nil {
+			p.write(" ")
+			p.item(node.Handler)
+		}
+		if node.Finalizer != nil {
+			p.write(" finally ")
+			p.block(node.Finalizer)
+		}
+	case *parser.CatchClause:
+		p.write("catch ")
+		if node.Param != nil {
+			p.write("(" + node.Param.Value + ") ")
+		}
+		p.block(node.Body)
+	case *parser.ThrowStatement:
+		p.write("throw ")
+		p.expression(node.Argument, parser.LOWEST)
+		p.semicolon()
+	case *parser.ReturnStatement:
+		p.write("return")
+		if node.Value != nil {
+			p.write(" ")
+			p.expression(node.Value, parser.LOWEST)
+		}
+		p.semicolon()
+	case *parser.WithStatement:
+		p.write("with (")
+		p.expression(node.Object, parser.LOWEST)
+		p.write(")")
+		p.body(node.Body)
+	case *parser.LabeledStatement:
+		p.write(node.Label.Value + ":")
+		p.body(node.Body)
+	case *parser.EmptyStatement:
+		p.write(";")
+	case *parser.BreakStatement:
+		p.write("break")
+		p.label(node.Label)
+		p.semicolon()
+	case *parser.ContinueStatement:
+		p.write("continue")
+		p.label(node.Label)
+		p.semicolon()
+	case *parser.Property:
+		p.property(node)
+	case *parser.TemplateElement:
+		p.write(node.Raw)
+	default:
+		p.moduleItem(node)
+	}
+}
+
+func (p *printer) variableDeclaration(node *parser.VariableDeclaration) {
+	kind := node.Token.Text
+	if kind != "var" && kind != "let" && kind != "const" {
+		// Built by hand
+		kind = "let"
+		if node.IsConstant {
+			kind = "const"
+		}
+	}
+	p.write(kind + " ")
+	for idx, declarator := range node.Declarations {
+		if idx > 0 {
+			p.write(", ")
+		}
+		p.variableDeclarator(declarator)
+	}
+}
+
+// forLeft prints what precedes the first ";", "in" or "of" in the head of a for loop.
+func (p *printer) forLeft(node parser.Node) {
+	switch node := node.(type) {
+	case *parser.VariableDeclaration:
+		p.variableDeclaration(node)
+	case *parser.AssignmentExpression, *parser.SequenceExpression:
+		p.expression(node.(parser.Expression), parser.LOWEST)
+	case parser.Expression:
+		p.expression(node, parser.CALL)
+	}
+}
+
+func (p *printer) variableDeclarator(node *parser.VariableDeclarator) {
+	p.write(node.Name.Value)
+	if node.Value != nil {
+		p.write(" = ")
+		p.expression(node.Value, parser.ASSIGN)
+	}
+}
+
+func (p *printer) block(node *parser.BlockStatement) {
+	p.write("{")
+	p.level++
+	printed := p.list(nodesOf(node.Statements), node.Loc)
+	p.level--
+	p.closeBrace(printed)
+}
+
+// closeBrace ends a block on its own line, unless it is empty.
+func (p *printer) closeBrace(printed bool) {
+	if printed {
+		p.newline()
+	}
+	p.write("}")
+}
+
+// body prints the body of a statement after its head, on the same line.
+func (p *printer) body(body parser.Statement) {
+	if _, ok := body.(*parser.EmptyStatement); !ok {
+		p.write(" ")
+	}
+	p.item(body)
+}
+
+func (p *printer) ifStatement(node *parser.IfStatement) {
+	p.write("if (")
+	p.expression(node.Condition, parser.LOWEST)
+	p.write(") ")
+	p.block(node.Consequence)
+	if node.Alternative == nil {
+		return
+	}
+	p.write(" else ")
+	if len(node.Alternative.Statements) == 1 {
+		if elseIf, ok := node.Alternative.Statements[0].(*parser.IfStatement); ok {
+			p.ifStatement(elseIf)
+			return
+		}
+	}
+	p.block(node.Alternative)
+}
+
+func (p *printer) label(label *parser.Identifier) {
+	if label != nil {
+		p.write(" " + label.Value)
+	}
+}
+
+func (p *printer) function(node parser.Node, async bool, generator bool, name *parser.Identifier,
+	parameters []*parser.Identifier, body *parser.BlockStatement) {
+	if async {
+		p.write("async ")
+	}
+	p.write("function")
+	if generator {
+		p.write("*")
+	}
+	if name != nil {
+		p.write(" " + name.Value)
+	} else {
+		p.write(" ")
+	}
+	p.parameters(node, parameters)
+	p.write(" ")
+	p.block(body)
+}
+
+// parameters prints the parameters of a function, the node of which tells the list apart.
+func (p *printer) parameters(node parser.Node, parameters []*parser.Identifier) {
+	p.group(node, "(", ")", len(parameters), groupOptions{}, func(p *printer, idx int) {
+		p.write(parameters[idx].Value)
+	})
+}
+
+/**
+ * Modules
+ */
+
+func (p *printer) moduleItem(node parser.Node) {
+	switch node := node.(type) {
+	case *parser.ImportDeclaration:
+		p.write("import ")
+		if len(node.Specifiers) > 0 {
+			p.importSpecifiers(node, node.Specifiers, p.tail(node.Source))
+			p.write(" from ")
+		}
+		p.stringLiteral(node.Source)
+		p.semicolon()
+	case *parser.ExportNamedDeclaration:
+		p.write("export ")
+		if node.Declaration != nil {
+			p.item(node.Declaration)
+			return
+		}
+		options := groupOptions{spaced: true, trailingComma: true, tail: p.tail(node.Source)}
+		p.group(node, "{", "}", len(node.Specifiers), options, func(p *printer, idx int) {
+			p.specifier(node.Specifiers[idx].Local, node.Specifiers[idx].Exported)
+		})
+		if node.Source != nil {
+			p.write(" from ")
+			p.stringLiteral(node.Source)
+		}
+		p.semicolon()
+	case *parser.ExportDefaultDeclaration:
+		p.write("export default ")
+		switch declaration := node.Declaration.(type) {
+		case *parser.FunctionDeclaration:
+			p.item(declaration)
+		case parser.Expression:
+			// An expression starting with function would be a declaration
+			if _, ok := leftmost(declaration).(*parser.FunctionExpression); ok {
+				p.write("(")
+				p.expression(declaration, parser.LOWEST)
+				p.write(")")
+			} else {
+				p.expression(declaration, parser.ASSIGN)
+			}
+			p.semicolon()
+		}
+	case *parser.ExportAllDeclaration:
+		p.write("export *")
+		if node.Exported != nil {
+			p.write(" as " + node.Exported.Value)
+		}
+		p.write(" from ")
+		p.stringLiteral(node.Source)
+		p.semicolon()
+	case *parser.ImportDefaultSpecifier, *parser.ImportNamespaceSpecifier, *parser.ImportSpecifier:
+		p.importSpecifiers(node, []parser.Node{node}, 0)
+	case *parser.ExportSpecifier:
+		p.specifier(node.Local, node.Exported)
+	default:
+		panic("printer: unexpected node " + node.String())
+	}
+}
+
+// importSpecifiers prints the bindings of an import, the named ones between braces.
+func (p *printer) importSpecifiers(node parser.Node, specifiers []parser.Node, tail int) {
+	var bindings []string
+	var named []*parser.ImportSpecifier
+	for _, specifier := range specifiers {
+		switch specifier := specifier.(type) {
+		case *parser.ImportDefaultSpecifier:
+			bindings = append(bindings, specifier.Local.Value)
+		case *parser.ImportNamespaceSpecifier:
+			bindings = append(bindings, "* as "+specifier.Local.Value)
+		case *parser.ImportSpecifier:
+			named = append(named, specifier)
+		}
+	}
+	p.write(strings.Join(bindings, ", "))
+	if len(named) == 0 {
+		return
+	}
+	if len(bindings) > 0 {
+		p.write(", ")
+	}
+	options := groupOptions{spaced: true, trailingComma: true, tail: tail}
+	p.group(node, "{", "}", len(named), options, func(p *printer, idx int) {
+		p.specifier(named[idx].Imported, named[idx].Local)
+	})
+}
+
+// tail is the width of what follows the specifiers of an import or export: the source and the semicolon.
+func (p *printer) tail(source *parser.StringLiteral) int {
+	width := 0
+	if !p.config.OmitSemicolons {
+		width++
+	}
+	if source != nil {
+		text := p.flatText(source, func(flat *printer) { flat.stringLiteral(source) })
+		width += len(" from ") + utf8.RuneCountInString(text)
+	}
+	return width
+}
+
+// specifier prints a name with its alias, unless they are the same (e.g., a as b).
+func (p *printer) specifier(name *parser.Identifier, alias *parser.Identifier) {
+	p.write(name.Value)
+	if alias.Value != name.Value {
+		p.write(" as " + alias.Value)
+	}
+}
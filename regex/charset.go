@@ -0,0 +1,201 @@
+package regex
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// charSet is the set of characters a class matches. With the v flag a class may also match strings
+// of several characters (e.g., [\q{abc}]).
+type charSet struct {
+	has     func(rune) bool // nil for the empty set
+	strings []string
+}
+
+func (s *charSet) contains(r rune) bool {
+	return s.has != nil && s.has(r)
+}
+
+func (s *charSet) add(has func(rune) bool) {
+	if s.has == nil {
+		s.has = has
+		return
+	}
+	previous := s.has
+	s.has = func(r rune) bool { return previous(r) || has(r) }
+}
+
+// addAtom adds a class atom, either a single character or the set of a class escape.
+func (s *charSet) addAtom(char rune, set *charSet) {
+	if set != nil {
+		s.add(set.contains)
+		return
+	}
+	s.add(runeRange(char, char))
+}
+
+func (s *charSet) addString(text string) {
+	for _, existing := range s.strings {
+		if existing == text {
+			return
+		}
+	}
+	s.strings = append(s.strings, text)
+	// Longer strings are tried first, as a class matches the longest string it can
+	sort.SliceStable(s.strings, func(a, b int) bool { return len(s.strings[a]) > len(s.strings[b]) })
+}
+
+func (s *charSet) union(other *charSet) *charSet {
+	result := &charSet{has: s.has}
+	if other.has != nil {
+		result.add(other.has)
+	}
+	for _, text := range append(append([]string{}, s.strings...), other.strings...) {
+		result.addString(text)
+	}
+	return result
+}
+
+func (s *charSet) intersect(other *charSet) *charSet {
+	result := &charSet{has: func(r rune) bool { return s.contains(r) && other.contains(r) }}
+	for _, text := range s.strings {
+		if other.hasString(text) {
+			result.addString(text)
+		}
+	}
+	return result
+}
+
+func (s *charSet) subtract(other *charSet) *charSet {
+	result := &charSet{has: func(r rune) bool { return s.contains(r) && !other.contains(r) }}
+	for _, text := range s.strings {
+		if !other.hasString(text) {
+			result.addString(text)
+		}
+	}
+	return result
+}
+
+func (s *charSet) hasString(text string) bool {
+	for _, existing := range s.strings {
+		if existing == text {
+			return true
+		}
+	}
+	return false
+}
+
+func runeRange(lo, hi rune) func(rune) bool {
+	return func(r rune) bool { return r >= lo && r <= hi }
+}
+
+func complement(has func(rune) bool) func(rune) bool {
+	return func(r rune) bool { return has == nil || !has(r) }
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isWordChar(r rune) bool {
+	return isASCIILetter(r) || isDigit(r) || r == '_'
+}
+
+// isSpace reports whether a character is white space or a line terminator, the characters of \s.
+func isSpace(r rune) bool {
+	switch r {
+	case '\t', '\n', '\v', '\f', '\r', ' ', 0xA0, 0x1680, 0x2028, 0x2029, 0x202F, 0x205F, 0x3000, 0xFEFF:
+		return true
+	}
+	return r >= 0x2000 && r <= 0x200A
+}
+
+func isLineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == 0x2028 || r == 0x2029
+}
+
+// generalCategories maps the long names of the general categories to the names of the unicode package.
+var generalCategories = map[string]string{
+	"Letter": "L", "Uppercase_Letter": "Lu", "Lowercase_Letter": "Ll", "Titlecase_Letter": "Lt",
+	"Modifier_Letter": "Lm", "Other_Letter": "Lo",
+	"Mark": "M", "Combining_Mark": "M", "Spacing_Mark": "Mc", "Enclosing_Mark": "Me", "Nonspacing_Mark": "Mn",
+	"Number": "N", "Decimal_Number": "Nd", "digit": "Nd", "Letter_Number": "Nl", "Other_Number": "No",
+	"Punctuation": "P", "punct": "P", "Connector_Punctuation": "Pc", "Dash_Punctuation": "Pd",
+	"Close_Punctuation": "Pe", "Final_Punctuation": "Pf", "Initial_Punctuation": "Pi",
+	"Other_Punctuation": "Po", "Open_Punctuation": "Ps",
+	"Symbol": "S", "Currency_Symbol": "Sc", "Modifier_Symbol": "Sk", "Math_Symbol": "Sm", "Other_Symbol": "So",
+	"Separator": "Z", "Line_Separator": "Zl", "Paragraph_Separator": "Zp", "Space_Separator": "Zs",
+	"Other": "C", "Control": "Cc", "cntrl": "Cc", "Format": "Cf", "Private_Use": "Co", "Surrogate": "Cs",
+}
+
+// unicodeProperty returns the set of characters of a \p{...} escape: a general category (e.g., Lu or
+// General_Category=Letter), a script (e.g., Script=Greek) or a binary property (e.g., White_Space).
+func unicodeProperty(name string) (func(rune) bool, bool) {
+	if key, value, ok := strings.Cut(name, "="); ok {
+		switch key {
+		case "General_Category", "gc":
+			return generalCategory(value)
+		case "Script", "sc", "Script_Extensions", "scx":
+			if table, ok := unicode.Scripts[value]; ok {
+				return inTable(table), true
+			}
+		}
+		return nil, false
+	}
+
+	if has, ok := generalCategory(name); ok {
+		return has, true
+	}
+	switch name {
+	case "Any":
+		return func(r rune) bool { return true }, true
+	case "ASCII":
+		return func(r rune) bool { return r < 0x80 }, true
+	case "Assigned":
+		return isAssigned, true
+	case "Alphabetic":
+		return anyOf(unicode.Letter, unicode.Nl, unicode.Other_Alphabetic), true
+	case "Lowercase":
+		return anyOf(unicode.Ll, unicode.Other_Lowercase), true
+	case "Uppercase":
+		return anyOf(unicode.Lu, unicode.Other_Uppercase), true
+	}
+	if table, ok := unicode.Properties[name]; ok {
+		return inTable(table), true
+	}
+	return nil, false
+}
+
+func generalCategory(name string) (func(rune) bool, bool) {
+	if short, ok := generalCategories[name]; ok {
+		name = short
+	}
+	switch name {
+	case "LC", "Cased_Letter":
+		return anyOf(unicode.Lu, unicode.Ll, unicode.Lt), true
+	case "Cn", "Unassigned":
+		return complement(isAssigned), true
+	}
+	if table, ok := unicode.Categories[name]; ok {
+		return inTable(table), true
+	}
+	return nil, false
+}
+
+func isAssigned(r rune) bool {
+	for _, table := range unicode.Categories {
+		if unicode.Is(table, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func inTable(table *unicode.RangeTable) func(rune) bool {
+	return func(r rune) bool { return unicode.Is(table, r) }
+}
+
+func anyOf(tables ...*unicode.RangeTable) func(rune) bool {
+	return func(r rune) bool { return unicode.IsOneOf(tables, r) }
+}
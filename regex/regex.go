@@ -0,0 +1,438 @@
+// Package regex implements JavaScript regular expressions with a backtracking matcher, supporting what
+// the regexp package of the standard library cannot: lookarounds, backreferences and the JavaScript
+// flags. Positions are byte offsets in UTF-8 strings, every code point counts as a single character.
+package regex
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Flags are the flags of a regular expression.
+type Flags struct {
+	HasIndices  bool // d: matches record the positions of the groups
+	Global      bool // g: matches continue from the previous one
+	IgnoreCase  bool // i
+	Multiline   bool // m: ^ and $ match at line terminators
+	DotAll      bool // s: . matches line terminators
+	Unicode     bool // u: stricter syntax, \u{...} and \p{...}
+	UnicodeSets bool // v: u with set operations and strings in character classes
+	Sticky      bool // y: matches only at the start position
+}
+
+// ParseFlags parses the flags following a regular expression literal, each one may appear only once
+// and u and v exclude each other.
+func ParseFlags(text string) (Flags, error) {
+	var flags Flags
+	for _, c := range text {
+		var flag *bool
+		switch c {
+		case 'd':
+			flag = &flags.HasIndices
+		case 'g':
+			flag = &flags.Global
+		case 'i':
+			flag = &flags.IgnoreCase
+		case 'm':
+			flag = &flags.Multiline
+		case 's':
+			flag = &flags.DotAll
+		case 'u':
+			flag = &flags.Unicode
+		case 'v':
+			flag = &flags.UnicodeSets
+		case 'y':
+			flag = &flags.Sticky
+		}
+		if flag == nil || *flag {
+			return Flags{}, errors.New("Invalid flags")
+		}
+		*flag = true
+	}
+	if flags.Unicode && flags.UnicodeSets {
+		return Flags{}, errors.New("Invalid flags")
+	}
+	return flags, nil
+}
+
+// String returns the flags in their canonical order.
+func (f Flags) String() string {
+	var out strings.Builder
+	for _, flag := range []struct {
+		set  bool
+		name byte
+	}{
+		{f.HasIndices, 'd'}, {f.Global, 'g'}, {f.IgnoreCase, 'i'}, {f.Multiline, 'm'},
+		{f.DotAll, 's'}, {f.Unicode, 'u'}, {f.UnicodeSets, 'v'}, {f.Sticky, 'y'},
+	} {
+		if flag.set {
+			out.WriteByte(flag.name)
+		}
+	}
+	return out.String()
+}
+
+// Regexp is a compiled regular expression.
+type Regexp struct {
+	Source string
+	Flags  Flags
+	groups int
+	names  []string // The name of each capture group, empty for unnamed groups
+	match  matchFunc
+}
+
+// Compile parses a pattern with its flags, the error describes the invalid syntax.
+func Compile(source string, flags string) (*Regexp, error) {
+	parsedFlags, err := ParseFlags(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &patternParser{
+		pattern: []rune(source),
+		unicode: parsedFlags.Unicode || parsedFlags.UnicodeSets,
+		sets:    parsedFlags.UnicodeSets,
+		names:   map[string]int{},
+	}
+	tree, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	re := &Regexp{Source: source, Flags: parsedFlags, groups: p.groups, names: make([]string, p.groups+1)}
+	for name, index := range p.names {
+		re.names[index] = name
+	}
+	re.match = compile(tree, false)
+	return re, nil
+}
+
+// NumGroups returns the number of capture groups.
+func (re *Regexp) NumGroups() int {
+	return re.groups
+}
+
+// GroupNames returns the name of each capture group by index, empty for unnamed groups and the whole match.
+func (re *Regexp) GroupNames() []string {
+	return re.names
+}
+
+// HasNamedGroups reports whether any capture group is named.
+func (re *Regexp) HasNamedGroups() bool {
+	for _, name := range re.names {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAt finds the first match starting at or after start, or only at start for sticky expressions.
+// It returns the start and end offsets of the match and of each capture group, -1 for groups that
+// did not participate, or nil if there is no match.
+func (re *Regexp) FindAt(input string, start int) []int {
+	for pos := start; pos <= len(input); {
+		if captures := re.MatchAt(input, pos); captures != nil || re.Flags.Sticky {
+			return captures
+		}
+		if pos == len(input) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(input[pos:])
+		pos += size
+	}
+	return nil
+}
+
+// MatchAt matches the expression at the given position only, it returns the offsets like FindAt.
+func (re *Regexp) MatchAt(input string, pos int) []int {
+	m := &matcher{input: input, flags: re.Flags, captures: make([]int, 2*(re.groups+1))}
+	for idx := range m.captures {
+		m.captures[idx] = -1
+	}
+	matched := re.match(m, pos, func(end int) bool {
+		m.captures[0], m.captures[1] = pos, end
+		return true
+	})
+	if !matched {
+		return nil
+	}
+	return m.captures
+}
+
+// matcher holds the state of a match attempt.
+type matcher struct {
+	input    string
+	flags    Flags
+	captures []int
+}
+
+// matchFunc matches a node at a position, then calls the continuation with the position after it
+// and reports what the continuation reports. Matching backward, for lookbehinds, the position is the
+// end of the text the node matches. When a matchFunc fails, the captures are as they were before.
+type matchFunc func(m *matcher, pos int, k func(int) bool) bool
+
+// step reads the character after the position, or before it when matching backward.
+func (m *matcher) step(pos int, backward bool) (rune, int, bool) {
+	if backward {
+		if pos <= 0 {
+			return 0, pos, false
+		}
+		r, size := utf8.DecodeLastRuneInString(m.input[:pos])
+		return r, pos - size, true
+	}
+	if pos >= len(m.input) {
+		return 0, pos, false
+	}
+	r, size := utf8.DecodeRuneInString(m.input[pos:])
+	return r, pos + size, true
+}
+
+// equal compares two characters, ignoring case with the i flag.
+func (m *matcher) equal(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	if !m.flags.IgnoreCase {
+		return false
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+// inSet reports whether a character is in a set, or with the i flag any of its case variants.
+func (m *matcher) inSet(set *charSet, r rune) bool {
+	if set.contains(r) {
+		return true
+	}
+	if !m.flags.IgnoreCase {
+		return false
+	}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if set.contains(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchText matches a string at a position, forward or backward, and returns the position after it.
+func (m *matcher) matchText(text string, pos int, backward bool) (int, bool) {
+	if backward {
+		for len(text) > 0 {
+			want, size := utf8.DecodeLastRuneInString(text)
+			r, next, ok := m.step(pos, true)
+			if !ok || !m.equal(r, want) {
+				return 0, false
+			}
+			text, pos = text[:len(text)-size], next
+		}
+		return pos, true
+	}
+	for _, want := range text {
+		r, next, ok := m.step(pos, false)
+		if !ok || !m.equal(r, want) {
+			return 0, false
+		}
+		pos = next
+	}
+	return pos, true
+}
+
+func (m *matcher) isWordAt(pos int, backward bool) bool {
+	r, _, ok := m.step(pos, backward)
+	return ok && isWordChar(r)
+}
+
+// compile turns a node into its matchFunc. Inside lookbehinds nodes match backward: the terms of a
+// sequence are matched from the last one, only lookaheads match forward again.
+func compile(n node, backward bool) matchFunc {
+	switch n := n.(type) {
+	case *sequence:
+		return compileSequence(n.terms, backward)
+	case *alternation:
+		var alternatives []matchFunc
+		for _, alternative := range n.alternatives {
+			alternatives = append(alternatives, compile(alternative, backward))
+		}
+		return func(m *matcher, pos int, k func(int) bool) bool {
+			for _, alternative := range alternatives {
+				if alternative(m, pos, k) {
+					return true
+				}
+			}
+			return false
+		}
+	case *literal:
+		return func(m *matcher, pos int, k func(int) bool) bool {
+			r, next, ok := m.step(pos, backward)
+			return ok && m.equal(r, n.char) && k(next)
+		}
+	case *anyChar:
+		return func(m *matcher, pos int, k func(int) bool) bool {
+			r, next, ok := m.step(pos, backward)
+			return ok && (m.flags.DotAll || !isLineTerminator(r)) && k(next)
+		}
+	case *class:
+		return compileClass(n, backward)
+	case *assertion:
+		return compileAssertion(n.kind)
+	case *group:
+		return compileGroup(n, backward)
+	case *lookaround:
+		return compileLookaround(n)
+	case *backreference:
+		return func(m *matcher, pos int, k func(int) bool) bool {
+			start, end := m.captures[2*n.index], m.captures[2*n.index+1]
+			// A reference to a group that did not participate matches the empty string
+			if start < 0 || end < 0 {
+				return k(pos)
+			}
+			next, ok := m.matchText(m.input[start:end], pos, backward)
+			return ok && k(next)
+		}
+	case *repeat:
+		return compileRepeat(n, backward)
+	}
+	panic("regex: unknown node")
+}
+
+func compileSequence(terms []node, backward bool) matchFunc {
+	if len(terms) == 0 {
+		return func(m *matcher, pos int, k func(int) bool) bool { return k(pos) }
+	}
+	var first matchFunc
+	var rest []node
+	if backward {
+		first, rest = compile(terms[len(terms)-1], true), terms[:len(terms)-1]
+	} else {
+		first, rest = compile(terms[0], false), terms[1:]
+	}
+	if len(rest) == 0 {
+		return first
+	}
+	next := compileSequence(rest, backward)
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		return first(m, pos, func(p int) bool { return next(m, p, k) })
+	}
+}
+
+func compileClass(n *class, backward bool) matchFunc {
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		// The strings of a class are alternatives to its single characters, the longest first
+		for _, text := range n.set.strings {
+			if next, ok := m.matchText(text, pos, backward); ok && k(next) {
+				return true
+			}
+		}
+		r, next, ok := m.step(pos, backward)
+		return ok && m.inSet(n.set, r) != n.negated && k(next)
+	}
+}
+
+func compileAssertion(kind assertionKind) matchFunc {
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		var ok bool
+		switch kind {
+		case lineStart:
+			r, _, found := m.step(pos, true)
+			ok = !found || (m.flags.Multiline && isLineTerminator(r))
+		case lineEnd:
+			r, _, found := m.step(pos, false)
+			ok = !found || (m.flags.Multiline && isLineTerminator(r))
+		case wordBoundary:
+			ok = m.isWordAt(pos, true) != m.isWordAt(pos, false)
+		case notWordBoundary:
+			ok = m.isWordAt(pos, true) == m.isWordAt(pos, false)
+		}
+		return ok && k(pos)
+	}
+}
+
+func compileGroup(n *group, backward bool) matchFunc {
+	body := compile(n.body, backward)
+	if n.index == 0 {
+		return body
+	}
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		return body(m, pos, func(end int) bool {
+			start, stop := &m.captures[2*n.index], &m.captures[2*n.index+1]
+			previousStart, previousStop := *start, *stop
+			if backward {
+				*start, *stop = end, pos
+			} else {
+				*start, *stop = pos, end
+			}
+			if k(end) {
+				return true
+			}
+			*start, *stop = previousStart, previousStop
+			return false
+		})
+	}
+}
+
+// compileLookaround compiles a lookahead or lookbehind. They are atomic: once the body matched, the
+// match continues without backtracking into it. Negative ones never keep their captures.
+func compileLookaround(n *lookaround) matchFunc {
+	body := compile(n.body, n.behind)
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		saved := append([]int(nil), m.captures...)
+		matched := body(m, pos, func(int) bool { return true })
+		if matched == n.negative {
+			copy(m.captures, saved)
+			return false
+		}
+		if k(pos) {
+			return true
+		}
+		copy(m.captures, saved)
+		return false
+	}
+}
+
+// compileRepeat compiles a quantified atom. Each iteration starts with the captures of the atom reset,
+// and once the minimum is reached an iteration matching the empty string ends the repetition.
+func compileRepeat(n *repeat, backward bool) matchFunc {
+	body := compile(n.body, backward)
+
+	var iterate func(m *matcher, pos int, count int, k func(int) bool) bool
+	iterate = func(m *matcher, pos int, count int, k func(int) bool) bool {
+		if n.max != -1 && count >= n.max {
+			return k(pos)
+		}
+		attempt := func() bool {
+			saved := append([]int(nil), m.captures[2*n.firstGroup:2*n.lastGroup+2]...)
+			for idx := 2 * n.firstGroup; idx < 2*n.lastGroup+2; idx++ {
+				m.captures[idx] = -1
+			}
+			matched := body(m, pos, func(next int) bool {
+				if next == pos && count >= n.min {
+					return false
+				}
+				return iterate(m, next, count+1, k)
+			})
+			if !matched {
+				copy(m.captures[2*n.firstGroup:], saved)
+			}
+			return matched
+		}
+		if count < n.min {
+			return attempt()
+		}
+		if n.greedy {
+			return attempt() || k(pos)
+		}
+		return k(pos) || attempt()
+	}
+
+	return func(m *matcher, pos int, k func(int) bool) bool {
+		return iterate(m, pos, 0, k)
+	}
+}
@@ -0,0 +1,875 @@
+package regex
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Nodes of a parsed pattern.
+type (
+	alternation struct{ alternatives []node }
+	sequence    struct{ terms []node }
+	literal     struct{ char rune }
+	anyChar     struct{}
+	class       struct {
+		set     *charSet
+		negated bool
+	}
+	assertion struct{ kind assertionKind }
+	group     struct {
+		index int // The capture index, 0 for non-capturing groups
+		body  node
+	}
+	lookaround struct {
+		behind   bool
+		negative bool
+		body     node
+	}
+	backreference struct{ index int }
+	repeat        struct {
+		body       node
+		min, max   int // max is -1 when unbounded
+		greedy     bool
+		firstGroup int // The captures inside the body, reset by each iteration
+		lastGroup  int
+	}
+)
+
+type node interface{}
+
+type assertionKind int
+
+const (
+	lineStart assertionKind = iota
+	lineEnd
+	wordBoundary
+	notWordBoundary
+)
+
+// patternParser parses the syntax of a pattern, stricter with the u and v flags, where the
+// web compatibility leniencies (e.g., a lone ']' or an unknown escape as a literal) are errors.
+type patternParser struct {
+	pattern []rune
+	pos     int
+	unicode bool // Whether the u or v flag is set
+	sets    bool // Whether the v flag is set, which enables set operations in character classes
+	groups  int  // The number of capture groups opened so far
+	total   int  // The number of capture groups in the whole pattern, so \10 is known to be a reference
+	names   map[string]int
+}
+
+func (p *patternParser) eof() bool { return p.pos >= len(p.pattern) }
+
+func (p *patternParser) peek() rune { return p.pattern[p.pos] }
+
+func (p *patternParser) peekIs(text string) bool {
+	return strings.HasPrefix(string(p.pattern[p.pos:]), text)
+}
+
+func (p *patternParser) next() rune {
+	r := p.pattern[p.pos]
+	p.pos++
+	return r
+}
+
+// parse parses a whole pattern, its capture groups are counted and named beforehand.
+func (p *patternParser) parse() (node, error) {
+	if err := p.scanGroups(); err != nil {
+		return nil, err
+	}
+	n, err := p.parseDisjunction()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, errors.New("Unmatched ')'")
+	}
+	return n, nil
+}
+
+// scanGroups counts the capture groups of the pattern and records the names of the named ones.
+func (p *patternParser) scanGroups() error {
+	inClass := false
+	for i := 0; i < len(p.pattern); i++ {
+		switch p.pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '(':
+			if inClass {
+				continue
+			}
+			rest := string(p.pattern[i+1:])
+			if !strings.HasPrefix(rest, "?") {
+				p.total++
+				continue
+			}
+			if !strings.HasPrefix(rest, "?<") || strings.HasPrefix(rest, "?<=") || strings.HasPrefix(rest, "?<!") {
+				continue
+			}
+			p.total++
+			end := strings.IndexRune(rest, '>')
+			if end < 0 {
+				return errors.New("Invalid capture group name")
+			}
+			name := rest[2:end]
+			if _, ok := p.names[name]; ok {
+				return errors.New("Duplicate capture group name")
+			}
+			p.names[name] = p.total
+		}
+	}
+	return nil
+}
+
+func (p *patternParser) parseDisjunction() (node, error) {
+	var alternatives []node
+	for {
+		alternative, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, alternative)
+		if p.eof() || p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return &alternation{alternatives: alternatives}, nil
+}
+
+func (p *patternParser) parseAlternative() (node, error) {
+	seq := &sequence{}
+	for !p.eof() && p.peek() != '|' && p.peek() != ')' {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		seq.terms = append(seq.terms, term)
+	}
+	return seq, nil
+}
+
+// parseTerm parses an assertion, or an atom with an optional quantifier.
+func (p *patternParser) parseTerm() (node, error) {
+	firstGroup := p.groups
+	var atom node
+
+	switch c := p.next(); c {
+	case '^':
+		return &assertion{kind: lineStart}, nil
+	case '$':
+		return &assertion{kind: lineEnd}, nil
+	case '\\':
+		if !p.eof() && (p.peek() == 'b' || p.peek() == 'B') {
+			if p.next() == 'b' {
+				return &assertion{kind: wordBoundary}, nil
+			}
+			return &assertion{kind: notWordBoundary}, nil
+		}
+		escape, err := p.parseAtomEscape()
+		if err != nil {
+			return nil, err
+		}
+		atom = escape
+	case '(':
+		parsed, quantifiable, err := p.parseGroup()
+		if err != nil || !quantifiable {
+			return parsed, err
+		}
+		atom = parsed
+	case '.':
+		atom = &anyChar{}
+	case '[':
+		parsed, err := p.parseClass()
+		if err != nil {
+			return nil, err
+		}
+		atom = parsed
+	case '*', '+', '?':
+		return nil, errors.New("Nothing to repeat")
+	case '{':
+		if p.unicode {
+			return nil, errors.New("Lone quantifier brackets")
+		}
+		p.pos--
+		if _, _, ok := p.parseBraces(); ok {
+			return nil, errors.New("Nothing to repeat")
+		}
+		p.pos++
+		atom = &literal{char: c}
+	case '}', ']':
+		if p.unicode {
+			return nil, errors.New("Lone quantifier brackets")
+		}
+		atom = &literal{char: c}
+	default:
+		atom = &literal{char: c}
+	}
+
+	return p.parseQuantifier(atom, firstGroup)
+}
+
+// parseQuantifier parses the quantifier following an atom, if any.
+func (p *patternParser) parseQuantifier(atom node, firstGroup int) (node, error) {
+	if p.eof() {
+		return atom, nil
+	}
+	min, max := 0, -1
+	switch p.peek() {
+	case '*':
+		p.pos++
+	case '+':
+		min = 1
+		p.pos++
+	case '?':
+		max = 1
+		p.pos++
+	case '{':
+		var ok bool
+		if min, max, ok = p.parseBraces(); !ok {
+			if p.unicode {
+				return nil, errors.New("Incomplete quantifier")
+			}
+			// Without the u flag, a brace that does not start a quantifier is a literal
+			return atom, nil
+		}
+	default:
+		return atom, nil
+	}
+
+	greedy := true
+	if !p.eof() && p.peek() == '?' {
+		greedy = false
+		p.pos++
+	}
+	if max != -1 && min > max {
+		return nil, errors.New("numbers out of order in {} quantifier")
+	}
+	return &repeat{body: atom, min: min, max: max, greedy: greedy, firstGroup: firstGroup + 1,
+		lastGroup: p.groups}, nil
+}
+
+// parseBraces parses a {n}, {n,} or {n,m} quantifier, it leaves the position unchanged if there is none.
+func (p *patternParser) parseBraces() (int, int, bool) {
+	start := p.pos
+	p.pos++ // Consume '{'
+	min, ok := p.parseDecimal()
+	if !ok {
+		p.pos = start
+		return 0, 0, false
+	}
+	max := min
+	if !p.eof() && p.peek() == ',' {
+		p.pos++
+		max = -1
+		if value, ok := p.parseDecimal(); ok {
+			max = value
+		}
+	}
+	if p.eof() || p.peek() != '}' {
+		p.pos = start
+		return 0, 0, false
+	}
+	p.pos++
+	return min, max, true
+}
+
+// parseDecimal parses a decimal number, values too large to matter are clamped.
+func (p *patternParser) parseDecimal() (int, bool) {
+	value, digits := 0, 0
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		if value < 1<<30 {
+			value = value*10 + int(p.next()-'0')
+		} else {
+			p.pos++
+		}
+		digits++
+	}
+	return value, digits > 0
+}
+
+// parseGroup parses a group after its '(': a capture group, possibly named, a non-capturing group or a
+// lookaround assertion. Only lookaheads without the u flag may be quantified among the assertions.
+func (p *patternParser) parseGroup() (node, bool, error) {
+	index := 0
+	var look *lookaround
+	switch {
+	case p.peekIs("?:"):
+		p.pos += 2
+	case p.peekIs("?="), p.peekIs("?!"):
+		look = &lookaround{negative: p.pattern[p.pos+1] == '!'}
+		p.pos += 2
+	case p.peekIs("?<="), p.peekIs("?<!"):
+		look = &lookaround{behind: true, negative: p.pattern[p.pos+2] == '!'}
+		p.pos += 3
+	case p.peekIs("?<"):
+		p.pos += 2
+		name, err := p.parseGroupName()
+		if err != nil {
+			return nil, false, err
+		}
+		p.groups++
+		index = p.groups
+		if p.names[name] != index {
+			return nil, false, errors.New("Invalid capture group name")
+		}
+	case p.peekIs("?"):
+		return nil, false, errors.New("Invalid group")
+	default:
+		p.groups++
+		index = p.groups
+	}
+
+	body, err := p.parseDisjunction()
+	if err != nil {
+		return nil, false, err
+	}
+	if p.eof() || p.peek() != ')' {
+		return nil, false, errors.New("Unterminated group")
+	}
+	p.pos++
+
+	if look != nil {
+		look.body = body
+		return look, !look.behind && !p.unicode, nil
+	}
+	return &group{index: index, body: body}, true, nil
+}
+
+// parseGroupName parses the name of a named group or reference up to its closing '>'.
+func (p *patternParser) parseGroupName() (string, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != '>' {
+		c := p.next()
+		if !(c == '$' || c == '_' || unicode.IsLetter(c) || (p.pos-1 > start && unicode.IsDigit(c))) {
+			return "", errors.New("Invalid capture group name")
+		}
+	}
+	if p.eof() || p.pos == start {
+		return "", errors.New("Invalid capture group name")
+	}
+	name := string(p.pattern[start:p.pos])
+	p.pos++ // Consume '>'
+	return name, nil
+}
+
+// parseAtomEscape parses an escape outside a character class, after its '\'.
+func (p *patternParser) parseAtomEscape() (node, error) {
+	if p.eof() {
+		return nil, errors.New("\\ at end of pattern")
+	}
+
+	switch c := p.peek(); {
+	case c >= '1' && c <= '9':
+		start := p.pos
+		if n, _ := p.parseDecimal(); n <= p.total {
+			return &backreference{index: n}, nil
+		}
+		if p.unicode {
+			return nil, errors.New("Invalid escape")
+		}
+		// Without the u flag, a number that is not a reference is an octal escape, or \8 and \9 themselves
+		p.pos = start
+		if c >= '8' {
+			p.pos++
+			return &literal{char: c}, nil
+		}
+		return &literal{char: p.parseLegacyOctal()}, nil
+	case c == 'k' && (p.unicode || len(p.names) > 0):
+		p.pos++
+		if p.eof() || p.next() != '<' {
+			return nil, errors.New("Invalid named reference")
+		}
+		name, err := p.parseGroupName()
+		if err != nil {
+			return nil, errors.New("Invalid named reference")
+		}
+		index, ok := p.names[name]
+		if !ok {
+			return nil, errors.New("Invalid named capture referenced")
+		}
+		return &backreference{index: index}, nil
+	}
+
+	if set, ok, err := p.parseClassEscape(); ok || err != nil {
+		return &class{set: set}, err
+	}
+	char, err := p.parseCharacterEscape(false)
+	if err != nil {
+		return nil, err
+	}
+	return &literal{char: char}, nil
+}
+
+// parseClassEscape parses the escapes standing for a set of characters: \d, \s, \w, \p{...} and their
+// negations. It reports false for any other escape.
+func (p *patternParser) parseClassEscape() (*charSet, bool, error) {
+	c := p.peek()
+	var has func(rune) bool
+	switch c {
+	case 'd', 'D':
+		has = isDigit
+	case 's', 'S':
+		has = isSpace
+	case 'w', 'W':
+		has = isWordChar
+	case 'p', 'P':
+		if !p.unicode {
+			return nil, false, nil
+		}
+	default:
+		return nil, false, nil
+	}
+	p.pos++
+
+	if c == 'p' || c == 'P' {
+		property, err := p.parseUnicodeProperty()
+		if err != nil {
+			return nil, false, err
+		}
+		has = property
+	}
+	// The upper case escapes are the negations, e.g., \D
+	if unicode.IsUpper(c) {
+		has = complement(has)
+	}
+	return &charSet{has: has}, true, nil
+}
+
+// parseUnicodeProperty parses the {Name} or {Name=Value} of a \p escape.
+func (p *patternParser) parseUnicodeProperty() (func(rune) bool, error) {
+	if p.eof() || p.next() != '{' {
+		return nil, errors.New("Invalid property name")
+	}
+	start := p.pos
+	for !p.eof() && p.peek() != '}' {
+		p.pos++
+	}
+	if p.eof() {
+		return nil, errors.New("Invalid property name")
+	}
+	name := string(p.pattern[start:p.pos])
+	p.pos++ // Consume '}'
+	has, ok := unicodeProperty(name)
+	if !ok {
+		return nil, errors.New("Invalid property name")
+	}
+	return has, nil
+}
+
+// parseCharacterEscape parses an escape standing for a single character, after its '\'.
+func (p *patternParser) parseCharacterEscape(inClass bool) (rune, error) {
+	c := p.next()
+	switch c {
+	case 't':
+		return '\t', nil
+	case 'n':
+		return '\n', nil
+	case 'v':
+		return '\v', nil
+	case 'f':
+		return '\f', nil
+	case 'r':
+		return '\r', nil
+	case 'c':
+		if !p.eof() && isASCIILetter(p.peek()) {
+			return p.next() % 32, nil
+		}
+		if p.unicode {
+			return 0, errors.New("Invalid unicode escape")
+		}
+		// Without the u flag, \c not followed by a letter is a backslash followed by c
+		p.pos--
+		return '\\', nil
+	case '0':
+		if p.eof() || p.peek() < '0' || p.peek() > '9' {
+			return 0, nil
+		}
+		if p.unicode {
+			return 0, errors.New("Invalid decimal escape")
+		}
+		p.pos--
+		return p.parseLegacyOctal(), nil
+	case 'x':
+		if value, ok := p.parseHex(2); ok {
+			return value, nil
+		}
+		if p.unicode {
+			return 0, errors.New("Invalid escape")
+		}
+		return c, nil
+	case 'u':
+		if value, ok := p.parseUnicodeEscape(); ok {
+			return value, nil
+		}
+		if p.unicode {
+			return 0, errors.New("Invalid Unicode escape")
+		}
+		return c, nil
+	}
+
+	if inClass && c >= '1' && c <= '9' {
+		if p.unicode {
+			return 0, errors.New("Invalid class escape")
+		}
+		if c >= '8' {
+			return c, nil
+		}
+		p.pos--
+		return p.parseLegacyOctal(), nil
+	}
+	if p.unicode && !strings.ContainsRune(`^$\.*+?()[]{}|/`, c) && !(inClass && c == '-') {
+		return 0, errors.New("Invalid escape")
+	}
+	return c, nil
+}
+
+// parseLegacyOctal parses an octal escape of up to three digits, at most \377.
+func (p *patternParser) parseLegacyOctal() rune {
+	value := rune(0)
+	for digits := 0; digits < 3 && !p.eof() && p.peek() >= '0' && p.peek() <= '7'; digits++ {
+		if value*8+p.peek()-'0' > 0377 {
+			break
+		}
+		value = value*8 + p.next() - '0'
+	}
+	return value
+}
+
+func (p *patternParser) parseHex(digits int) (rune, bool) {
+	if p.pos+digits > len(p.pattern) {
+		return 0, false
+	}
+	value := rune(0)
+	for i := 0; i < digits; i++ {
+		digit, ok := hexValue(p.pattern[p.pos+i])
+		if !ok {
+			return 0, false
+		}
+		value = value*16 + digit
+	}
+	p.pos += digits
+	return value, true
+}
+
+// parseUnicodeEscape parses the rest of a \uXXXX escape, with the u flag also a \u{X...} escape or
+// a surrogate pair written as two escapes.
+func (p *patternParser) parseUnicodeEscape() (rune, bool) {
+	if p.unicode && !p.eof() && p.peek() == '{' {
+		start := p.pos
+		p.pos++
+		value := rune(0)
+		digits := 0
+		for !p.eof() && p.peek() != '}' {
+			digit, ok := hexValue(p.next())
+			if !ok || value > unicode.MaxRune {
+				p.pos = start
+				return 0, false
+			}
+			value = value*16 + digit
+			digits++
+		}
+		if p.eof() || digits == 0 || value > unicode.MaxRune {
+			p.pos = start
+			return 0, false
+		}
+		p.pos++
+		return value, true
+	}
+
+	value, ok := p.parseHex(4)
+	if !ok {
+		return 0, false
+	}
+	if p.unicode && value >= 0xD800 && value <= 0xDBFF && p.peekIs(`\u`) {
+		start := p.pos
+		p.pos += 2
+		if low, ok := p.parseHex(4); ok && low >= 0xDC00 && low <= 0xDFFF {
+			return (value-0xD800)<<10 + (low - 0xDC00) + 0x10000, true
+		}
+		p.pos = start
+	}
+	return value, true
+}
+
+// parseClass parses a character class after its '['.
+func (p *patternParser) parseClass() (node, error) {
+	negated := !p.eof() && p.peek() == '^'
+	if negated {
+		p.pos++
+	}
+
+	if p.sets {
+		set, err := p.parseClassSet()
+		if err != nil {
+			return nil, err
+		}
+		if negated && len(set.strings) > 0 {
+			return nil, errors.New("Negated character class may contain strings")
+		}
+		return &class{set: set, negated: negated}, nil
+	}
+
+	set := &charSet{}
+	for {
+		if p.eof() {
+			return nil, errors.New("Unterminated character class")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			break
+		}
+		lo, loSet, err := p.parseClassAtom()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos+1 >= len(p.pattern) || p.peek() != '-' || p.pattern[p.pos+1] == ']' {
+			set.addAtom(lo, loSet)
+			continue
+		}
+		p.pos++ // Consume '-'
+		hi, hiSet, err := p.parseClassAtom()
+		if err != nil {
+			return nil, err
+		}
+		if loSet != nil || hiSet != nil {
+			if p.unicode {
+				return nil, errors.New("Invalid character class")
+			}
+			// Without the u flag, a class escape cannot bound a range, so the '-' is a literal
+			set.addAtom(lo, loSet)
+			set.addAtom('-', nil)
+			set.addAtom(hi, hiSet)
+			continue
+		}
+		if lo > hi {
+			return nil, errors.New("Range out of order in character class")
+		}
+		set.add(runeRange(lo, hi))
+	}
+	return &class{set: set, negated: negated}, nil
+}
+
+// parseClassAtom parses a character of a class, or a class escape such as \d.
+func (p *patternParser) parseClassAtom() (rune, *charSet, error) {
+	c := p.next()
+	if c != '\\' {
+		return c, nil, nil
+	}
+	if p.eof() {
+		return 0, nil, errors.New("\\ at end of pattern")
+	}
+	if p.peek() == 'b' {
+		p.pos++
+		return '\b', nil, nil
+	}
+	if set, ok, err := p.parseClassEscape(); ok || err != nil {
+		return 0, set, err
+	}
+	char, err := p.parseCharacterEscape(true)
+	return char, nil, err
+}
+
+// parseClassSet parses the contents of a class with the v flag: a union of characters, ranges, strings
+// and nested classes, or an intersection (&&) or a subtraction (--) of operands.
+func (p *patternParser) parseClassSet() (*charSet, error) {
+	first, err := p.parseClassSetOperand(true)
+	if err != nil {
+		return nil, err
+	}
+	if first == nil {
+		if p.eof() {
+			return nil, errors.New("Unterminated character class")
+		}
+		p.pos++ // Consume ']' of an empty class
+		return &charSet{}, nil
+	}
+
+	if p.peekIs("&&") || p.peekIs("--") {
+		operator := string(p.pattern[p.pos : p.pos+2])
+		result := first
+		for p.peekIs(operator) {
+			p.pos += 2
+			operand, err := p.parseClassSetOperand(false)
+			if err != nil {
+				return nil, err
+			}
+			if operand == nil {
+				return nil, errors.New("Invalid set operation in character class")
+			}
+			if operator == "&&" {
+				result = result.intersect(operand)
+			} else {
+				result = result.subtract(operand)
+			}
+		}
+		if p.eof() || p.peek() != ']' {
+			return nil, errors.New("Invalid set operation in character class")
+		}
+		p.pos++
+		return result, nil
+	}
+
+	result := first
+	for {
+		if p.eof() {
+			return nil, errors.New("Unterminated character class")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return result, nil
+		}
+		if p.peekIs("&&") || p.peekIs("--") {
+			return nil, errors.New("Invalid set operation in character class")
+		}
+		operand, err := p.parseClassSetOperand(true)
+		if err != nil {
+			return nil, err
+		}
+		result = result.union(operand)
+	}
+}
+
+// parseClassSetOperand parses an operand of a class with the v flag, it returns nil at the closing ']'.
+// Ranges are only operands of unions.
+func (p *patternParser) parseClassSetOperand(rangeAllowed bool) (*charSet, error) {
+	if p.eof() || p.peek() == ']' {
+		return nil, nil
+	}
+
+	switch {
+	case p.peek() == '[':
+		p.pos++
+		negated := !p.eof() && p.peek() == '^'
+		if negated {
+			p.pos++
+		}
+		set, err := p.parseClassSet()
+		if err != nil {
+			return nil, err
+		}
+		if negated {
+			if len(set.strings) > 0 {
+				return nil, errors.New("Negated character class may contain strings")
+			}
+			set = &charSet{has: complement(set.contains)}
+		}
+		return set, nil
+	case p.peekIs(`\q{`):
+		p.pos += 3
+		return p.parseClassStrings()
+	case p.peekIs(`\`):
+		p.pos++
+		if p.eof() {
+			return nil, errors.New("\\ at end of pattern")
+		}
+		if p.peek() == 'b' {
+			p.pos++
+			return p.parseClassSetRange('\b', rangeAllowed)
+		}
+		if set, ok, err := p.parseClassEscape(); ok || err != nil {
+			return set, err
+		}
+		char, err := p.parseCharacterEscape(true)
+		if err != nil {
+			return nil, err
+		}
+		return p.parseClassSetRange(char, rangeAllowed)
+	}
+
+	c := p.next()
+	if strings.ContainsRune("()[]{}/-|", c) {
+		return nil, errors.New("Invalid character in character class")
+	}
+	return p.parseClassSetRange(c, rangeAllowed)
+}
+
+// parseClassSetRange parses the optional end of a range starting at the given character.
+func (p *patternParser) parseClassSetRange(lo rune, rangeAllowed bool) (*charSet, error) {
+	if !rangeAllowed || !p.peekIs("-") || p.peekIs("--") {
+		return &charSet{has: runeRange(lo, lo)}, nil
+	}
+	p.pos++ // Consume '-'
+	if p.eof() {
+		return nil, errors.New("Unterminated character class")
+	}
+	var hi rune
+	if c := p.next(); c == '\\' {
+		if p.eof() {
+			return nil, errors.New("\\ at end of pattern")
+		}
+		char, err := p.parseCharacterEscape(true)
+		if err != nil {
+			return nil, err
+		}
+		hi = char
+	} else if strings.ContainsRune("()[]{}/-|", c) {
+		return nil, errors.New("Invalid character class")
+	} else {
+		hi = c
+	}
+	if lo > hi {
+		return nil, errors.New("Range out of order in character class")
+	}
+	return &charSet{has: runeRange(lo, hi)}, nil
+}
+
+// parseClassStrings parses the alternatives of a \q{...} escape, the strings a class with the v flag
+// may contain.
+func (p *patternParser) parseClassStrings() (*charSet, error) {
+	set := &charSet{}
+	var current []rune
+	for {
+		if p.eof() {
+			return nil, errors.New("Invalid escape")
+		}
+		c := p.next()
+		switch c {
+		case '}', '|':
+			if len(current) == 1 {
+				set.add(runeRange(current[0], current[0]))
+			} else {
+				set.addString(string(current))
+			}
+			current = nil
+			if c == '}' {
+				return set, nil
+			}
+		case '\\':
+			if p.eof() {
+				return nil, errors.New("\\ at end of pattern")
+			}
+			char, err := p.parseCharacterEscape(true)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, char)
+		default:
+			current = append(current, c)
+		}
+	}
+}
+
+func hexValue(c rune) (rune, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func isASCIILetter(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -59,11 +59,14 @@ func StartREPL(i *interpreter.Interpreter) {
 		p := parser.New(l)
 
 		// Parse the input to create a program AST
-		program := p.ParseProgram()
+		program, errors := p.ParseProgram()
+		if len(errors) == 0 {
+			errors = parser.CheckEarlyErrors(program)
+		}
 
 		// Check for parsing errors
-		if len(p.Errors()) > 0 {
-			for _, e := range p.Errors() {
+		if len(errors) > 0 {
+			for _, e := range errors {
 				fmt.Println("Parsing error:", e)
 			}
 			continue
@@ -0,0 +1,338 @@
+package scope
+
+import (
+	"gojo/parser"
+)
+
+type analyzer struct {
+	info       *Info
+	scope      *Scope
+	references []*Reference            // Resolved once the whole program is declared
+	withs      []*Scope                // The scopes of the enclosing with statements, innermost last
+	withScopes map[*Reference][]*Scope // The scopes of the with statements enclosing each reference
+	strict     bool                    // Whether the code being analyzed is strict mode code
+	blocks     []*Declaration          // The functions declared in blocks of sloppy mode code, see hoistBlockFunction
+}
+
+func (a *analyzer) enterScope(t Type, node parser.Node) *Scope {
+	scope := &Scope{Type: t, Node: node, Parent: a.scope, names: map[string]*Declaration{}}
+	if a.scope != nil {
+		a.scope.Children = append(a.scope.Children, scope)
+	}
+	a.info.Scopes[node] = scope
+	a.scope = scope
+	return scope
+}
+
+func (a *analyzer) leaveScope() {
+	a.scope = a.scope.Parent
+}
+
+// declare declares a name in a scope, declaring it again adds an identifier to the same declaration.
+// A function declaration takes over a var or a parameter of the same name, and the last of several
+// function declarations is the one the name is initialized with.
+func (a *analyzer) declare(scope *Scope, identifier *parser.Identifier, kind Kind, node parser.Node) {
+	declaration, ok := scope.names[identifier.Value]
+	if !ok {
+		declaration = &Declaration{Name: identifier.Value, Kind: kind, Node: node, Scope: scope}
+		scope.names[identifier.Value] = declaration
+		scope.Declarations = append(scope.Declarations, declaration)
+	} else if kind == FunctionName && (declaration.Kind == Var || declaration.Kind == Parameter ||
+		declaration.Kind == FunctionName) {
+		declaration.Kind, declaration.Node = kind, node
+	}
+	declaration.Identifiers = append(declaration.Identifiers, identifier)
+	a.info.Declarations[identifier] = declaration
+}
+
+func (a *analyzer) reference(identifier *parser.Identifier, read bool, write bool) {
+	reference := &Reference{Identifier: identifier, Scope: a.scope, Read: read, Write: write}
+	a.references = append(a.references, reference)
+	a.info.References[identifier] = reference
+	if len(a.withs) > 0 {
+		a.withScopes[reference] = a.withs
+	}
+}
+
+// withinWith reports whether a with statement encloses a reference without enclosing its declaration,
+// the object of the with statement may then have a property of the same name.
+func (a *analyzer) withinWith(reference *Reference, declaration *Declaration) bool {
+	for _, with := range a.withScopes[reference] {
+		if declaration == nil {
+			return true
+		}
+		for scope := with; scope != nil; scope = scope.Parent {
+			if scope == declaration.Scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a *analyzer) statements(statements []parser.Statement) {
+	for _, stmt := range statements {
+		a.statement(stmt)
+	}
+}
+
+func (a *analyzer) statement(stmt parser.Statement) {
+	switch stmt := stmt.(type) {
+	case *parser.VariableDeclaration:
+		a.variableDeclaration(stmt)
+	case *parser.FunctionDeclaration:
+		a.declare(a.scope, stmt.Name, FunctionName, stmt)
+		if declaration := a.scope.names[stmt.Name.Value]; !a.strict && a.scope != a.scope.VariableScope() &&
+			!contains(a.blocks, declaration) {
+			a.blocks = append(a.blocks, declaration)
+		}
+		a.function(stmt, nil, stmt.Parameters, stmt.Body, stmt.Strict)
+	case *parser.BlockStatement:
+		a.enterScope(Block, stmt)
+		a.statements(stmt.Statements)
+		a.leaveScope()
+	case *parser.ExpressionStatement:
+		a.expression(stmt.Expression)
+	case *parser.IfStatement:
+		a.expression(stmt.Condition)
+		a.statement(stmt.Consequence)
+		if stmt.Alternative != nil {
+			a.statement(stmt.Alternative)
+		}
+	case *parser.WhileStatement:
+		a.expression(stmt.Condition)
+		a.statement(stmt.Body)
+	case *parser.ForStatement:
+		// The bindings of the head have their own scope
+		a.enterScope(Block, stmt)
+		switch init := stmt.Init.(type) {
+		case *parser.VariableDeclaration:
+			a.variableDeclaration(init)
+		case parser.Expression:
+			a.expression(init)
+		}
+		a.expression(stmt.Test)
+		a.expression(stmt.Update)
+		a.statement(stmt.Body)
+		a.leaveScope()
+	case *parser.ForInStatement:
+		a.enterScope(Block, stmt)
+		a.forLeft(stmt.Left)
+		a.expression(stmt.Right)
+		a.statement(stmt.Body)
+		a.leaveScope()
+	case *parser.ForOfStatement:
+		a.enterScope(Block, stmt)
+		a.forLeft(stmt.Left)
+		a.expression(stmt.Right)
+		a.statement(stmt.Body)
+		a.leaveScope()
+	case *parser.SwitchStatement:
+		// The case clauses form a single block
+		a.expression(stmt.Expression)
+		a.enterScope(Block, stmt)
+		for _, clause := range stmt.Cases {
+			a.expression(clause.Condition)
+			a.statements(clause.Consequent)
+		}
+		a.leaveScope()
+	case *parser.TryStatement:
+		a.statement(stmt.Block)
+		if stmt.Handler != nil {
+			// The body of the catch clause shares the scope of its parameter, which it cannot redeclare
+			a.enterScope(Catch, stmt.Handler)
+			if stmt.Handler.Param != nil {
+				a.declare(a.scope, stmt.Handler.Param, CatchParameter, stmt.Handler)
+			}
+			a.statements(stmt.Handler.Body.Statements)
+			a.leaveScope()
+		}
+		if stmt.Finalizer != nil {
+			a.statement(stmt.Finalizer)
+		}
+	case *parser.ThrowStatement:
+		a.expression(stmt.Argument)
+	case *parser.ReturnStatement:
+		a.expression(stmt.Value)
+	case *parser.WithStatement:
+		a.expression(stmt.Object)
+		withs := a.withs
+		a.withs = append(withs[:len(withs):len(withs)], a.scope)
+		a.statement(stmt.Body)
+		a.withs = withs
+	case *parser.LabeledStatement:
+		a.statement(stmt.Body)
+	case *parser.ImportDeclaration:
+		for _, specifier := range stmt.Specifiers {
+			switch specifier := specifier.(type) {
+			case *parser.ImportDefaultSpecifier:
+				a.declare(a.scope, specifier.Local, Import, stmt)
+			case *parser.ImportNamespaceSpecifier:
+				a.declare(a.scope, specifier.Local, Import, stmt)
+			case *parser.ImportSpecifier:
+				a.declare(a.scope, specifier.Local, Import, stmt)
+			}
+		}
+	case *parser.ExportNamedDeclaration:
+		if stmt.Declaration != nil {
+			a.statement(stmt.Declaration)
+		}
+		// Re-exported names are those of the source module
+		if stmt.Source == nil {
+			for _, specifier := range stmt.Specifiers {
+				a.reference(specifier.Local, true, false)
+			}
+		}
+	case *parser.ExportDefaultDeclaration:
+		switch declaration := stmt.Declaration.(type) {
+		case *parser.FunctionDeclaration:
+			if declaration.Name != nil {
+				a.declare(a.scope, declaration.Name, FunctionName, declaration)
+			}
+			a.function(declaration, nil, declaration.Parameters, declaration.Body, declaration.Strict)
+		case parser.Expression:
+			a.expression(declaration)
+		}
+	}
+}
+
+// variableDeclaration declares the names of a declaration, var ones in the enclosing function.
+func (a *analyzer) variableDeclaration(stmt *parser.VariableDeclaration) {
+	scope, kind := a.scope, Let
+	switch {
+	case stmt.IsConstant:
+		kind = Const
+	case stmt.Token.Type.Label == "var":
+		scope, kind = a.scope.VariableScope(), Var
+	}
+	for _, declarator := range stmt.Declarations {
+		a.declare(scope, declarator.Name, kind, stmt)
+		a.expression(declarator.Value)
+	}
+}
+
+// forLeft analyzes the left side of a for...in or for...of loop, which each iteration assigns.
+func (a *analyzer) forLeft(left parser.Node) {
+	switch left := left.(type) {
+	case *parser.VariableDeclaration:
+		a.variableDeclaration(left)
+	case parser.Expression:
+		a.assignmentTarget(left, false)
+	}
+}
+
+// function analyzes a function in its own scope. The name of a function expression is only declared there
+// when the parameters and the body do not declare it, as it is bound in a scope of its own around them.
+func (a *analyzer) function(node parser.Node, name *parser.Identifier, parameters []*parser.Identifier,
+	body parser.Node, strict bool) {
+	outerStrict := a.strict
+	a.strict = strict
+	defer func() { a.strict = outerStrict }()
+	scope := a.enterScope(Function, node)
+	for _, parameter := range parameters {
+		a.declare(scope, parameter, Parameter, node)
+	}
+	switch body := body.(type) {
+	case *parser.BlockStatement:
+		a.statements(body.Statements)
+	case parser.Expression:
+		a.expression(body)
+	}
+	if name != nil && scope.names[name.Value] == nil {
+		a.declare(scope, name, FunctionName, node)
+	}
+	a.leaveScope()
+}
+
+// hoistBlockFunction makes a function declared in a block of sloppy mode code a var of the enclosing function
+// too, which it is assigned to when its declaration runs (Annex B.3.3). It is not when that var would clash
+// with a let, const or function declared in a block in between, or with a let, const or import of the function.
+func hoistBlockFunction(declaration *Declaration) {
+	variableScope := declaration.Scope.VariableScope()
+	for scope := declaration.Scope.Parent; scope != variableScope; scope = scope.Parent {
+		if outer := scope.names[declaration.Name]; outer != nil && outer.Kind != CatchParameter {
+			return
+		}
+	}
+	if outer := variableScope.names[declaration.Name]; outer != nil && outer.Initial() == Uninitialized {
+		return
+	}
+	variableScope.BlockFunctions = append(variableScope.BlockFunctions, declaration)
+}
+
+// assignmentTarget records the variable an assignment writes, compound assignments read it too.
+func (a *analyzer) assignmentTarget(target parser.Expression, read bool) {
+	if identifier, ok := target.(*parser.Identifier); ok {
+		a.reference(identifier, read, true)
+		return
+	}
+	a.expression(target)
+}
+
+func (a *analyzer) expression(expr parser.Expression) {
+	switch expr := expr.(type) {
+	case *parser.Identifier:
+		a.reference(expr, true, false)
+	case *parser.AssignmentExpression:
+		a.assignmentTarget(expr.Left, expr.Operator != "=")
+		a.expression(expr.Value)
+	case *parser.UpdateExpression:
+		a.assignmentTarget(expr.Argument, true)
+	case *parser.ArrayLiteral:
+		for _, element := range expr.Elements {
+			a.expression(element)
+		}
+	case *parser.ObjectLiteral:
+		for _, property := range expr.Properties {
+			if property.Computed {
+				a.expression(property.Key)
+			}
+			a.expression(property.Value)
+		}
+	case *parser.BinaryExpression:
+		a.expression(expr.Left)
+		a.expression(expr.Right)
+	case *parser.PrefixExpression:
+		a.expression(expr.Right)
+	case *parser.ConditionalExpression:
+		a.expression(expr.Test)
+		a.expression(expr.Consequent)
+		a.expression(expr.Alternate)
+	case *parser.SequenceExpression:
+		for _, e := range expr.Expressions {
+			a.expression(e)
+		}
+	case *parser.MemberExpression:
+		a.expression(expr.Object)
+		if expr.Computed {
+			a.expression(expr.Property)
+		}
+	case *parser.CallExpression:
+		a.expression(expr.Function)
+		for _, argument := range expr.Arguments {
+			a.expression(argument)
+		}
+	case *parser.NewExpression:
+		a.expression(expr.Callee)
+		for _, argument := range expr.Arguments {
+			a.expression(argument)
+		}
+	case *parser.TemplateLiteral:
+		for _, e := range expr.Expressions {
+			a.expression(e)
+		}
+	case *parser.TaggedTemplateExpression:
+		a.expression(expr.Tag)
+		a.expression(expr.Quasi)
+	case *parser.FunctionExpression:
+		a.function(expr, expr.Name, expr.Parameters, expr.Body, expr.Strict)
+	case *parser.ArrowFunctionExpression:
+		a.function(expr, nil, expr.Parameters, expr.Body, expr.Strict)
+	case *parser.AwaitExpression:
+		a.expression(expr.Argument)
+	case *parser.YieldExpression:
+		a.expression(expr.Argument)
+	case *parser.ImportExpression:
+		a.expression(expr.Source)
+	}
+}
@@ -0,0 +1,240 @@
+// Package scope resolves the names of a program: it builds the tree of its scopes, the declarations of
+// each scope and the declaration every identifier refers to. It also finds the variables closures capture,
+// and how each declaration is initialized when its scope is entered, which is what hoisting amounts to.
+// gojo does not parse classes yet, so there are no class scopes.
+package scope
+
+import (
+	"fmt"
+	"gojo/parser"
+	"strings"
+)
+
+// Type is the kind of construct a scope belongs to.
+type Type int
+
+const (
+	Global   Type = iota // The top level of a script
+	Module               // The top level of an ES module
+	Function             // The parameters and body of a function
+	Block                // A block, a switch statement or the head of a for loop
+	Catch                // The parameter and body of a catch clause
+)
+
+var typeNames = [...]string{"global", "module", "function", "block", "catch"}
+
+func (t Type) String() string { return typeNames[t] }
+
+// Kind is how a name is declared.
+type Kind int
+
+const (
+	Var            Kind = iota
+	Let                 // let declarations
+	Const               // const declarations
+	FunctionName        // Function declarations, and the name of a named function expression in its own scope
+	Parameter           // Function parameters
+	CatchParameter      // The parameter of a catch clause
+	Import              // Import bindings
+)
+
+var kindNames = [...]string{"var", "let", "const", "function", "parameter", "catch parameter", "import"}
+
+func (k Kind) String() string { return kindNames[k] }
+
+// Initial is the state of a declaration when its scope is entered, before the declaration itself runs.
+type Initial int
+
+const (
+	Uninitialized  Initial = iota // let, const and imports, reading them throws a ReferenceError until declared
+	Undefined                     // var declarations
+	FunctionObject                // Function declarations, and the name of a function expression
+	Bound                         // Parameters and catch parameters, bound to the arguments or the exception
+)
+
+// Scope is a region of the program where declared names are visible.
+type Scope struct {
+	Type           Type
+	Node           parser.Node // The *Program, function, *BlockStatement, *SwitchStatement, for loop or *CatchClause
+	Parent         *Scope      // nil for the top level scope
+	Children       []*Scope
+	Declarations   []*Declaration // In the order of their first declaration
+	Captures       []*Declaration // For function scopes, the variables of enclosing functions referenced inside
+	BlockFunctions []*Declaration // Functions of nested blocks also bound to a var of the scope, see hoistBlockFunction
+	names          map[string]*Declaration
+}
+
+// VariableScope returns the scope var declarations go to: the enclosing function scope or the top level scope.
+func (s *Scope) VariableScope() *Scope {
+	scope := s
+	for scope.Type != Global && scope.Type != Module && scope.Type != Function {
+		scope = scope.Parent
+	}
+	return scope
+}
+
+// Lookup returns the declaration a name refers to in the scope, or nil for names the program does not declare.
+func (s *Scope) Lookup(name string) *Declaration {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if declaration, ok := scope.names[name]; ok {
+			return declaration
+		}
+	}
+	return nil
+}
+
+// Declaration is a name declared in a scope, possibly more than once (e.g., var a; var a).
+type Declaration struct {
+	Name        string
+	Kind        Kind
+	Identifiers []*parser.Identifier // The declaring identifiers, in source order
+	Node        parser.Node          // The node declaring the first identifier, e.g., a *VariableDeclaration or a function
+	Scope       *Scope
+	References  []*Reference
+	Captured    bool // Whether a function nested in the function of the declaration references it
+}
+
+// Initial returns the state of the declaration when its scope is entered.
+func (d *Declaration) Initial() Initial {
+	switch d.Kind {
+	case Var:
+		return Undefined
+	case FunctionName:
+		return FunctionObject
+	case Parameter, CatchParameter:
+		return Bound
+	}
+	return Uninitialized
+}
+
+// Reference is an identifier of an expression, reading or writing a variable.
+type Reference struct {
+	Identifier  *parser.Identifier
+	Scope       *Scope       // The scope the identifier is in
+	Declaration *Declaration // nil for names the program does not declare (e.g., globals)
+	Read        bool         // Whether the value of the variable is read, like in a += 1
+	Write       bool         // Whether the variable is assigned
+	Dynamic     bool         // Whether a with statement may bind the name to a property of its object instead
+}
+
+// Info is the result of the analysis of a program.
+type Info struct {
+	Root         *Scope
+	Scopes       map[parser.Node]*Scope
+	Declarations map[*parser.Identifier]*Declaration // By declaring identifier
+	References   map[*parser.Identifier]*Reference
+	Unresolved   []*Reference // The references to undeclared names, in source order
+}
+
+// Analyze builds the scopes of a program, resolving its references once all names are declared since
+// declarations are visible in their whole scope.
+func Analyze(program *parser.Program) *Info {
+	a := &analyzer{info: &Info{
+		Scopes:       map[parser.Node]*Scope{},
+		Declarations: map[*parser.Identifier]*Declaration{},
+		References:   map[*parser.Identifier]*Reference{},
+	}, withScopes: map[*Reference][]*Scope{}}
+
+	rootType := Global
+	if program.SourceType == parser.Module {
+		rootType = Module
+	}
+	a.strict = program.Strict
+	a.info.Root = a.enterScope(rootType, program)
+	a.statements(program.Statements)
+	a.leaveScope()
+	for _, declaration := range a.blocks {
+		hoistBlockFunction(declaration)
+	}
+
+	for _, reference := range a.references {
+		declaration := reference.Scope.Lookup(reference.Identifier.Value)
+		reference.Declaration = declaration
+		reference.Dynamic = a.withinWith(reference, declaration)
+		if declaration == nil {
+			a.info.Unresolved = append(a.info.Unresolved, reference)
+			continue
+		}
+		declaration.References = append(declaration.References, reference)
+		capture(reference.Scope.VariableScope(), declaration)
+	}
+	return a.info
+}
+
+// capture records a declaration as captured by the functions between the function of a reference and that of
+// the declaration.
+func capture(function *Scope, declaration *Declaration) {
+	for ; function != declaration.Scope.VariableScope(); function = function.Parent.VariableScope() {
+		declaration.Captured = true
+		if !contains(function.Captures, declaration) {
+			function.Captures = append(function.Captures, declaration)
+		}
+	}
+}
+
+func contains(declarations []*Declaration, declaration *Declaration) bool {
+	for _, d := range declarations {
+		if d == declaration {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the scope tree, one scope or declaration per line with the positions of its references, then
+// the references to undeclared names. Writes are marked with =, references a with statement may bind
+// differently with ?.
+func (i *Info) String() string {
+	var out strings.Builder
+	i.Root.write(&out, "")
+	if len(i.Unresolved) > 0 {
+		out.WriteString("unresolved")
+		for _, reference := range i.Unresolved {
+			out.WriteString(" " + reference.Identifier.Value + " " + reference.String())
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func (s *Scope) write(out *strings.Builder, indent string) {
+	fmt.Fprintf(out, "%s%s %s", indent, s.Type, s.Node.Location().Start)
+	for idx, declaration := range s.Captures {
+		if idx == 0 {
+			out.WriteString(" captures")
+		}
+		out.WriteString(" " + declaration.Name)
+	}
+	out.WriteString("\n")
+	for _, declaration := range s.Declarations {
+		fmt.Fprintf(out, "%s  %s %s %s", indent, declaration.Kind, declaration.Name,
+			declaration.Identifiers[0].Start)
+		if declaration.Captured {
+			out.WriteString(" captured")
+		}
+		for idx, reference := range declaration.References {
+			if idx == 0 {
+				out.WriteString(" refs")
+			}
+			out.WriteString(" " + reference.String())
+		}
+		out.WriteString("\n")
+	}
+	for _, declaration := range s.BlockFunctions {
+		fmt.Fprintf(out, "%s  block function %s %s\n", indent, declaration.Name, declaration.Identifiers[0].Start)
+	}
+	for _, child := range s.Children {
+		child.write(out, indent+"  ")
+	}
+}
+
+func (r *Reference) String() string {
+	text := r.Identifier.Start.String()
+	if r.Write {
+		text += "="
+	}
+	if r.Dynamic {
+		text += "?"
+	}
+	return text
+}
@@ -0,0 +1,22 @@
+let a = 1;
+var a = 2;
+const b = 3;
+function b() {}
+{
+  let c;
+  let c;
+}
+function f(p) {
+  let p;
+  {
+    var inner;
+  }
+  let inner;
+}
+try {
+} catch (e) {
+  let e;
+}
+for (let d of []) {
+  var d;
+}
@@ -0,0 +1,27 @@
+outer: while (true) {
+  outer: while (true) {
+    break outer;
+  }
+}
+block: {
+  continue block;
+}
+while (true) {
+  break missing;
+}
+break;
+continue;
+return 1;
+function f() {
+  loop: while (true) {
+    const g = () => {
+      break loop;
+    };
+  }
+  return new.target;
+}
+new.target;
+const o = { __proto__: null, "__proto__": null };
+const ok = { __proto__: null, __proto__() {}, ["__proto__"]: 1 };
+with (o) let x = 1;
+empty: empty: ;
@@ -0,0 +1,33 @@
+var a = 1;
+var a = 2;
+function a() {}
+{
+  function g() {}
+  function g() {}
+}
+function h(h) {
+  var h;
+  function i() {}
+  var i;
+}
+try {
+} catch (e) {
+  var e;
+}
+let s;
+switch (s) {
+  case 1: {
+    let t = 1;
+    break;
+  }
+  default: {
+    let t = 2;
+  }
+}
+one: two: while (true) {
+  continue one;
+  while (false) {
+    continue two;
+  }
+}
+label: function labeled() {}
@@ -0,0 +1,12 @@
+import a from "mod";
+import { b, c as d } from "mod";
+let a;
+const d = 1;
+function b() {}
+var e;
+{
+  function g() {}
+  function g() {}
+}
+export { e, f, a as default2 };
+export { z } from "mod";
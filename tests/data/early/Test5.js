@@ -0,0 +1,12 @@
+"use strict";
+{
+  function g() {}
+  function g() {}
+}
+label: function labeled() {}
+switch (1) {
+  case 1:
+    let shared;
+  default:
+    let shared;
+}
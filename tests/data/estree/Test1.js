@@ -0,0 +1,5 @@
+"use strict";
+const f = (a) => a ?? /x+/g;
+let o = { a, b: f`t${1}`, m() {} };
+let s = "h\u00e9 é \x41 \u{1F600}";
+let n = [0.5, 1e21, 0b11, 1e400];
@@ -0,0 +1,14 @@
+const base = await Promise.resolve(20);
+export let total = base * 2;
+export function helper() {
+  return "helper";
+}
+export default async function () {
+  return total;
+}
+total = total + await Promise.resolve(2);
+
+var named = helper();
+var metaType = typeof import.meta;
+var failed;
+await import("./missing.js").catch((e) => { failed = e instanceof Error && `${e}`; });
@@ -0,0 +1,35 @@
+function sloppyThis() {
+  return this;
+}
+function strictThis() {
+  "use strict";
+  return this;
+}
+var sloppyGlobal = sloppyThis() === globalThis;
+var strictUndefined = strictThis() === undefined;
+var scriptThis = this === globalThis;
+
+function leak() {
+  leaked = 5;
+}
+leak();
+globalThis.viaGlobal = 7;
+
+var strictError;
+function strictLeak() {
+  "use strict";
+  undeclared = 1;
+}
+try {
+  strictLeak();
+} catch (e) {
+  strictError = "" + e;
+}
+
+var point = { x: 1, y: 2 };
+var sum;
+with (point) {
+  x = 10;
+  sum = x + y;
+}
+var legacyOctal = 017;
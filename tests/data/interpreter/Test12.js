@@ -0,0 +1,27 @@
+var log = "";
+var n = 0;
+outer: while (n < 3) {
+  n = n + 1;
+  for (const x of [1, 2, 3]) {
+    if (x === 2) { continue outer; }
+    log = log + "a";
+  }
+}
+block: {
+  log = log + "b";
+  break block;
+  log = log + "never";
+}
+for (const y of [1, 2, 3, 4]) {
+  if (y === 2) { continue; }
+  if (y === 4) { break; }
+  log = log + "c";
+}
+var cleaned = 0;
+function* g() { try { yield 1; yield 2; } finally { cleaned = cleaned + 1; } }
+top: for (const a of [1, 2]) {
+  for (const b of g()) {
+    continue top;
+  }
+}
+switch (1) { case 1: { log = log + "s"; break; } }
@@ -0,0 +1,25 @@
+var date = /(?<year>\d{4})-(?<month>\d\d)-(?<day>\d\d)/;
+var match = date.exec("on: 2024-05-09");
+var year = match.groups.year;
+var day = match[3];
+var index = match.index;
+var swapped = "2024-05-09".replace(date, "$<day>/$2/$1");
+var capitals = { a: "A", b: "B", c: "C" };
+var upper = "a-b-c".replace(/[a-z]/g, (letter) => capitals[letter]);
+var parts = "";
+for (const part of "a1b2c".split(/(\d)/)) {
+  parts = parts + part + "|";
+}
+var found = "";
+for (const m of "1a 2b".matchAll(/(\d)([a-z])/g)) {
+  found = found + m[0];
+}
+var price = /(?<=\$)\d+/.exec("costs $42")[0];
+var failed;
+try { new RegExp("("); } catch (e) { failed = "" + e; }
+var source = new RegExp("a/b").source;
+var global = /x/g;
+global.test("xx");
+global.test("xx");
+var third = global.test("xx");
+var position = global.lastIndex;
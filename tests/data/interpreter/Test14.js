@@ -0,0 +1,26 @@
+var params = "";
+function sql(strings, id, name) {
+  params = String(id) + "," + name;
+  return strings[0] + "$1" + strings[1] + "$2" + strings[2];
+}
+var id = 7;
+var name = "ann";
+var query = sql`select * from users where id = ${id} and name = ${name}`;
+var greeting = `hello ${name}, you are ${id}`;
+
+function strings(s) { return s; }
+function site() { return strings`same`; }
+var cached = site() === site();
+var distinct = strings`same` === site();
+
+var template = strings`a${1}`;
+template[0] = "changed";
+var frozen = template[0];
+
+var raw = String.raw`C:\new\x${1}`;
+function check(s) { return s[0] === undefined && s.raw[0] === "\\unicode"; }
+var invalid = check`\unicode`;
+var db = { name: "db", tag: function (s, v) { return this.name + s[0] + String(v); } };
+var method = db.tag`:${id}`;
+var multiline = `one
+two`;
@@ -0,0 +1,42 @@
+function kind(x) {
+  var out = "";
+  switch (x) {
+    default:
+      out = out + "d";
+    case 1:
+      out = out + "1";
+    case 2:
+      out = out + "2";
+      break;
+    case "3":
+      let word = "s";
+      out = out + word;
+      break;
+  }
+  return out;
+}
+var one = kind(1);
+var two = kind(2);
+var three = kind("3");
+var other = kind(9);
+
+var evaluated = 0;
+function next() { evaluated = evaluated + 1; return evaluated; }
+var matched = "";
+switch (2) {
+  case next():
+    matched = matched + "a";
+  case next():
+    matched = matched + "b";
+  case next():
+    matched = matched + "c";
+}
+
+var loops = 0;
+outer: while (loops < 5) {
+  loops = loops + 1;
+  switch (loops) {
+    case 3:
+      break outer;
+  }
+}
@@ -0,0 +1,60 @@
+function Point(x) {
+  this.x = x;
+}
+Point.prototype.scale = 2;
+var point = new Point(1);
+
+var hasX = "x" in point;
+var hasScale = "scale" in point;
+var hasY = "y" in point;
+var inArray = 1 in [4, 5] && !(2 in [4, 5]);
+var isPoint = point instanceof Point;
+var isNotPoint = {} instanceof Point;
+var primitive = 1 instanceof Point;
+
+var Even = {};
+Even[Symbol.hasInstance] = function (n) {
+  return n % 2 === 0;
+};
+var even = 4 instanceof Even;
+var odd = 3 instanceof Even;
+
+var voided = void point.x;
+
+var pairs = "";
+for (var i = 0, j = 3; i < j; i++, j--) {
+  pairs += `${i}${j};`;
+}
+
+var captured = [0, 0, 0];
+for (let n = 0; n < 3; n++) {
+  captured[n] = () => n;
+}
+var captures = `${captured[0]()}${captured[1]()}${captured[2]()}`;
+
+var keys = "";
+for (var key in point) {
+  keys += key;
+}
+
+var skipped = "";
+var letters = { a: 1, b: 2, c: 3 };
+for (const letter in letters) {
+  delete letters.b;
+  skipped += letter;
+}
+
+var found = 0;
+for (var start = ("x" in point) ? 1 : 0; ; start++) {
+  if (start === 3) {
+    break;
+  }
+  found = start;
+}
+
+var thrown = "";
+try {
+  "x" in 1;
+} catch (e) {
+  thrown = "" + e;
+}
@@ -0,0 +1,104 @@
+var sum = add(1, 2);
+function add(a, b) {
+  return a + b;
+}
+
+var before = typeof later;
+var later = 1;
+
+var early = "";
+try {
+  early = limit;
+} catch (e) {
+  early = "" + e;
+}
+const limit = 10;
+
+var assigned = "";
+try {
+  count = 1;
+} catch (e) {
+  assigned = e instanceof ReferenceError && e instanceof Error ? e.message : "";
+}
+let count = 0;
+
+var custom = new TypeError("bad input");
+var errorParts = custom.name + "|" + custom.message + "|" + (custom instanceof Error) + "|" +
+  (custom instanceof RangeError) + "|" + RangeError("called").toString() + "|" + new Error();
+
+function keep(a) {
+  var a;
+  return a;
+}
+var kept = keep(5);
+
+function outer() {
+  return inner();
+  function inner() {
+    return "inner";
+  }
+}
+var nested = outer();
+
+{
+  let scoped = 1;
+  var blockFunction = local();
+  function local() {
+    return scoped;
+  }
+}
+var leaked = typeof scoped;
+
+if (true) {
+  function sloppy() {
+    return "sloppy";
+  }
+}
+var annex = sloppy();
+
+var caught = 0;
+try {
+  throw 1;
+} catch (caught) {
+  var caught = 2;
+}
+
+var factorial = function fact(n) {
+  return n <= 1 ? 1 : n * fact(n - 1);
+};
+var named = factorial(5);
+
+function kind() {
+  let shadow = "let";
+  {
+    function shadow() {}
+  }
+  return shadow;
+}
+var shadowKind = kind();
+
+var undeclared = "";
+try {
+  missing;
+} catch (e) {
+  undeclared = e instanceof ReferenceError ? e.message : "";
+}
+
+var constant = "";
+try {
+  limit = 11;
+} catch (e) {
+  constant = e instanceof TypeError ? e.message : "";
+}
+
+var text = "abc";
+text.size = 3;
+var sloppyProperty = typeof text.size;
+var strictProperty = (function () {
+  "use strict";
+  try {
+    text.size = 3;
+  } catch (e) {
+    return "" + e;
+  }
+})();
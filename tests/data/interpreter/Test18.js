@@ -0,0 +1,34 @@
+var kinds = [typeof undefined, typeof null, typeof true, typeof 1, typeof "", typeof Symbol(), typeof BigInt(1),
+  typeof {}, typeof [], typeof console.log];
+var types = `${kinds}`;
+
+var big = BigInt("123456789012345678901234567890") * BigInt(10) + BigInt(1);
+var bigText = `${big}`;
+var bigEqual = BigInt(7) === BigInt("7");
+var bigFalsy = !BigInt(0);
+var bigLess = BigInt(-2) < BigInt(1);
+var bigLiteral = `${10n ** 20n + 0xffn},${typeof 0b1n},${{ 2n: "two" }[2]}`;
+var bigError;
+try {
+  BigInt("1.5");
+} catch (e) {
+  bigError = "" + e;
+}
+
+var root = Math.sqrt(4);
+var sameNumber = root === 2;
+var sameArray = [1] === [1];
+var resolvers = [];
+new Promise((resolve) => { resolvers[0] = resolve; });
+new Promise((resolve) => { resolvers[1] = resolve; });
+var sameBuiltin = `${resolvers[0] === resolvers[1]},${resolvers[0] === resolvers[0]},${console.log === console.log}`;
+
+var list = [1, 2];
+list[3] = 4;
+var grown = list.length;
+var hole = list[2];
+list.length = 1;
+var truncated = `${list};${list.length}`;
+var aliased = list;
+aliased[0] = "first";
+var shared = list[0];
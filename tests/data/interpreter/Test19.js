@@ -0,0 +1,38 @@
+var half = 5 / 2;
+var infinite = 1 / 0;
+var negativeZero = 1 / -0;
+var notANumber = 0 / 0;
+var nanUnequal = notANumber === notANumber;
+var zeros = 0 === -0;
+var unsigned = -1 >>> 0;
+var power = 2 ** 3 ** 2;
+var reciprocal = 2 ** -1;
+var oneToInfinity = 1 ** infinite;
+var oneToNaN = oneToInfinity !== oneToInfinity;
+var remainder = -7 % 3;
+var wrapped = 2147483648 | 0;
+var shifted = 1 << 33;
+var signed = -16 >> 2;
+var inverted = ~5;
+var sum = 0.1 + 0.2;
+var large = `${1e21}`;
+var small = `${1e-7}`;
+var fraction = 1.5 + 0x10;
+var coerced = "3" * "4";
+var concatenated = 1 + "2";
+var counter = "5";
+counter++;
+var ordered = 0 / 0 < 1 || 0 / 0 >= 1;
+var globalNumbers = NaN !== NaN && Infinity === 1 / 0 && -Infinity === negativeZero;
+NaN = 1;
+Infinity = 2;
+globalThis.NaN = 3;
+var readOnly = `${NaN},${Infinity}`;
+var strictReadOnly = (function () {
+  "use strict";
+  try {
+    Infinity = 0;
+  } catch (e) {
+    return "" + e;
+  }
+})();
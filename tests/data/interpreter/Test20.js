@@ -0,0 +1,22 @@
+var cleaned = 0;
+function* numbers() {
+  let n = 0;
+  try {
+    while (true) {
+      yield n++;
+    }
+  } finally {
+    cleaned++;
+  }
+}
+async function forever() {
+  await new Promise(() => {});
+  cleaned++;
+}
+var started = 0;
+while (started < 1000) {
+  let it = numbers();
+  it.next();
+  forever();
+  started++;
+}
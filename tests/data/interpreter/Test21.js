@@ -0,0 +1,17 @@
+// Loose equality converts the operands it compares
+var nullish = null == undefined && undefined == null && null != 0 && undefined != false;
+var numeric = 1 == "1" && 0 == false && "" == 0 && " 1 " == true && "1" != "01";
+var notANumber = NaN != NaN && NaN != "NaN";
+var bigInts = 1n == 1 && 1 == 1n && 2n == "2" && 1n != 1.5 && 0n == false && 1n != NaN;
+var wrapped = { valueOf() { return 5; } };
+var labelled = { toString() { return "gojo"; } };
+var objects = wrapped == 5 && "5" == wrapped && labelled == "gojo" && [1, 2] == "1,2" && wrapped != labelled;
+var symbol = Symbol("s");
+var symbols = symbol == symbol && symbol != "s";
+var opaque = { valueOf() { return {}; }, toString() { return {}; } };
+var unconvertible;
+try {
+  opaque == 1;
+} catch (e) {
+  unconvertible = `${e}`;
+}
@@ -0,0 +1,9 @@
+var a = 2 ** 3 ** 2;
+var b = a > 100 ? "big" : "small";
+let c = 1;
+c += 4;
+c **= 2;
+var d = null ?? "default";
+var e = typeof d;
+var f = -c++;
+var g = 1 === 1 && 2 !== 3;
@@ -0,0 +1,16 @@
+var m = [[1, 2], [3, 4]];
+var x = m[1][0];
+m[0][1] = 9;
+m[0][1] = m[0][1] + 1;
+var y = m[0][1];
+var fns = [Math.pow];
+var z = fns[0](2, 3);
+var w = Math["pow"](3, 2);
+var len = m[0].length + "abc".length;
+var c = "abc"[1];
+var missing = "";
+try {
+  m[5][0];
+} catch (e) {
+  missing = "" + e;
+}
@@ -0,0 +1,47 @@
+function Point(x, y) {
+  this.x = x;
+  this.y = y;
+}
+function sum() {
+  return this.x + this.y;
+}
+Point.prototype.sum = sum;
+
+var p = new Point(3, 4);
+var total = p.sum();
+var isPoint = p.constructor === Point;
+
+function Wrapper() {
+  this.ignored = true;
+  return { wrapped: "yes" };
+}
+var wrapped = new Wrapper().wrapped;
+var ignored = new Wrapper().ignored;
+
+function Counter() {
+  this.count = 1;
+  return 5;
+}
+var count = new Counter().count;
+
+function Target() {
+  this.matches = new.target === Target;
+}
+var matches = new Target().matches;
+
+var base = { greeting: "hi" };
+var child = { __proto__: base, name: "child", toString() { return this.name; } };
+var greeting = child.greeting;
+
+var arrowError = "";
+try {
+  new (() => 1)();
+} catch (e) {
+  arrowError = "" + e;
+}
+var methodError = "";
+try {
+  new child.toString();
+} catch (e) {
+  methodError = "" + e;
+}
@@ -0,0 +1,40 @@
+function* count(n) {
+  var i = 0;
+  while (i < n) {
+    var received = yield i;
+    if (received) {
+      i = received;
+    }
+    i = i + 1;
+  }
+  return "done";
+}
+var it = count(5);
+var first = it.next().value;
+var skipped = it.next(3).value;
+var finished = it.next();
+var result = finished.value;
+var done = finished.done;
+
+function* inner() {
+  yield 1;
+  return "inner";
+}
+var outer = function* () {
+  var r = yield* inner();
+  yield r;
+  yield* [2, 3];
+};
+var o = outer();
+var d1 = o.next().value;
+var d2 = o.next().value;
+var d3 = o.next().value;
+var d4 = o.next().value;
+
+var obj = { a: 42, *items() { yield this.a; } };
+var fromMethod = obj.items().next().value;
+
+var early = count(10);
+early.next();
+var returned = early.return(7).value;
+var afterReturn = early.next().done;
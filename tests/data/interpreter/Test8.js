@@ -0,0 +1,48 @@
+function delay(value) {
+  return new Promise((resolve) => resolve(value));
+}
+async function add(a, b) {
+  const x = await delay(a);
+  const y = await b;
+  return x + y;
+}
+async function fails() {
+  await null;
+  throw "bad";
+}
+async function recovers() {
+  try {
+    await fails();
+    return "unreachable";
+  } catch (e) {
+    return "caught " + e;
+  }
+}
+
+var sum;
+add(1, 2).then((v) => { sum = v; });
+var recovered;
+recovers().then(function (v) { recovered = v; });
+var rejected;
+Promise.reject("no").catch((e) => { rejected = e; });
+
+var order = "";
+Promise.resolve("b").then((v) => { order = order + v; });
+order = order + "a";
+
+var counter = 0;
+const increment = () => (counter = counter + 1);
+increment();
+increment();
+
+var obj = { n: 5, getter() { return () => this.n; } };
+var arrowThis = obj.getter()();
+
+var syncCatch;
+try {
+  throw "sync";
+} catch (e) {
+  syncCatch = e;
+} finally {
+  counter = counter + 10;
+}
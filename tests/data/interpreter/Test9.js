@@ -0,0 +1,60 @@
+async function* countdown(n) {
+  try {
+    while (n > 0) {
+      yield await Promise.resolve(n);
+      n = n - 1;
+    }
+    return "liftoff";
+  } finally {
+    closed = true;
+  }
+}
+
+var closed = false;
+var total = 0;
+var early = 0;
+async function consume() {
+  for await (const n of countdown(3)) {
+    total = total + n;
+  }
+  for await (const v of [10, Promise.resolve(20)]) {
+    total = total + v;
+  }
+  for await (const n of countdown(5)) {
+    early = n;
+    break;
+  }
+}
+consume();
+
+var gen = countdown(1);
+var first = gen.next();
+var second = gen.next();
+var third = gen.next();
+var queued;
+var last;
+var exhausted;
+first.then((r) => { queued = r.value; });
+second.then((r) => { last = r.value; });
+third.then((r) => { exhausted = r.done; });
+
+var source = {
+  [Symbol.asyncIterator]() {
+    var i = 0;
+    return { next() { i = i + 1; return Promise.resolve({ value: i, done: i > 2 }); } };
+  }
+};
+var custom = 0;
+(async () => {
+  for await (const x of source) {
+    custom = custom + x;
+  }
+})();
+
+var letters = "";
+for (const ch of "abc") {
+  if (ch === "c") {
+    break;
+  }
+  letters = letters + ch;
+}
@@ -0,0 +1,2 @@
+var a = 10n + 0xFFn;
+var b = 1.5n;
@@ -1,2 +1,4 @@
 var str = "Hello, world!\n";
 var escapedStr = "This is a \"quoted\" string.";
+var cooked = "\x41\u0042\u{1F600}\uD83D\uDE00 é\
+";
@@ -0,0 +1 @@
+var t = tag`a\n${x}b${ {y: 1} }\u{41}`;
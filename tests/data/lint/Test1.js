@@ -0,0 +1,20 @@
+const limit = 10;
+limit = 20;
+let total = 0;
+total = total + limit;
+let count = 0;
+count = 1;
+var declared;
+
+function sum(a, b, c) {
+  return b;
+}
+sum(1, 2);
+
+function unused() {}
+const named = function inner() {};
+try {
+  named();
+} catch (error) {}
+
+for (const item of [1, 2]) {}
@@ -0,0 +1,33 @@
+function check(a, b) {
+  if (a == b) {
+    return a != b;
+    a = b;
+  }
+  if (a === b) {
+    throw a;
+  } else {
+    return b;
+  }
+  var later;
+  function hoisted() {}
+  check(a, b);
+}
+
+function pick(x) {
+  switch (x) {
+    case 1:
+      break;
+      x = x + 1;
+    case "a":
+    case x + 1:
+      return 2;
+    case 1:
+    case x + 1:
+    case 0x1:
+    case "\x61":
+    case "1":
+    default:
+      return 3;
+  }
+}
+check(pick(1), 2);
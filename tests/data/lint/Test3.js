@@ -0,0 +1,22 @@
+let value = 1;
+function outer(value) {
+  let inner = value;
+  {
+    let inner = 2;
+    inner = inner + 1;
+  }
+  for (const value of [inner]) {
+    value;
+  }
+  try {} catch (value) {}
+  return inner;
+}
+outer();
+
+let a = 1; // gojo-lint-disable-line
+let b = a == 1; // gojo-lint-disable-line no-unused-vars
+// gojo-lint-disable-next-line eqeqeq, no-unused-vars
+let c = b != 2;
+/* gojo-lint-disable-next-line no-shadow */
+function f(value) { return value; }
+f(value);
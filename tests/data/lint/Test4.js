@@ -0,0 +1,11 @@
+import { used, unused } from "m";
+import * as namespace from "n";
+
+export const exported = 1;
+export function run() {
+  return used;
+}
+export default function main() {}
+const local = 2;
+function helper() {}
+export { helper };
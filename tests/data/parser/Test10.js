@@ -0,0 +1,9 @@
+for (const x of items) {
+  use(x);
+}
+async function* stream() {
+  yield* other();
+  for await (y of source) yield y;
+}
+for (var z of list) break;
+for await (const w of list) {}
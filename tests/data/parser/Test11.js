@@ -0,0 +1,11 @@
+import "./setup.js";
+import a, { b, default as c } from "./a.js";
+import * as ns from "./ns.js";
+export const x = await load(a);
+export async function f() { return import("./lazy.js"); }
+export { b as y, c };
+export { default as z } from "./z.js";
+export * as space from "./space.js";
+export default function () { return import.meta; }
+export { x };
+delete x;
@@ -0,0 +1,5 @@
+var lazy = import("./lazy.js");
+import a from "./a.js";
+export var b = 1;
+var meta = import.meta;
+await lazy;
@@ -0,0 +1,12 @@
+var legacy = 010 + 019;
+with (point) {
+  x = y;
+}
+function sloppy(a, a) {}
+function strict(b, b) {
+  "use strict";
+  return 017;
+}
+var arrow = (c, c) => c;
+"use strict";
+function later() { eval = 1; }
@@ -0,0 +1,16 @@
+"use strict";
+var eval = 1;
+arguments = 2;
+try {} catch (arguments) {}
+with (point) {}
+delete legacy;
+var n = 08;
+function inner() {
+  "not strict";
+  return 1;
+}
+var escaped = "\101" + "\8";
+function prologue() {
+  "\102";
+  "use strict";
+}
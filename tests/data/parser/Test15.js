@@ -0,0 +1,9 @@
+outer: while (a) {
+  inner: for (const x of xs) {
+    continue outer;
+    break inner;
+  }
+  continue;
+  break
+  outer;
+}
@@ -0,0 +1,6 @@
+var half = total / 2 / count;
+var re = /[/]+\/(?<name>\d)/gi;
+var list = [/a/, /b/y];
+if (/^x/.test(s)) { n = n / 2; }
+var bad = /a/gg;
+var worse = /(a/;
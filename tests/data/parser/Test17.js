@@ -0,0 +1,4 @@
+var a = `x${y}z` + tag`\unicode${1}`;
+var b = new Foo`q`.bar;
+var c = `bad \unicode`;
+var d = `open ${x
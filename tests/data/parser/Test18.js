@@ -0,0 +1,12 @@
+switch (x) {
+  case 1:
+  case 2:
+    a();
+    break;
+  default:
+    b();
+  case 3: {
+    c();
+  }
+}
+switch (y) { default: default: }
@@ -0,0 +1,5 @@
+for (var i = 0, n = (a in b); i < n; i++, n--) f(i);
+for (;;) {}
+for (x = [a in b]; ; ) break;
+for (const k in obj) g(k);
+for (a.b in obj);
@@ -0,0 +1,2 @@
+for (let x = 1 in obj) {}
+for (const c; c; ) {}
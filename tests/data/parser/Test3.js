@@ -0,0 +1,3 @@
+let a, b = 2;
+let x;
+var y, z;
@@ -0,0 +1 @@
+const w;
@@ -0,0 +1,11 @@
+let a = ;
+var b = 1 +;
+if (a { b = 2; }
+while (x) { let = 3; y = 4; }
+const c;
+var d = "unterminated
+var ok = 5;
+function f(a, 1) { return a; }
+let z = @;
+x = 1 +
+for (let i = 0; i < 3; i++) {}
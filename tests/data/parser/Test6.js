@@ -0,0 +1,6 @@
+a[0].b;
+a[0](x);
+m[i][j] = 1;
+obj["key"];
+a.b[c](d).e;
+x.default;
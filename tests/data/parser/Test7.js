@@ -0,0 +1,8 @@
+var p = new a.b.C(1, 2);
+var q = new Point;
+new Factory().create();
+function F() {
+  this.target = new.target;
+  return;
+}
+var o = { a: 1, "b": 2, [c]: 3, d };
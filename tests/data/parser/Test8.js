@@ -0,0 +1,10 @@
+function* gen(a) {
+  var x = yield a + 1;
+  yield;
+  yield* other();
+}
+var f = function* () { yield 1; };
+var o = { *items() {}, m(a) { return a; } };
+function plain() {
+  yield 1;
+}
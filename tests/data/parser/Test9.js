@@ -0,0 +1,14 @@
+async function load(url) {
+  try {
+    return await fetch(url);
+  } catch (e) {
+    throw e;
+  } finally {
+    done();
+  }
+}
+var f = async x => await x;
+var g = (a, b) => { return a + b; };
+var o = { async m() {}, async: 1 };
+try { x(); } catch { y(); }
+function bad() { await 1; }
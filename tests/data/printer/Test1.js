@@ -0,0 +1,37 @@
+// Comments and constructs the other test programs lack
+let a = 1, b; // trailing
+
+/* block
+   comment */
+const f = function () {}, g = async (x) => ({ x }), h = (x) => ({}).x;
+
+function* gen(a, b) {
+  // inside
+  yield;
+  yield* a;
+  return (a, b);
+
+  // before the end
+}
+
+x = (a || b) ?? c;
+x = a ?? (b && c);
+x = (-a) ** b ** c;
+x = (a ** b) ** c;
+x = - -a + +(+b) - -1 - (-1);
+x = (1).toString() + 0x10.toString() + 1.5e3.toFixed() + 2E-3;
+new (f().g)(1);
+new a.b.C;
+(function () {})();
+({}).toString();
+x = (a ? b : c) ? d : e;
+x = (a, b) ? c : d;
+x = `a${b + `c${d}`}e`;
+if (a) { b(); } else if (c) { d(); } else { e(); }
+out: for (const x of xs) { if (x) { continue out; } }
+switch (a) { case 1: b(); /* in case */ case 2: default: }
+try { a(); } catch { } finally { }
+with (o) p();
+x = { "a b": 1, 2: 3, [c]: 4, async *m(a) { await a; }, get: 5 };
+z = a++ + ++b, -(-a);
+/* last */
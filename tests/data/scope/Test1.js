@@ -0,0 +1,9 @@
+var a = 1;
+function outer(p) {
+  let b = p;
+  return function inner() {
+    with (p) { b = a + c; }
+    return () => b + inner();
+  };
+}
+try { outer(); } catch (e) { let f = e; }
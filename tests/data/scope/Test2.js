@@ -0,0 +1,20 @@
+import { m } from "m";
+export { later };
+hoisted();
+function hoisted() {
+  if (m) {
+    var x = 1;
+    function nested() { return x; }
+  }
+  return nested;
+}
+var later = value;
+let value = 2;
+for (const item of [value]) {
+  switch (item) {
+    case 1:
+      let y = item;
+  }
+}
+const f = function f(f) { return f; };
+const g = function g() { return g; };
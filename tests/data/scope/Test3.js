@@ -0,0 +1,18 @@
+var twice;
+function twice() {}
+function outer(a) {
+  function a() {}
+  if (a) {
+    function inner() {}
+  }
+  let shadowed;
+  {
+    function shadowed() {}
+  }
+  try {
+  } catch (e) {
+    {
+      function e() {}
+    }
+  }
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"fmt"
+	"gojo/lexer"
+	. "gojo/parser"
+	"os"
+	"testing"
+)
+
+type EarlyErrorsTestCase struct {
+	Name   string
+	Errors []string // Expected early errors, in source order
+	Module bool     // Whether the input is parsed as an ES module rather than a script
+}
+
+func TestEarlyErrors(t *testing.T) {
+	for _, test := range earlyErrorsTestCases {
+		t.Run(
+			test.Name,
+			func(t *testing.T) {
+				CompareEarlyErrors(t, test)
+			},
+		)
+	}
+}
+
+func CompareEarlyErrors(t *testing.T, test EarlyErrorsTestCase) {
+	const testDataDir = "data/early"
+	filePath := fmt.Sprintf("%s/%s.js", testDataDir, test.Name)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Could not read file: %q", filePath)
+	}
+
+	lex := lexer.New(string(data))
+	parser := New(lex)
+	var program *Program
+	var errors []*ParseError
+	if test.Module {
+		program, errors = parser.ParseModule()
+	} else {
+		program, errors = parser.ParseProgram()
+	}
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+
+	errors = CheckEarlyErrors(program)
+	if len(errors) != len(test.Errors) {
+		t.Fatalf("\nExpected %d errors, got %d: %v\n", len(test.Errors), len(errors), errors)
+	}
+	for i, err := range errors {
+		if err.Error() != test.Errors[i] {
+			t.Errorf("\nExpected: %v\nReceived: %v\n", test.Errors[i], err.Error())
+		}
+	}
+}
+
+var earlyErrorsTestCases = []EarlyErrorsTestCase{
+	{
+		Name: "Test1",
+		Errors: []string{
+			"SyntaxError (Line: 2, Column: 5): identifier 'a' has already been declared",
+			"SyntaxError (Line: 4, Column: 10): identifier 'b' has already been declared",
+			"SyntaxError (Line: 7, Column: 7): identifier 'c' has already been declared",
+			"SyntaxError (Line: 10, Column: 7): identifier 'p' has already been declared",
+			"SyntaxError (Line: 14, Column: 7): identifier 'inner' has already been declared",
+			"SyntaxError (Line: 18, Column: 7): identifier 'e' has already been declared",
+			"SyntaxError (Line: 21, Column: 7): identifier 'd' has already been declared",
+		},
+	},
+	{
+		Name: "Test2",
+		Errors: []string{
+			"SyntaxError (Line: 2, Column: 3): label 'outer' has already been declared",
+			"SyntaxError (Line: 7, Column: 3): illegal continue statement: 'block' does not denote an iteration statement",
+			"SyntaxError (Line: 10, Column: 9): undefined label 'missing'",
+			"SyntaxError (Line: 12, Column: 1): illegal break statement",
+			"SyntaxError (Line: 13, Column: 1): illegal continue statement: no surrounding iteration statement",
+			"SyntaxError (Line: 14, Column: 1): illegal return statement",
+			"SyntaxError (Line: 18, Column: 13): undefined label 'loop'",
+			"SyntaxError (Line: 23, Column: 1): new.target expression is not allowed here",
+			"SyntaxError (Line: 24, Column: 30): duplicate __proto__ fields are not allowed in object literals",
+			"SyntaxError (Line: 26, Column: 10): lexical declaration cannot appear in a single-statement context",
+			"SyntaxError (Line: 27, Column: 8): label 'empty' has already been declared",
+		},
+	},
+	{
+		// Redeclarations and labels the specification allows
+		Name: "Test3",
+	},
+	{
+		Name:   "Test4",
+		Module: true,
+		Errors: []string{
+			"SyntaxError (Line: 3, Column: 5): identifier 'a' has already been declared",
+			"SyntaxError (Line: 4, Column: 7): identifier 'd' has already been declared",
+			"SyntaxError (Line: 5, Column: 10): identifier 'b' has already been declared",
+			"SyntaxError (Line: 9, Column: 12): identifier 'g' has already been declared",
+			"SyntaxError (Line: 11, Column: 13): export 'f' is not defined",
+		},
+	},
+	{
+		Name: "Test5",
+		Errors: []string{
+			"SyntaxError (Line: 4, Column: 12): identifier 'g' has already been declared",
+			"SyntaxError (Line: 6, Column: 8): function declarations are not allowed in a single-statement context",
+			"SyntaxError (Line: 11, Column: 9): identifier 'shared' has already been declared",
+		},
+	},
+}
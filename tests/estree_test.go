@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"fmt"
+	"gojo/estree"
+	"gojo/lexer"
+	. "gojo/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type ESTreeTestCase struct {
+	Name     string
+	Expected string // For JSON inputs, the expected String() of the imported program
+}
+
+// TestESTree checks the export of .js inputs against their .json fixture, and the import of .json inputs.
+func TestESTree(t *testing.T) {
+	for _, test := range estreeTestCases {
+		t.Run(
+			test.Name,
+			func(t *testing.T) {
+				CompareESTree(t, test)
+			},
+		)
+	}
+}
+
+func CompareESTree(t *testing.T, test ESTreeTestCase) {
+	const testDataDir = "data/estree"
+	filePath := fmt.Sprintf("%s/%s", testDataDir, test.Name)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Could not read file: %q", filePath)
+	}
+
+	if strings.HasSuffix(test.Name, ".json") {
+		program, err := estree.Unmarshal(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if program.String() != test.Expected {
+			t.Fatalf("\nExpected: %v\nReceived: %v\n", test.Expected, program.String())
+		}
+		return
+	}
+
+	program, errors := New(lexer.New(string(data))).ParseProgram()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+	received, err := estree.MarshalIndent(program, "  ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fixturePath := strings.TrimSuffix(filePath, ".js") + ".json"
+	expected, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatalf("Could not read file: %q", fixturePath)
+	}
+	if string(received) != strings.TrimSpace(string(expected)) {
+		t.Fatalf("\nExpected: %s\nReceived: %s\n", expected, received)
+	}
+}
+
+var estreeTestCases = []ESTreeTestCase{
+	{
+		Name: "Test1.js",
+	},
+	{
+		// Single statement bodies are wrapped in blocks
+		Name:     "Test2.json",
+		Expected: `Program(ExpressionStatement(StringLiteral("use strict"))EmptyStatement()VariableDeclaration(let Identifier(n) = IntegerLiteral(3))IfStatement(BinaryExpression(Identifier(n) && BinaryExpression(Identifier(n) > IntegerLiteral(2))) {ExpressionStatement(CallExpression(MemberExpression(Identifier(console).Identifier(log))(args=StringLiteral("big"))))} else {EmptyStatement()}))`,
+	},
+}
+
+// TestESTreeRoundTrip checks that exporting then importing the test programs gives back the same AST.
+func TestESTreeRoundTrip(t *testing.T) {
+	files, _ := filepath.Glob("data/*/*.js")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Could not read file: %q", file)
+		}
+		program, errors := New(lexer.New(string(data))).ParseProgram()
+		if len(errors) != 0 {
+			program, errors = New(lexer.New(string(data))).ParseModule()
+		}
+		if len(errors) != 0 {
+			continue
+		}
+
+		t.Run(file, func(t *testing.T) {
+			exported, err := estree.Marshal(program)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			imported, err := estree.Unmarshal(exported)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if imported.String() != program.String() || imported.Strict != program.Strict {
+				t.Fatalf("\nExpected: %v\nReceived: %v\n", program.String(), imported.String())
+			}
+			reexported, _ := estree.Marshal(imported)
+			for idx := range exported {
+				if idx >= len(reexported) || reexported[idx] != exported[idx] {
+					t.Fatalf("\nExpected: %s\nReceived: %s\n", exported[idx:min(idx+200, len(exported))],
+						reexported[idx:min(idx+200, len(reexported))])
+				}
+			}
+		})
+	}
+}
+
+type ESTreeErrorTestCase struct {
+	Input string
+	Error string
+}
+
+func TestESTreeErrors(t *testing.T) {
+	for _, test := range estreeErrorTestCases {
+		t.Run(test.Error, func(t *testing.T) {
+			_, err := estree.Unmarshal([]byte(test.Input))
+			if err == nil || err.Error() != test.Error {
+				t.Fatalf("\nExpected: %v\nReceived: %v\n", test.Error, err)
+			}
+		})
+	}
+}
+
+var estreeErrorTestCases = []ESTreeErrorTestCase{
+	{`[]`, "estree: expected a node, got []"},
+	{`{"type": "Identifier", "name": "a"}`, "estree: expected Program, got Identifier"},
+	{`{"type": "Program", "body": [{"type": "ClassDeclaration", "loc": {"start": {"line": 2, "column": 0}}}]}`,
+		"estree (Line: 2): unsupported statement ClassDeclaration"},
+	{`{"type": "Program", "body": [{"type": "ExpressionStatement", "expression": {"type": "Literal", "value": 1e400}}]}`,
+		"estree: unsupported number 1e400"},
+	{`{"type": "Program", "body": [{"type": "ReturnStatement", "argument": {"type": "UnaryExpression", "operator": "++", "argument": {"type": "Literal", "value": 0}}}]}`,
+		"estree: unsupported unary operator ++"},
+	{`{"type": "Program", "body": [{"type": "VariableDeclaration", "kind": "var", "declarations": [{"type": "VariableDeclarator", "id": {"type": "ObjectPattern", "properties": []}}]}]}`,
+		"estree: expected Identifier, got ObjectPattern"},
+}
@@ -5,13 +5,17 @@ import (
 	. "gojo/interpreter"
 	"gojo/lexer"
 	"gojo/parser"
+	"math"
 	"os"
+	"runtime"
 	"testing"
+	"time"
 )
 
 type InterpreterTestCase struct {
 	Name     string
-	Expected map[string]interface{}
+	Expected map[string]Value
+	Module   bool // Whether the input is run as an ES module rather than a script
 }
 
 func TestInterpreter(t *testing.T) {
@@ -35,7 +39,19 @@ func CompareInterpreterOutput(t *testing.T, test InterpreterTestCase) {
 
 	l := lexer.New(string(data))
 	p := parser.New(l)
-	program := p.ParseProgram()
+	var program *parser.Program
+	var errors []*parser.ParseError
+	if test.Module {
+		program, errors = p.ParseModule()
+	} else {
+		program, errors = p.ParseProgram()
+	}
+	if len(errors) == 0 {
+		errors = parser.CheckEarlyErrors(program)
+	}
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
 	interpreter := New()
 	interpreter.Interpret(program)
 
@@ -48,21 +64,324 @@ func CompareInterpreterOutput(t *testing.T, test InterpreterTestCase) {
 	}
 }
 
+// TestAbandonedCoroutines checks that the goroutines of generators and async functions suspended forever
+// are released once nothing can resume them, without running their finally blocks.
+func TestAbandonedCoroutines(t *testing.T) {
+	data, err := os.ReadFile("data/interpreter/Test20.js")
+	if err != nil {
+		t.Fatalf("Could not read file: %q", "data/interpreter/Test20.js")
+	}
+	program, errors := parser.New(lexer.New(string(data))).ParseProgram()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+	empty, _ := parser.New(lexer.New("")).ParseProgram()
+
+	before := runtime.NumGoroutine()
+	interpreter := New()
+	interpreter.Interpret(program)
+	// Finalizers queue the coroutines after a collection, the next program run releases them
+	for attempt := 0; attempt < 100 && runtime.NumGoroutine() > before+10; attempt++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+		interpreter.Interpret(empty)
+	}
+	if goroutines := runtime.NumGoroutine(); goroutines > before+10 {
+		t.Errorf("Expected the coroutines to be released, %d goroutines are left", goroutines-before)
+	}
+	if cleaned := interpreter.Env["cleaned"]; cleaned != Number(0) {
+		t.Errorf("Expected no finally block to run, cleaned is %v", cleaned)
+	}
+}
+
 var interpreterTestCases = []InterpreterTestCase{
 	{
 		Name: "Test1",
-		Expected: map[string]interface{}{
-			"x": int64(5),
-			"y": int64(10),
-			"z": int64(15),
+		Expected: map[string]Value{
+			"x": Number(5),
+			"y": Number(10),
+			"z": Number(15),
 		},
 	},
 	{
 		Name: "Test2",
-		Expected: map[string]interface{}{
-			"a": true,
-			"b": false,
-			"c": false,
+		Expected: map[string]Value{
+			"a": Boolean(true),
+			"b": Boolean(false),
+			"c": Boolean(false),
+		},
+	},
+	{
+		Name: "Test3",
+		Expected: map[string]Value{
+			"a": Undefined,
+			"b": Number(2),
+			"x": Undefined,
+			"y": Number(3),
+			"z": Undefined,
+		},
+	},
+	{
+		Name: "Test4",
+		Expected: map[string]Value{
+			"a": Number(512),
+			"b": String("big"),
+			"c": Number(26),
+			"d": String("default"),
+			"e": String("string"),
+			"f": Number(-25),
+			"g": Boolean(true),
+		},
+	},
+	{
+		Name: "Test5",
+		Expected: map[string]Value{
+			"x":       Number(3),
+			"y":       Number(10),
+			"z":       Number(8),
+			"w":       Number(9),
+			"len":     Number(5),
+			"c":       String("b"),
+			"missing": String("TypeError: Cannot access property '0' of undefined"),
+		},
+	},
+	{
+		Name: "Test6",
+		Expected: map[string]Value{
+			"total":       Number(7),
+			"isPoint":     Boolean(true),
+			"wrapped":     String("yes"),
+			"ignored":     Undefined,
+			"count":       Number(1),
+			"matches":     Boolean(true),
+			"greeting":    String("hi"),
+			"arrowError":  String("TypeError: () => 1 is not a constructor"),
+			"methodError": String("TypeError: child.toString is not a constructor"),
+		},
+	},
+	{
+		Name: "Test7",
+		Expected: map[string]Value{
+			"first":       Number(0),
+			"skipped":     Number(4),
+			"result":      String("done"),
+			"done":        Boolean(true),
+			"d1":          Number(1),
+			"d2":          String("inner"),
+			"d3":          Number(2),
+			"d4":          Number(3),
+			"fromMethod":  Number(42),
+			"returned":    Number(7),
+			"afterReturn": Boolean(true),
+		},
+	},
+	{
+		Name: "Test8",
+		Expected: map[string]Value{
+			"sum":       Number(3),
+			"recovered": String("caught bad"),
+			"rejected":  String("no"),
+			"order":     String("ab"),
+			"counter":   Number(12),
+			"arrowThis": Number(5),
+			"syncCatch": String("sync"),
+		},
+	},
+	{
+		Name: "Test9",
+		Expected: map[string]Value{
+			"total":     Number(36),
+			"early":     Number(5),
+			"closed":    Boolean(true),
+			"queued":    Number(1),
+			"last":      String("liftoff"),
+			"exhausted": Boolean(true),
+			"custom":    Number(3),
+			"letters":   String("ab"),
+		},
+	},
+	{
+		Name:   "Test10",
+		Module: true,
+		Expected: map[string]Value{
+			"base":     Number(20),
+			"total":    Number(42),
+			"named":    String("helper"),
+			"metaType": String("object"),
+			"failed":   String("Error: Cannot load module './missing.js': loading modules is not supported"),
+		},
+	},
+	{
+		Name: "Test11",
+		Expected: map[string]Value{
+			"sloppyGlobal":    Boolean(true),
+			"strictUndefined": Boolean(true),
+			"scriptThis":      Boolean(true),
+			"leaked":          Number(5),
+			"viaGlobal":       Number(7),
+			"strictError":     String("ReferenceError: undeclared is not defined"),
+			"sum":             Number(12),
+			"legacyOctal":     Number(15),
+		},
+	},
+	{
+		Name: "Test12",
+		Expected: map[string]Value{
+			"log":     String("aaabccs"),
+			"cleaned": Number(2),
+		},
+	},
+	{
+		Name: "Test13",
+		Expected: map[string]Value{
+			"year":     String("2024"),
+			"day":      String("09"),
+			"index":    Number(4),
+			"swapped":  String("09/05/2024"),
+			"upper":    String("A-B-C"),
+			"parts":    String("a|1|b|2|c|"),
+			"found":    String("1a2b"),
+			"price":    String("42"),
+			"failed":   String("SyntaxError: Invalid regular expression: /(/: Unterminated group"),
+			"source":   String("a\\/b"),
+			"third":    Boolean(false),
+			"position": Number(0),
+		},
+	},
+	{
+		Name: "Test14",
+		Expected: map[string]Value{
+			"query":     String("select * from users where id = $1 and name = $2"),
+			"params":    String("7,ann"),
+			"greeting":  String("hello ann, you are 7"),
+			"cached":    Boolean(true),
+			"distinct":  Boolean(false),
+			"frozen":    String("a"),
+			"raw":       String("C:\\new\\x1"),
+			"invalid":   Boolean(true),
+			"method":    String("db:7"),
+			"multiline": String("one\ntwo"),
+		},
+	},
+	{
+		Name: "Test15",
+		Expected: map[string]Value{
+			"one":       String("12"),
+			"two":       String("2"),
+			"three":     String("s"),
+			"other":     String("d12"),
+			"matched":   String("bc"),
+			"evaluated": Number(2),
+			"loops":     Number(3),
+		},
+	},
+	{
+		// in, instanceof, void, and for loops with their let bindings copied for each iteration
+		Name: "Test16",
+		Expected: map[string]Value{
+			"hasX":       Boolean(true),
+			"hasScale":   Boolean(true),
+			"hasY":       Boolean(false),
+			"inArray":    Boolean(true),
+			"isPoint":    Boolean(true),
+			"isNotPoint": Boolean(false),
+			"primitive":  Boolean(false),
+			"even":       Boolean(true),
+			"odd":        Boolean(false),
+			"voided":     Undefined,
+			"pairs":      String("03;12;"),
+			"captures":   String("012"),
+			"keys":       String("xscale"),
+			"skipped":    String("ac"),
+			"found":      Number(2),
+			"thrown":     String("TypeError: Cannot use 'in' operator to search for 'x' in 1"),
+		},
+	},
+	{
+		// Hoisting, and the temporal dead zone of let and const
+		Name: "Test17",
+		Expected: map[string]Value{
+			"sum":            Number(3),
+			"before":         String("undefined"),
+			"later":          Number(1),
+			"early":          String("ReferenceError: Cannot access 'limit' before initialization"),
+			"assigned":       String("Cannot access 'count' before initialization"),
+			"errorParts":     String("TypeError|bad input|true|false|RangeError: called|Error"),
+			"kept":           Number(5),
+			"nested":         String("inner"),
+			"blockFunction":  Number(1),
+			"leaked":         String("undefined"),
+			"annex":          String("sloppy"),
+			"caught":         Number(0),
+			"named":          Number(120),
+			"shadowKind":     String("let"),
+			"undeclared":     String("missing is not defined"),
+			"constant":       String("Assignment to constant variable."),
+			"sloppyProperty": String("undefined"),
+			"strictProperty": String("TypeError: Cannot create property 'size' on string 'abc'"),
+		},
+	},
+	{
+		// The kinds of values, BigInts, and arrays as objects
+		Name: "Test18",
+		Expected: map[string]Value{
+			"types":       String("undefined,object,boolean,number,string,symbol,bigint,object,object,function"),
+			"bigText":     String("1234567890123456789012345678901"),
+			"bigEqual":    Boolean(true),
+			"bigFalsy":    Boolean(true),
+			"bigLess":     Boolean(true),
+			"bigLiteral":  String("100000000000000000255,bigint,two"),
+			"bigError":    String("SyntaxError: Cannot convert 1.5 to a BigInt"),
+			"sameNumber":  Boolean(true),
+			"sameArray":   Boolean(false),
+			"sameBuiltin": String("false,true,true"),
+			"grown":       Number(4),
+			"hole":        Undefined,
+			"truncated":   String("1;1"),
+			"shared":      String("first"),
+		},
+	},
+	{
+		// IEEE-754 numbers, and bitwise operators on 32-bit integers
+		Name: "Test19",
+		Expected: map[string]Value{
+			"half":           Number(2.5),
+			"infinite":       Number(math.Inf(1)),
+			"negativeZero":   Number(math.Inf(-1)),
+			"nanUnequal":     Boolean(false),
+			"zeros":          Boolean(true),
+			"unsigned":       Number(4294967295),
+			"power":          Number(512),
+			"reciprocal":     Number(0.5),
+			"oneToNaN":       Boolean(true),
+			"remainder":      Number(-1),
+			"wrapped":        Number(-2147483648),
+			"shifted":        Number(2),
+			"signed":         Number(-4),
+			"inverted":       Number(-6),
+			"sum":            Number(0.30000000000000004),
+			"large":          String("1e+21"),
+			"small":          String("1e-7"),
+			"fraction":       Number(17.5),
+			"coerced":        Number(12),
+			"concatenated":   String("12"),
+			"counter":        Number(6),
+			"ordered":        Boolean(false),
+			"globalNumbers":  Boolean(true),
+			"readOnly":       String("NaN,Infinity"),
+			"strictReadOnly": String("TypeError: Cannot assign to read only property 'Infinity' of object"),
+		},
+	},
+	{
+		Name: "Test21",
+		Expected: map[string]Value{
+			"nullish":       Boolean(true),
+			"numeric":       Boolean(true),
+			"notANumber":    Boolean(true),
+			"bigInts":       Boolean(true),
+			"objects":       Boolean(true),
+			"symbols":       Boolean(true),
+			"unconvertible": String("TypeError: Cannot convert object to primitive value"),
 		},
 	},
 }
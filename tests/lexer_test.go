@@ -34,7 +34,7 @@ func CompareLexerOutput(t *testing.T, test LexerTestCase) {
 	lexer := New(string(data))
 	for i, expectedToken := range test.Expected {
 		var token GojoToken = lexer.NextToken()
-		if token.Type != expectedToken.Type || token.Text != expectedToken.Text {
+		if token.Type != expectedToken.Type || token.Text != expectedToken.Text || token.Raw != expectedToken.Raw {
 			t.Errorf("Token %2d: \nExpected: %v\nReceived: %v\n", i, expectedToken, token)
 		} else {
 			t.Logf("Token %2d: %v", i, token)
@@ -77,8 +77,18 @@ func NewID(id string) GojoToken {
 	return NewToken("identifier", id)
 }
 
-func NewString(str string) GojoToken {
-	return NewToken("string", str)
+// NewString creates a string token with its value and its source text, quotes included.
+func NewString(str string, raw string) GojoToken {
+	token := NewToken("string", str)
+	token.Raw = raw
+	return token
+}
+
+// NewTemplate creates a template chunk token with its cooked and raw text.
+func NewTemplate(tokenTypeStr string, cooked string, raw string) GojoToken {
+	token := NewToken(tokenTypeStr, cooked)
+	token.Raw = raw
+	return token
 }
 
 var lexerTestCases = []LexerTestCase{
@@ -116,8 +126,9 @@ var lexerTestCases = []LexerTestCase{
 	{
 		Name: "Strings",
 		Expected: []GojoToken{
-			NewToken("var"), NewID("str"), NewToken("="), NewString("Hello, world!\n"), NewToken(";"),
-			NewToken("var"), NewID("escapedStr"), NewToken("="), NewString("This is a \"quoted\" string."), NewToken(";"),
+			NewToken("var"), NewID("str"), NewToken("="), NewString("Hello, world!\n", `"Hello, world!\n"`), NewToken(";"),
+			NewToken("var"), NewID("escapedStr"), NewToken("="), NewString("This is a \"quoted\" string.", `"This is a \"quoted\" string."`), NewToken(";"),
+			NewToken("var"), NewID("cooked"), NewToken("="), NewString("AB😀😀 é", "\"\\x41\\u0042\\u{1F600}\\uD83D\\uDE00 é\\\n\""), NewToken(";"),
 		},
 	},
 	{
@@ -131,7 +142,7 @@ var lexerTestCases = []LexerTestCase{
 		Name: "Objects",
 		Expected: []GojoToken{
 			NewToken("var"), NewID("obj"), NewToken("="), NewToken("{"),
-			NewID("key1"), NewToken(":"), NewString("value1"), NewToken(","),
+			NewID("key1"), NewToken(":"), NewString("value1", `"value1"`), NewToken(","),
 			NewID("key2"), NewToken(":"), NewNumber("42"), NewToken(","),
 			NewID("key3"), NewToken(":"), NewToken("true"),
 			NewToken("}"), NewToken(";"),
@@ -163,6 +174,15 @@ var lexerTestCases = []LexerTestCase{
 			NewToken("var"), NewID("regex"), NewToken("="), NewToken("regexp", "/ab+c/"), NewToken(";"),
 		},
 	},
+	{
+		// Braces inside a substitution do not end it
+		Name: "Templates",
+		Expected: []GojoToken{
+			NewToken("var"), NewID("t"), NewToken("="), NewID("tag"), NewTemplate("templateHead", "a\n", `a\n`),
+			NewID("x"), NewTemplate("templateMiddle", "b", "b"), NewToken("{"), NewID("y"), NewToken(":"),
+			NewNumber("1"), NewToken("}"), NewTemplate("templateTail", "A", `\u{41}`), NewToken(";"),
+		},
+	},
 	{
 		Name: "MultiCharacterOperators",
 		Expected: []GojoToken{
@@ -185,4 +205,12 @@ var lexerTestCases = []LexerTestCase{
 			NewToken("var"), NewID("p"), NewToken("="), NewID("a"), NewToken("?."), NewID("b"), NewToken(";"),
 		},
 	},
+	{
+		// Only integers take the n suffix of BigInts
+		Name: "BigInts",
+		Expected: []GojoToken{
+			NewToken("var"), NewID("a"), NewToken("="), NewToken("bigint", "10n"), NewToken("+"), NewToken("bigint", "0xFFn"), NewToken(";"),
+			NewToken("var"), NewID("b"), NewToken("="), NewNumber("1.5"), NewID("n"), NewToken(";"),
+		},
+	},
 }
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"fmt"
+	"gojo/lexer"
+	"gojo/lint"
+	. "gojo/parser"
+	"os"
+	"testing"
+)
+
+type LintTestCase struct {
+	Name        string
+	Diagnostics []string // Expected diagnostics, in source order
+	Module      bool     // Whether the input is parsed as an ES module rather than a script
+}
+
+func TestLint(t *testing.T) {
+	for _, test := range lintTestCases {
+		t.Run(
+			test.Name,
+			func(t *testing.T) {
+				CompareLint(t, test)
+			},
+		)
+	}
+}
+
+func CompareLint(t *testing.T, test LintTestCase) {
+	const testDataDir = "data/lint"
+	filePath := fmt.Sprintf("%s/%s.js", testDataDir, test.Name)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Could not read file: %q", filePath)
+	}
+
+	parser := New(lexer.New(string(data)))
+	var program *Program
+	var errors []*ParseError
+	if test.Module {
+		program, errors = parser.ParseModule()
+	} else {
+		program, errors = parser.ParseProgram()
+	}
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+
+	diagnostics := lint.Run(program, lint.DefaultRules)
+	if len(diagnostics) != len(test.Diagnostics) {
+		t.Fatalf("\nExpected %d diagnostics, got %d: %v\n", len(test.Diagnostics), len(diagnostics), diagnostics)
+	}
+	for i, diagnostic := range diagnostics {
+		if diagnostic.String() != test.Diagnostics[i] {
+			t.Errorf("\nExpected: %v\nReceived: %v\n", test.Diagnostics[i], diagnostic.String())
+		}
+	}
+}
+
+var lintTestCases = []LintTestCase{
+	{
+		Name: "Test1",
+		Diagnostics: []string{
+			"no-const-assign (Line: 2, Column: 1): cannot assign to 'limit' because it is a constant",
+			"no-unused-vars (Line: 5, Column: 5): 'count' is assigned a value but never used",
+			"no-unused-vars (Line: 7, Column: 5): 'declared' is declared but never used",
+			"no-unused-vars (Line: 9, Column: 20): 'c' is declared but never used",
+			"no-unused-vars (Line: 14, Column: 10): 'unused' is declared but never used",
+			"no-unused-vars (Line: 20, Column: 12): 'item' is declared but never used",
+		},
+	},
+	{
+		Name: "Test2",
+		Diagnostics: []string{
+			"eqeqeq (Line: 2, Column: 7): expected '===' and instead saw '=='",
+			"eqeqeq (Line: 3, Column: 12): expected '!==' and instead saw '!='",
+			"no-unreachable (Line: 4, Column: 5): unreachable code",
+			"no-unused-vars (Line: 11, Column: 7): 'later' is declared but never used",
+			"no-unused-vars (Line: 12, Column: 12): 'hoisted' is declared but never used",
+			"no-unreachable (Line: 13, Column: 3): unreachable code",
+			"no-unreachable (Line: 20, Column: 7): unreachable code",
+			"no-duplicate-case (Line: 24, Column: 5): duplicate case label",
+			"no-duplicate-case (Line: 25, Column: 5): duplicate case label",
+			"no-duplicate-case (Line: 26, Column: 5): duplicate case label",
+			"no-duplicate-case (Line: 27, Column: 5): duplicate case label",
+		},
+	},
+	{
+		// Shadowing, and disable comments
+		Name: "Test3",
+		Diagnostics: []string{
+			"no-shadow (Line: 2, Column: 16): 'value' is already declared in the upper scope on line 1",
+			"no-shadow (Line: 5, Column: 9): 'inner' is already declared in the upper scope on line 3",
+			"no-shadow (Line: 8, Column: 14): 'value' is already declared in the upper scope on line 2",
+			"no-shadow (Line: 11, Column: 17): 'value' is already declared in the upper scope on line 2",
+			"eqeqeq (Line: 17, Column: 9): expected '===' and instead saw '=='",
+		},
+	},
+	{
+		// Exported declarations are used by the importing modules
+		Name: "Test4",
+		Diagnostics: []string{
+			"no-unused-vars (Line: 1, Column: 16): 'unused' is declared but never used",
+			"no-unused-vars (Line: 2, Column: 13): 'namespace' is declared but never used",
+			"no-unused-vars (Line: 9, Column: 7): 'local' is assigned a value but never used",
+		},
+		Module: true,
+	},
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"gojo/lexer"
+	. "gojo/parser"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type LocationsTestCase struct {
+	Input    string
+	Expected []string // Expected source text of each node, in the order of the fields of the nodes
+	Module   bool     // Whether the input is parsed as an ES module rather than a script
+}
+
+// TestLocations checks the source text covered by every node of small programs.
+func TestLocations(t *testing.T) {
+	for _, test := range locationsTestCases {
+		t.Run(
+			test.Input,
+			func(t *testing.T) {
+				program := parseLocated(t, test.Input, test.Module)
+				var received []string
+				walkNodes(program, nil, func(node Node, parent Node) {
+					loc := node.Location()
+					received = append(received, test.Input[loc.Start.Offset:loc.End.Offset])
+				})
+				if strings.Join(received, "|") != strings.Join(test.Expected, "|") {
+					t.Fatalf("\nExpected: %q\nReceived: %q\n", test.Expected, received)
+				}
+			},
+		)
+	}
+}
+
+// TestLocationsCorpus checks that every node of the test programs has a range inside the range of its
+// parent, with lines and columns matching its offsets.
+func TestLocationsCorpus(t *testing.T) {
+	files, _ := filepath.Glob("data/*/*.js")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Could not read file: %q", file)
+		}
+		input := string(data)
+		program, errors := New(lexer.New(input)).ParseProgram()
+		if len(errors) != 0 {
+			program, errors = New(lexer.New(input)).ParseModule()
+		}
+		if len(errors) != 0 {
+			continue
+		}
+
+		t.Run(file, func(t *testing.T) {
+			walkNodes(program, nil, func(node Node, parent Node) {
+				loc := node.Location()
+				if loc.End.Line == 0 || loc.Start.Offset > loc.End.Offset {
+					t.Fatalf("%s has no range: %v", node, loc)
+				}
+				for _, position := range []Position{loc.Start, loc.End} {
+					if expected := positionAt(input, position.Offset); position != expected {
+						t.Fatalf("%s has position %+v, expected %+v", node, position, expected)
+					}
+				}
+				if parent == nil {
+					return
+				}
+				if outer := parent.Location(); loc.Start.Offset < outer.Start.Offset ||
+					loc.End.Offset > outer.End.Offset {
+					t.Fatalf("%s (%v) is outside of %s (%v)", node, loc, parent, outer)
+				}
+			})
+		})
+	}
+}
+
+func parseLocated(t *testing.T, input string, module bool) *Program {
+	parser := New(lexer.New(input))
+	var program *Program
+	var errors []*ParseError
+	if module {
+		program, errors = parser.ParseModule()
+	} else {
+		program, errors = parser.ParseProgram()
+	}
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected parser errors: %v", errors)
+	}
+	return program
+}
+
+// positionAt computes the line and column of an offset.
+func positionAt(input string, offset int) Position {
+	line := strings.Count(input[:offset], "\n") + 1
+	return Position{Offset: offset, Line: line, Column: offset - strings.LastIndex(input[:offset], "\n")}
+}
+
+// walkNodes visits the nodes of a tree in the order of their fields, parents before their children.
+func walkNodes(node Node, parent Node, visit func(node Node, parent Node)) {
+	visit(node, parent)
+	value := reflect.ValueOf(node).Elem()
+	for idx := 0; idx < value.NumField(); idx++ {
+		walkValue(value.Field(idx), node, visit)
+	}
+}
+
+func walkValue(value reflect.Value, parent Node, visit func(node Node, parent Node)) {
+	switch value.Kind() {
+	case reflect.Slice:
+		for idx := 0; idx < value.Len(); idx++ {
+			walkValue(value.Index(idx), parent, visit)
+		}
+	case reflect.Interface, reflect.Pointer:
+		if value.IsNil() {
+			return
+		}
+		if node, ok := value.Interface().(Node); ok {
+			walkNodes(node, parent, visit)
+		}
+	}
+}
+
+var locationsTestCases = []LocationsTestCase{
+	{
+		Input:    "let a = b.c(1) + 2;",
+		Expected: []string{"let a = b.c(1) + 2;", "let a = b.c(1) + 2;", "a = b.c(1) + 2", "a", "b.c(1) + 2", "b.c(1)", "b.c", "b", "c", "1", "2"},
+	},
+	{
+		// Parenthesized expressions keep the range inside the parentheses
+		Input:    "x = (a || b) && c",
+		Expected: []string{"x = (a || b) && c", "x = (a || b) && c", "x = (a || b) && c", "x", "(a || b) && c", "a || b", "a", "b", "c"},
+	},
+	{
+		Input: "function f(a) {\n  return a;\n}",
+		Expected: []string{"function f(a) {\n  return a;\n}", "function f(a) {\n  return a;\n}", "f", "a",
+			"{\n  return a;\n}", "return a;", "a"},
+	},
+	{
+		Input: "switch (a) {\n  case 1:\n    b();\n  default:\n}",
+		Expected: []string{"switch (a) {\n  case 1:\n    b();\n  default:\n}", "switch (a) {\n  case 1:\n    b();\n  default:\n}",
+			"a", "case 1:\n    b();", "1", "b();", "b()", "b", "default:"},
+	},
+	{
+		Input: "o = { a, b: 1, c() {} }",
+		Expected: []string{"o = { a, b: 1, c() {} }", "o = { a, b: 1, c() {} }", "o = { a, b: 1, c() {} }", "o",
+			"{ a, b: 1, c() {} }", "a", "a", "a", "b: 1", "b", "1", "c() {}", "c", "() {}", "{}"},
+	},
+	{
+		Input:    "tag`a${b}c` + new.target",
+		Expected: []string{"tag`a${b}c` + new.target", "tag`a${b}c` + new.target", "tag`a${b}c` + new.target", "tag`a${b}c`", "tag", "`a${b}c`", "`a${", "}c`", "b", "new.target", "new", "target"},
+	},
+	{
+		Input:    "for (const x of xs) try {} catch (e) {}",
+		Expected: []string{"for (const x of xs) try {} catch (e) {}", "for (const x of xs) try {} catch (e) {}", "const x", "x", "x", "xs", "try {} catch (e) {}", "{}", "catch (e) {}", "e", "{}"},
+	},
+	{
+		Input:    "import a, { b as c } from \"m\";\nexport default async function () {}",
+		Expected: []string{"import a, { b as c } from \"m\";\nexport default async function () {}", "import a, { b as c } from \"m\";", "a", "a", "b as c", "b", "c", "\"m\"", "export default async function () {}", "async function () {}", "{}"},
+		Module:   true,
+	},
+}
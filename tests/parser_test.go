@@ -11,6 +11,8 @@ import (
 type ParserTestCase struct {
 	Name     string
 	Expected string
+	Errors   []string // Expected parser errors, in order
+	Module   bool     // Whether the input is parsed as an ES module rather than a script
 }
 
 func TestParser(t *testing.T) {
@@ -34,7 +36,21 @@ func CompareParserOutput(t *testing.T, test ParserTestCase) {
 
 	lex := lexer.New(string(data))
 	parser := New(lex)
-	program := parser.ParseProgram()
+	var program *Program
+	var errors []*ParseError
+	if test.Module {
+		program, errors = parser.ParseModule()
+	} else {
+		program, errors = parser.ParseProgram()
+	}
+	if len(errors) != len(test.Errors) {
+		t.Fatalf("\nExpected %d errors, got %d: %v\n", len(test.Errors), len(errors), errors)
+	}
+	for i, err := range errors {
+		if err.Error() != test.Errors[i] {
+			t.Errorf("\nExpected: %v\nReceived: %v\n", test.Errors[i], err.Error())
+		}
+	}
 	if program.String() != test.Expected {
 		t.Fatalf("\nExpected: %v\nReceived: %v\n", test.Expected, program.String())
 	}
@@ -49,4 +65,248 @@ var parserTestCases = []ParserTestCase{
 		Name:     "Test2",
 		Expected: `Program(VariableDeclaration(var Identifier(a) = BooleanLiteral(true))VariableDeclaration(var Identifier(b) = BooleanLiteral(false))VariableDeclaration(var Identifier(c) = BinaryExpression(Identifier(a) && Identifier(b))))`,
 	},
+	{
+		Name:     "Test3",
+		Expected: `Program(VariableDeclaration(let Identifier(a), Identifier(b) = IntegerLiteral(2))VariableDeclaration(let Identifier(x))VariableDeclaration(var Identifier(y), Identifier(z)))`,
+	},
+	{
+		Name:     "Test4",
+		Expected: `Program()`,
+		Errors: []string{
+			"SyntaxError (Line: 1, Column: 8): missing initializer in const declaration 'w'",
+		},
+	},
+	{
+		// Parsing recovers at statement boundaries and keeps the statements that parsed
+		Name:     "Test5",
+		Expected: `Program(WhileStatement(Identifier(x), {ExpressionStatement(AssignmentExpression(Identifier(y) = IntegerLiteral(4)))})VariableDeclaration(var Identifier(ok) = IntegerLiteral(5))ForStatement(VariableDeclaration(let Identifier(i) = IntegerLiteral(0)); BinaryExpression(Identifier(i) < IntegerLiteral(3)); UpdateExpression(Identifier(i)++) {}))`,
+		Errors: []string{
+			"SyntaxError (Line: 1, Column: 9): unexpected token ';'",
+			"SyntaxError (Line: 2, Column: 12): unexpected token ';'",
+			"SyntaxError (Line: 3, Column: 7): expected ')', got '{' instead",
+			"SyntaxError (Line: 4, Column: 17): expected 'identifier', got '=' instead",
+			"SyntaxError (Line: 5, Column: 8): missing initializer in const declaration 'c'",
+			"SyntaxError (Line: 6, Column: 9): unterminated string literal",
+			"SyntaxError (Line: 8, Column: 15): expected 'identifier', got number '1' instead",
+			"SyntaxError (Line: 9, Column: 9): invalid or unexpected token \"@\"",
+			"SyntaxError (Line: 11, Column: 1): unexpected token 'for'",
+		},
+	},
+	{
+		Name:     "Test6",
+		Expected: `Program(ExpressionStatement(MemberExpression(MemberExpression(Identifier(a)[IntegerLiteral(0)]).Identifier(b)))ExpressionStatement(CallExpression(MemberExpression(Identifier(a)[IntegerLiteral(0)])(args=Identifier(x))))ExpressionStatement(AssignmentExpression(MemberExpression(MemberExpression(Identifier(m)[Identifier(i)])[Identifier(j)]) = IntegerLiteral(1)))ExpressionStatement(MemberExpression(Identifier(obj)[StringLiteral("key")]))ExpressionStatement(MemberExpression(CallExpression(MemberExpression(MemberExpression(Identifier(a).Identifier(b))[Identifier(c)])(args=Identifier(d))).Identifier(e)))ExpressionStatement(MemberExpression(Identifier(x).Identifier(default))))`,
+	},
+	{
+		Name:     "Test7",
+		Expected: `Program(VariableDeclaration(var Identifier(p) = NewExpression(MemberExpression(MemberExpression(Identifier(a).Identifier(b)).Identifier(C))(args=IntegerLiteral(1), IntegerLiteral(2))))VariableDeclaration(var Identifier(q) = NewExpression(Identifier(Point)(args=)))ExpressionStatement(CallExpression(MemberExpression(NewExpression(Identifier(Factory)(args=)).Identifier(create))(args=)))FunctionDeclaration(Identifier(F)() {ExpressionStatement(AssignmentExpression(MemberExpression(ThisExpression().Identifier(target)) = MetaProperty(new.target)))ReturnStatement()})VariableDeclaration(var Identifier(o) = ObjectLiteral(Identifier(a): IntegerLiteral(1), StringLiteral("b"): IntegerLiteral(2), [Identifier(c)]: IntegerLiteral(3), Identifier(d))))`,
+	},
+	{
+		Name:     "Test8",
+		Expected: `Program(FunctionDeclaration(*Identifier(gen)(Identifier(a)) {VariableDeclaration(var Identifier(x) = YieldExpression(yield BinaryExpression(Identifier(a) + IntegerLiteral(1))))ExpressionStatement(YieldExpression(yield))ExpressionStatement(YieldExpression(yield* CallExpression(Identifier(other)(args=))))})VariableDeclaration(var Identifier(f) = FunctionExpression(*() {ExpressionStatement(YieldExpression(yield IntegerLiteral(1)))}))VariableDeclaration(var Identifier(o) = ObjectLiteral(method Identifier(items): FunctionExpression(*() {}), method Identifier(m): FunctionExpression((Identifier(a)) {ReturnStatement(Identifier(a))})))FunctionDeclaration(Identifier(plain)() {}))`,
+		Errors: []string{
+			"SyntaxError (Line: 9, Column: 3): yield is only valid in generator functions",
+		},
+	},
+	{
+		Name:     "Test9",
+		Expected: `Program(FunctionDeclaration(async Identifier(load)(Identifier(url)) {TryStatement({ReturnStatement(AwaitExpression(CallExpression(Identifier(fetch)(args=Identifier(url)))))} catch (Identifier(e)) {ThrowStatement(Identifier(e))} finally {ExpressionStatement(CallExpression(Identifier(done)(args=)))})})VariableDeclaration(var Identifier(f) = ArrowFunctionExpression(async (Identifier(x)) => AwaitExpression(Identifier(x))))VariableDeclaration(var Identifier(g) = ArrowFunctionExpression((Identifier(a), Identifier(b)) => {ReturnStatement(BinaryExpression(Identifier(a) + Identifier(b)))}))VariableDeclaration(var Identifier(o) = ObjectLiteral(method Identifier(m): FunctionExpression(async () {}), Identifier(async): IntegerLiteral(1)))TryStatement({ExpressionStatement(CallExpression(Identifier(x)(args=)))} catch {ExpressionStatement(CallExpression(Identifier(y)(args=)))})FunctionDeclaration(Identifier(bad)() {}))`,
+		Errors: []string{
+			"SyntaxError (Line: 14, Column: 18): await is only valid in async functions",
+		},
+	},
+	{
+		Name:     "Test10",
+		Expected: `Program(ForOfStatement(VariableDeclaration(const Identifier(x)) of Identifier(items) {ExpressionStatement(CallExpression(Identifier(use)(args=Identifier(x))))})FunctionDeclaration(async *Identifier(stream)() {ExpressionStatement(YieldExpression(yield* CallExpression(Identifier(other)(args=))))ForOfStatement(await Identifier(y) of Identifier(source) ExpressionStatement(YieldExpression(yield Identifier(y))))})ForOfStatement(VariableDeclaration(var Identifier(z)) of Identifier(list) BreakStatement())ForOfStatement(await VariableDeclaration(const Identifier(w)) of Identifier(list) {}))`,
+		Errors: []string{
+			"SyntaxError (Line: 9, Column: 5): for await is only valid in async functions",
+		},
+	},
+	{
+		Name:     "Test11",
+		Module:   true,
+		Expected: `Program(ImportDeclaration(StringLiteral("./setup.js"))ImportDeclaration(ImportDefaultSpecifier(Identifier(a)), ImportSpecifier(Identifier(b)), ImportSpecifier(Identifier(default) as Identifier(c)) from StringLiteral("./a.js"))ImportDeclaration(ImportNamespaceSpecifier(Identifier(ns)) from StringLiteral("./ns.js"))ExportNamedDeclaration(VariableDeclaration(const Identifier(x) = AwaitExpression(CallExpression(Identifier(load)(args=Identifier(a))))))ExportNamedDeclaration(FunctionDeclaration(async Identifier(f)() {ReturnStatement(ImportExpression(StringLiteral("./lazy.js")))}))ExportNamedDeclaration({ExportSpecifier(Identifier(b) as Identifier(y)), ExportSpecifier(Identifier(c))})ExportNamedDeclaration({ExportSpecifier(Identifier(default) as Identifier(z))} from StringLiteral("./z.js"))ExportAllDeclaration(* as Identifier(space) from StringLiteral("./space.js"))ExportDefaultDeclaration(FunctionDeclaration(() {ReturnStatement(MetaProperty(import.meta))}))ExportNamedDeclaration({ExportSpecifier(Identifier(x))}))`,
+		Errors: []string{
+			"SyntaxError (Line: 10, Column: 10): duplicate export 'x'",
+			"SyntaxError (Line: 11, Column: 1): delete of an unqualified identifier in strict mode",
+		},
+	},
+	{
+		Name:     "Test12",
+		Expected: `Program(VariableDeclaration(var Identifier(lazy) = ImportExpression(StringLiteral("./lazy.js")))VariableDeclaration(var Identifier(b) = IntegerLiteral(1)))`,
+		Errors: []string{
+			"SyntaxError (Line: 2, Column: 1): import declarations may only appear at top level of a module",
+			"SyntaxError (Line: 3, Column: 1): export declarations may only appear at top level of a module",
+			"SyntaxError (Line: 4, Column: 12): import.meta may only appear in module code",
+			"SyntaxError (Line: 5, Column: 1): await is only valid in async functions",
+		},
+	},
+	{
+		Name:     "Test13",
+		Expected: `Program(VariableDeclaration(var Identifier(legacy) = BinaryExpression(IntegerLiteral(8) + IntegerLiteral(19)))WithStatement(Identifier(point) {ExpressionStatement(AssignmentExpression(Identifier(x) = Identifier(y)))})FunctionDeclaration(Identifier(sloppy)(Identifier(a), Identifier(a)) {})FunctionDeclaration(Identifier(strict)(Identifier(b), Identifier(b)) {ExpressionStatement(StringLiteral("use strict"))ReturnStatement(IntegerLiteral(15))})VariableDeclaration(var Identifier(arrow) = ArrowFunctionExpression((Identifier(c), Identifier(c)) => Identifier(c)))ExpressionStatement(StringLiteral("use strict"))FunctionDeclaration(Identifier(later)() {ExpressionStatement(AssignmentExpression(Identifier(eval) = IntegerLiteral(1)))}))`,
+		Errors: []string{
+			"SyntaxError (Line: 8, Column: 10): octal literals are not allowed in strict mode",
+			"SyntaxError (Line: 6, Column: 20): duplicate parameter name 'b' not allowed in this context",
+			"SyntaxError (Line: 10, Column: 17): duplicate parameter name 'c' not allowed in this context",
+		},
+	},
+	{
+		Name:     "Test14",
+		Expected: `Program(ExpressionStatement(StringLiteral("use strict"))VariableDeclaration(var Identifier(eval) = IntegerLiteral(1))ExpressionStatement(AssignmentExpression(Identifier(arguments) = IntegerLiteral(2)))TryStatement({} catch (Identifier(arguments)) {})WithStatement(Identifier(point) {})VariableDeclaration(var Identifier(n) = IntegerLiteral(8))FunctionDeclaration(Identifier(inner)() {ExpressionStatement(StringLiteral("not strict"))ReturnStatement(IntegerLiteral(1))})VariableDeclaration(var Identifier(escaped) = BinaryExpression(StringLiteral("A") + StringLiteral("8")))FunctionDeclaration(Identifier(prologue)() {ExpressionStatement(StringLiteral("B"))ExpressionStatement(StringLiteral("use strict"))}))`,
+		Errors: []string{
+			"SyntaxError (Line: 2, Column: 5): unexpected eval in strict mode",
+			"SyntaxError (Line: 3, Column: 1): unexpected arguments in strict mode",
+			"SyntaxError (Line: 4, Column: 15): unexpected arguments in strict mode",
+			"SyntaxError (Line: 5, Column: 1): strict mode code may not include a with statement",
+			"SyntaxError (Line: 6, Column: 1): delete of an unqualified identifier in strict mode",
+			"SyntaxError (Line: 7, Column: 9): decimals with leading zeros are not allowed in strict mode",
+			"SyntaxError (Line: 12, Column: 15): octal escape sequences are not allowed in strict mode",
+			"SyntaxError (Line: 12, Column: 24): octal escape sequences are not allowed in strict mode",
+			"SyntaxError (Line: 14, Column: 3): octal escape sequences are not allowed in strict mode",
+		},
+	},
+	{
+		// A label on the next line is not part of a break, which ends at the line break
+		Name:     "Test15",
+		Expected: `Program(LabeledStatement(Identifier(outer): WhileStatement(Identifier(a), {LabeledStatement(Identifier(inner): ForOfStatement(VariableDeclaration(const Identifier(x)) of Identifier(xs) {ContinueStatement(Identifier(outer))BreakStatement(Identifier(inner))}))ContinueStatement()BreakStatement()ExpressionStatement(Identifier(outer))})))`,
+	},
+	{
+		// A slash starts a regular expression where an expression may start, otherwise it divides
+		Name:     "Test16",
+		Expected: `Program(VariableDeclaration(var Identifier(half) = BinaryExpression(BinaryExpression(Identifier(total) / IntegerLiteral(2)) / Identifier(count)))VariableDeclaration(var Identifier(re) = RegExpLiteral(/[/]+\/(?<name>\d)/gi))VariableDeclaration(var Identifier(list) = ArrayLiteral(RegExpLiteral(/a/), RegExpLiteral(/b/y)))IfStatement(CallExpression(MemberExpression(RegExpLiteral(/^x/).Identifier(test))(args=Identifier(s))) {ExpressionStatement(AssignmentExpression(Identifier(n) = BinaryExpression(Identifier(n) / IntegerLiteral(2))))}))`,
+		Errors: []string{
+			"SyntaxError (Line: 5, Column: 11): invalid regular expression flags 'gg'",
+			"SyntaxError (Line: 6, Column: 13): invalid regular expression: /(a/: Unterminated group",
+		},
+	},
+	{
+		// Only tagged templates may have invalid escapes
+		Name:     "Test17",
+		Expected: "Program(VariableDeclaration(var Identifier(a) = BinaryExpression(TemplateLiteral(`x${Identifier(y)}z`) + TaggedTemplateExpression(Identifier(tag) TemplateLiteral(`\\unicode${IntegerLiteral(1)}`))))VariableDeclaration(var Identifier(b) = NewExpression(MemberExpression(TaggedTemplateExpression(Identifier(Foo) TemplateLiteral(`q`)).Identifier(bar))(args=))))",
+		Errors: []string{
+			"SyntaxError (Line: 3, Column: 9): invalid escape sequence in template literal",
+			"SyntaxError (Line: 5, Column: 1): expected '}', got end of input instead",
+		},
+	},
+	{
+		// Case bodies are statement lists, the default clause may come anywhere but only once
+		Name:     "Test18",
+		Expected: `Program(Switch (Identifier(x): CaseClause(IntegerLiteral(1)Body()) CaseClause(IntegerLiteral(2)Body(ExpressionStatement(CallExpression(Identifier(a)(args=)))BreakStatement())) DefaultCaseClause(Body(ExpressionStatement(CallExpression(Identifier(b)(args=))))) CaseClause(IntegerLiteral(3)Body({ExpressionStatement(CallExpression(Identifier(c)(args=)))})) ))`,
+		Errors: []string{
+			"SyntaxError (Line: 12, Column: 23): more than one default clause in switch statement",
+		},
+	},
+	{
+		// in is only an operator between brackets in the head of a for loop, an empty body is an empty statement
+		Name:     "Test19",
+		Expected: `Program(ForStatement(VariableDeclaration(var Identifier(i) = IntegerLiteral(0), Identifier(n) = BinaryExpression(Identifier(a) in Identifier(b))); BinaryExpression(Identifier(i) < Identifier(n)); SequenceExpression(UpdateExpression(Identifier(i)++), UpdateExpression(Identifier(n)--)) ExpressionStatement(CallExpression(Identifier(f)(args=Identifier(i)))))ForStatement(; ;  {})ForStatement(AssignmentExpression(Identifier(x) = ArrayLiteral(BinaryExpression(Identifier(a) in Identifier(b)))); ;  BreakStatement())ForInStatement(VariableDeclaration(const Identifier(k)) in Identifier(obj) ExpressionStatement(CallExpression(Identifier(g)(args=Identifier(k)))))ForInStatement(MemberExpression(Identifier(a).Identifier(b)) in Identifier(obj) EmptyStatement()))`,
+	},
+	{
+		Name:     "Test20",
+		Expected: `Program(ForInStatement(VariableDeclaration(let Identifier(x) = IntegerLiteral(1)) in Identifier(obj) {})ForStatement(VariableDeclaration(const Identifier(c)); Identifier(c);  {}))`,
+		Errors: []string{
+			"SyntaxError (Line: 1, Column: 10): the variable of a for...in loop may not have an initializer",
+			"SyntaxError (Line: 2, Column: 12): missing initializer in const declaration 'c'",
+		},
+	},
+}
+
+type PrecedenceTestCase struct {
+	Input    string
+	Expected string // Expected expression, empty when the input is a syntax error
+}
+
+// TestPrecedence checks every precedence level against its neighbours, plus associativity.
+func TestPrecedence(t *testing.T) {
+	for _, test := range precedenceTestCases {
+		t.Run(
+			test.Input,
+			func(t *testing.T) {
+				CompareParserPrecedence(t, test)
+			},
+		)
+	}
+}
+
+func CompareParserPrecedence(t *testing.T, test PrecedenceTestCase) {
+	lex := lexer.New(test.Input)
+	parser := New(lex)
+	program, errors := parser.ParseProgram()
+
+	if test.Expected == "" {
+		if len(errors) == 0 {
+			t.Fatalf("\nExpected a syntax error\nReceived: %v\n", program.String())
+		}
+		return
+	}
+	if len(errors) != 0 {
+		t.Fatalf("\nUnexpected errors: %v\n", errors)
+	}
+	expected := "Program(ExpressionStatement(" + test.Expected + "))"
+	if program.String() != expected {
+		t.Fatalf("\nExpected: %v\nReceived: %v\n", expected, program.String())
+	}
+}
+
+var precedenceTestCases = []PrecedenceTestCase{
+	// Comma
+	{"a, b = c", `SequenceExpression(Identifier(a), AssignmentExpression(Identifier(b) = Identifier(c)))`},
+	{"a = b, c", `SequenceExpression(AssignmentExpression(Identifier(a) = Identifier(b)), Identifier(c))`},
+	// Assignment (right-associative)
+	{"a = b = c", `AssignmentExpression(Identifier(a) = AssignmentExpression(Identifier(b) = Identifier(c)))`},
+	{"a = b ?? c", `AssignmentExpression(Identifier(a) = BinaryExpression(Identifier(b) ?? Identifier(c)))`},
+	{"a += b * c", `AssignmentExpression(Identifier(a) += BinaryExpression(Identifier(b) * Identifier(c)))`},
+	{"a **= b ?? c", `AssignmentExpression(Identifier(a) **= BinaryExpression(Identifier(b) ?? Identifier(c)))`},
+	{"a + b = c", ""},
+	// Conditional (right-associative)
+	{"a ? b : c ? d : e", `ConditionalExpression(Identifier(a) ? Identifier(b) : ConditionalExpression(Identifier(c) ? Identifier(d) : Identifier(e)))`},
+	{"a || b ? c = 1 : d", `ConditionalExpression(BinaryExpression(Identifier(a) || Identifier(b)) ? AssignmentExpression(Identifier(c) = IntegerLiteral(1)) : Identifier(d))`},
+	// Coalesce
+	{"a ?? b ?? c", `BinaryExpression(BinaryExpression(Identifier(a) ?? Identifier(b)) ?? Identifier(c))`},
+	{"a ?? b | c", `BinaryExpression(Identifier(a) ?? BinaryExpression(Identifier(b) | Identifier(c)))`},
+	{"(a || b) ?? c", `BinaryExpression(BinaryExpression(Identifier(a) || Identifier(b)) ?? Identifier(c))`},
+	{"a ?? (b && c)", `BinaryExpression(Identifier(a) ?? BinaryExpression(Identifier(b) && Identifier(c)))`},
+	{"a ?? b || c", ""},
+	{"a && b ?? c", ""},
+	// Logical OR / AND
+	{"a || b && c", `BinaryExpression(Identifier(a) || BinaryExpression(Identifier(b) && Identifier(c)))`},
+	{"a || b || c", `BinaryExpression(BinaryExpression(Identifier(a) || Identifier(b)) || Identifier(c))`},
+	{"a && b | c", `BinaryExpression(Identifier(a) && BinaryExpression(Identifier(b) | Identifier(c)))`},
+	// Bitwise OR / XOR / AND
+	{"a | b ^ c", `BinaryExpression(Identifier(a) | BinaryExpression(Identifier(b) ^ Identifier(c)))`},
+	{"a ^ b & c", `BinaryExpression(Identifier(a) ^ BinaryExpression(Identifier(b) & Identifier(c)))`},
+	{"a & b == c", `BinaryExpression(Identifier(a) & BinaryExpression(Identifier(b) == Identifier(c)))`},
+	// Equality
+	{"a === b < c", `BinaryExpression(Identifier(a) === BinaryExpression(Identifier(b) < Identifier(c)))`},
+	{"a !== b != c", `BinaryExpression(BinaryExpression(Identifier(a) !== Identifier(b)) != Identifier(c))`},
+	// Relational
+	{"a < b in obj", `BinaryExpression(BinaryExpression(Identifier(a) < Identifier(b)) in Identifier(obj))`},
+	{"a instanceof b >= c", `BinaryExpression(BinaryExpression(Identifier(a) instanceof Identifier(b)) >= Identifier(c))`},
+	{"a <= b << c", `BinaryExpression(Identifier(a) <= BinaryExpression(Identifier(b) << Identifier(c)))`},
+	// Shift
+	{"a >>> b >> c", `BinaryExpression(BinaryExpression(Identifier(a) >>> Identifier(b)) >> Identifier(c))`},
+	{"a << b + c", `BinaryExpression(Identifier(a) << BinaryExpression(Identifier(b) + Identifier(c)))`},
+	// Additive
+	{"a - b - c", `BinaryExpression(BinaryExpression(Identifier(a) - Identifier(b)) - Identifier(c))`},
+	{"a + b * c", `BinaryExpression(Identifier(a) + BinaryExpression(Identifier(b) * Identifier(c)))`},
+	// Multiplicative
+	{"a % b / c", `BinaryExpression(BinaryExpression(Identifier(a) % Identifier(b)) / Identifier(c))`},
+	{"a * b ** c", `BinaryExpression(Identifier(a) * BinaryExpression(Identifier(b) ** Identifier(c)))`},
+	// Exponentiation (right-associative, no unary left operand)
+	{"a ** b ** c", `BinaryExpression(Identifier(a) ** BinaryExpression(Identifier(b) ** Identifier(c)))`},
+	{"(-a) ** b", `BinaryExpression((-Identifier(a)) ** Identifier(b))`},
+	{"a ** -b", `BinaryExpression(Identifier(a) ** (-Identifier(b)))`},
+	{"++a ** b", `BinaryExpression(UpdateExpression(++Identifier(a)) ** Identifier(b))`},
+	{"-a ** b", ""},
+	{"typeof a ** b", ""},
+	// Unary
+	{"typeof a + b", `BinaryExpression((typeof Identifier(a)) + Identifier(b))`},
+	{"void a in b", `BinaryExpression((void Identifier(a)) in Identifier(b))`},
+	{"~a * !b", `BinaryExpression((~Identifier(a)) * (!Identifier(b)))`},
+	{"-a.b(c)", `(-CallExpression(MemberExpression(Identifier(a).Identifier(b))(args=Identifier(c))))`},
+	// Postfix
+	{"-a++", `(-UpdateExpression(Identifier(a)++))`},
+	{"!a--", `(!UpdateExpression(Identifier(a)--))`},
+	{"a++ + b", `BinaryExpression(UpdateExpression(Identifier(a)++) + Identifier(b))`},
+	// Number literals keep their value as a double
+	{"1.5 + 2e3 + 0x10 + 010 + 019 + 9007199254740993", `BinaryExpression(BinaryExpression(BinaryExpression(BinaryExpression(BinaryExpression(IntegerLiteral(1.5) + IntegerLiteral(2000)) + IntegerLiteral(16)) + IntegerLiteral(8)) + IntegerLiteral(19)) + IntegerLiteral(9.007199254740992e+15))`},
 }
@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"fmt"
+	"gojo/lexer"
+	. "gojo/parser"
+	"gojo/printer"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type PrinterTestCase struct {
+	Input    string
+	Expected string
+	Module   bool // Whether the input is parsed as an ES module rather than a script
+	Config   printer.Config
+}
+
+func TestPrinter(t *testing.T) {
+	for _, test := range printerTestCases {
+		t.Run(
+			test.Input,
+			func(t *testing.T) {
+				program := parseLocated(t, test.Input, test.Module)
+				received := test.Config.Print(program)
+				if received != test.Expected {
+					t.Fatalf("\nExpected: %s\nReceived: %s\n", test.Expected, received)
+				}
+			},
+		)
+	}
+}
+
+// TestPrinterBuiltNodes checks the parentheses of trees built by hand, without source text or locations.
+func TestPrinterBuiltNodes(t *testing.T) {
+	a, b := &Identifier{Value: "a"}, &Identifier{Value: "b"}
+	sum := &BinaryExpression{Left: a, Operator: "+", Right: b}
+	tests := []struct {
+		Node     Node
+		Expected string
+	}{
+		{&BinaryExpression{Left: sum, Operator: "*", Right: &IntegerLiteral{Value: -2}}, "(a + b) * -2"},
+		{&MemberExpression{Object: &IntegerLiteral{Value: -2}, Property: a}, "(-2).a"},
+		{&PrefixExpression{Operator: "-", Right: &IntegerLiteral{Value: -2}}, "- -2"},
+		{&NewExpression{Callee: &CallExpression{Function: a}}, "new (a())()"},
+		{&BinaryExpression{Left: &IntegerLiteral{Value: 0.5}, Operator: "+", Right: &IntegerLiteral{Value: 1e21}},
+			"0.5 + 1e+21"},
+		{&StringLiteral{Value: "\x00\u2028\n'é"}, `"\x00\u2028\n'é"`},
+		{&ExpressionStatement{Expression: &CallExpression{Function: &FunctionExpression{Body: &BlockStatement{}}}},
+			"(function () {}());"},
+		{&VariableDeclaration{IsConstant: true, Declarations: []*VariableDeclarator{{Name: a, Value: sum}}},
+			"const a = a + b;"},
+	}
+	for _, test := range tests {
+		if received := printer.Print(test.Node); received != test.Expected {
+			t.Fatalf("\nExpected: %s\nReceived: %s\n", test.Expected, received)
+		}
+	}
+}
+
+// TestPrinterRoundTrip checks that printing the test programs then parsing them again gives back the same AST,
+// and that printing is stable, whatever the configuration.
+func TestPrinterRoundTrip(t *testing.T) {
+	files, _ := filepath.Glob("data/*/*.js")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Could not read file: %q", file)
+		}
+		module := false
+		program, errors := New(lexer.New(string(data))).ParseProgram()
+		if len(errors) != 0 {
+			module = true
+			program, errors = New(lexer.New(string(data))).ParseModule()
+		}
+		if len(errors) != 0 {
+			continue
+		}
+
+		for _, config := range printerConfigs {
+			t.Run(fmt.Sprintf("%s %+v", file, config), func(t *testing.T) {
+				printed := config.Print(program)
+				reparsed := parseLocated(t, printed, module)
+				if reparsed.String() != program.String() || reparsed.Strict != program.Strict {
+					t.Fatalf("\nPrinted: %s\nExpected: %v\nReceived: %v\n", printed, program.String(),
+						reparsed.String())
+				}
+				if len(reparsed.Comments) != len(program.Comments) {
+					t.Fatalf("\nPrinted: %s\nExpected %d comments, received %d", printed, len(program.Comments),
+						len(reparsed.Comments))
+				}
+				if reprinted := config.Print(reparsed); reprinted != printed {
+					t.Fatalf("\nExpected: %s\nReceived: %s\n", printed, reprinted)
+				}
+			})
+		}
+	}
+}
+
+var printerConfigs = []printer.Config{
+	{},
+	{Indent: "\t", SingleQuote: true, OmitSemicolons: true},
+	{Indent: "    ", Width: 40},
+	{OmitSemicolons: true, Width: 20},
+}
+
+var printerTestCases = []PrinterTestCase{
+	{
+		// Only the parentheses the precedence of the operators requires are kept
+		Input:    "x = ((a + b) + (c * d)) * (e - (f - g)) / (h % i)",
+		Expected: "x = (a + b + c * d) * (e - (f - g)) / (h % i);\n",
+	},
+	{
+		Input:    "x = (a = b) || (e => f)",
+		Expected: "x = (a = b) || ((e) => f);\n",
+	},
+	{
+		Input:    "x = (a = b) ? (c, d) : e => (f, g)",
+		Expected: "x = (a = b) ? (c, d) : (e) => (f, g);\n",
+	},
+	{
+		Input:    "(a || b) ?? (c && d); (a ?? b) || c",
+		Expected: "(a || b) ?? (c && d);\n(a ?? b) || c;\n",
+	},
+	{
+		Input:    "x = typeof (-a) + (!b).c + -(-c) + (a || b)()",
+		Expected: "x = typeof -a + (!b).c + - -c + (a || b)();\n",
+	},
+	{
+		Input:    "x = (a ? b : c)() + -(-c++) + (a, b)",
+		Expected: "x = (a ? b : c)() + - -c++ + (a, b);\n",
+	},
+	{
+		Input:    "new (a.b().c)(); new (a.b.c)(); (new a)(); (a.b)``",
+		Expected: "new (a.b().c)();\nnew a.b.c();\nnew a()();\na.b``;\n",
+	},
+	{
+		// Statements starting with { or function keep parentheses
+		Input:    "({ a }).a = b; (function () {}).call(); (async function () {})(); x = () => ({})",
+		Expected: "({ a }.a = b);\n(function () {}.call());\n(async function () {}());\nx = () => ({});\n",
+	},
+	{
+		// The other quote is used when it needs fewer escapes
+		Input:    "x = 'it\\'s' + \"\\\"q\\\"\\n\" + 0x1F + 017",
+		Expected: "x = \"it's\" + '\"q\"\\n' + 0x1F + 017;\n",
+	},
+	{
+		// Strings keep the escapes of their source text, only their quotes change
+		Input:    "x = 'h\\u00e9 é \\x41 \\u{1F600}' + 'it\\x27s' + \"\\0\" + '\\u2028 \\\"'",
+		Expected: "x = \"h\\u00e9 é \\x41 \\u{1F600}\" + \"it\\x27s\" + \"\\0\" + '\\u2028 \"';\n",
+	},
+	{
+		// Numbers keep their source text, decimal ones are parenthesized before a dot
+		Input:    "x = 1.50 * 2e-3 - 1E21 + 1..toString() + 2.5.toFixed() + 0b11.x",
+		Expected: "x = 1.50 * 2e-3 - 1E21 + (1.).toString() + (2.5).toFixed() + 0b11.x;\n",
+	},
+	{
+		// BigInts keep their source text
+		Input:    "x = 0x1Fn * -2n + 10n.toString()",
+		Expected: "x = 0x1Fn * -2n + 10n.toString();\n",
+	},
+	{
+		// Single blank lines are kept, comments stay between the statements they were between
+		Input:    "a(); // a\n\n\n/* b */ b(/* c */);\nfunction f() {\n  // d\n}\n// e",
+		Expected: "a(); // a\n\n/* b */\nb(); /* c */\nfunction f() {\n  // d\n}\n// e\n",
+	},
+	{
+		Input:    "if (a) {} else { if (b) {} else {} }\nwhile (a) { if (b) { break; } }",
+		Expected: "if (a) {} else if (b) {} else {}\nwhile (a) {\n  if (b) {\n    break;\n  }\n}\n",
+	},
+	{
+		Input: "import a, * as b from \"m\"; import c, { d as e, f } from 'm'; import 'n';\n" +
+			"export { a as x, b }; export * as y from \"m\"; export default function () {}",
+		Expected: "import a, * as b from \"m\";\nimport c, { d as e, f } from \"m\";\nimport \"n\";\n" +
+			"export { a as x, b };\nexport * as y from \"m\";\nexport default function () {}\n",
+		Module: true,
+	},
+	{
+		Input:    "export default (function () {}); export const z = 1;",
+		Expected: "export default (function () {});\nexport const z = 1;\n",
+		Module:   true,
+	},
+	{
+		// In the head of a for loop, in operators keep parentheses so as not to start a for...in loop
+		Input:    "for (var i = (a in b), j; !(i in c);) {} for (x = f(a in b);;); for (k in (a, b)) {}",
+		Expected: "for (var i = (a in b), j; !(i in c);) {}\nfor (x = f(a in b);;);\nfor (k in a, b) {}\n",
+	},
+	{
+		Input:    "x = 'a' + \"b\" + \"it's\"",
+		Expected: "x = 'a' + 'b' + \"it's\";\n",
+		Config:   printer.Config{SingleQuote: true},
+	},
+	{
+		// Statements starting with ( [ ` + - or / get a semicolon to not continue the previous one
+		Input:    "a = b; (c || d).e(); [1, 2].forEach(f); `t`.length; -g; while (a) { a = a - 1; }",
+		Expected: "a = b\n;(c || d).e()\n;[1, 2].forEach(f)\n;`t`.length\n;-g\nwhile (a) {\n  a = a - 1\n}\n",
+		Config:   printer.Config{OmitSemicolons: true},
+	},
+	{
+		// Lists that do not fit are broken, objects with methods too
+		Input: "f(aaaaaaaaaa, [bbbbbbbbbb, cccccccccc], { d: 1 }); " +
+			"x = { e() { f(); } }; function g(hhhhhhhhhh, iiiiiiiiii) {}",
+		Expected: "f(\n\taaaaaaaaaa,\n\t[bbbbbbbbbb, cccccccccc],\n\t{ d: 1 }\n);\n" +
+			"x = {\n\te() {\n\t\tf();\n\t},\n};\nfunction g(\n\thhhhhhhhhh,\n\tiiiiiiiiii\n) {}\n",
+		Config: printer.Config{Indent: "\t", Width: 30},
+	},
+	{
+		Input: "import aaaaaaaaaa, { bbbbbbbbbb, cccccccccc as d } from \"m\"; export { dddddd, bbbbbbbbbb as eeeeeeeeee };",
+		Expected: "import aaaaaaaaaa, {\n  bbbbbbbbbb,\n  cccccccccc as d,\n} from \"m\";\n" +
+			"export {\n  dddddd,\n  bbbbbbbbbb as eeeeeeeeee,\n};\n",
+		Config: printer.Config{Width: 40},
+		Module: true,
+	},
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"gojo/regex"
+	"testing"
+)
+
+type RegexTestCase struct {
+	Pattern  string
+	Flags    string
+	Input    string
+	Expected []string // Expected captures, "<nil>" for unmatched groups, nil when nothing matches
+}
+
+// TestRegex checks the matching semantics of the engine on the examples of the specification.
+func TestRegex(t *testing.T) {
+	for _, test := range regexTestCases {
+		t.Run(
+			"/"+test.Pattern+"/"+test.Flags,
+			func(t *testing.T) {
+				CompareRegexMatch(t, test)
+			},
+		)
+	}
+}
+
+func CompareRegexMatch(t *testing.T, test RegexTestCase) {
+	re, err := regex.Compile(test.Pattern, test.Flags)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	captures := re.FindAt(test.Input, 0)
+	if captures == nil {
+		if test.Expected != nil {
+			t.Fatalf("\nExpected: %q\nReceived: no match\n", test.Expected)
+		}
+		return
+	}
+
+	var received []string
+	for group := 0; group <= re.NumGroups(); group++ {
+		if captures[2*group] < 0 {
+			received = append(received, "<nil>")
+			continue
+		}
+		received = append(received, test.Input[captures[2*group]:captures[2*group+1]])
+	}
+	if len(received) != len(test.Expected) {
+		t.Fatalf("\nExpected: %q\nReceived: %q\n", test.Expected, received)
+	}
+	for idx := range received {
+		if received[idx] != test.Expected[idx] {
+			t.Fatalf("\nExpected: %q\nReceived: %q\n", test.Expected, received)
+		}
+	}
+}
+
+var regexTestCases = []RegexTestCase{
+	// Alternatives and quantifiers
+	{"a|ab", "", "abc", []string{"a"}},
+	{"((a)|(ab))((c)|(bc))", "", "abc", []string{"abc", "a", "a", "<nil>", "bc", "<nil>", "bc"}},
+	{"a[a-z]{2,4}", "", "abcdefghi", []string{"abcde"}},
+	{"a[a-z]{2,4}?", "", "abcdefghi", []string{"abc"}},
+	{"(aa|aabaac|ba|b|c)*", "", "aabaac", []string{"aaba", "ba"}},
+	{"(z)((a+)?(b+)?(c))*", "", "zaacbbbcac", []string{"zaacbbbcac", "z", "ac", "a", "<nil>", "c"}},
+	{"(a*)*", "", "b", []string{"", "<nil>"}},
+	{"(a*)b\\1+", "", "baaaac", []string{"b", ""}},
+	{"x{,2}", "", "x{,2}", []string{"x{,2}"}},
+	// Lookarounds and back references
+	{"(?=(a+))", "", "baaabac", []string{"", "aaa"}},
+	{"(?=(a+))a*b\\1", "", "baaabac", []string{"aba", "a"}},
+	{"(.*?)a(?!(a+)b\\2c)\\2(.*)", "", "baaabaac", []string{"baaabaac", "ba", "<nil>", "abaac"}},
+	{"(?<=\\$)\\d+(\\.\\d*)?", "", "cost $10.53", []string{"10.53", ".53"}},
+	{"(?<=(\\d+)(\\d+))$", "", "1053", []string{"", "1", "053"}},
+	{"(?<!\\$)\\b\\d+", "", "$10 20", []string{"20"}},
+	{"(?<year>\\d{4})-\\k<year>", "", "2020-2020", []string{"2020-2020", "2020"}},
+	// Flags
+	{"^b", "m", "a\nb", []string{"b"}},
+	{"^b", "", "a\nb", nil},
+	{"a.c", "s", "a\nc", []string{"a\nc"}},
+	{"ß", "iu", "ẞ", []string{"ẞ"}},
+	{"[^a]", "u", "😀", []string{"😀"}},
+	{"\\p{Script=Greek}+", "u", "abγδε", []string{"γδε"}},
+	{"[\\p{L}--[a-z]]+", "v", "abCDé", []string{"CDé"}},
+	{"[\\q{abc|d}]", "v", "xabc", []string{"abc"}},
+}
+
+type RegexErrorTestCase struct {
+	Pattern string
+	Flags   string
+	Error   string
+}
+
+func TestRegexErrors(t *testing.T) {
+	for _, test := range regexErrorTestCases {
+		t.Run(
+			"/"+test.Pattern+"/"+test.Flags,
+			func(t *testing.T) {
+				_, err := regex.Compile(test.Pattern, test.Flags)
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("\nExpected: %v\nReceived: %v\n", test.Error, err)
+				}
+			},
+		)
+	}
+}
+
+var regexErrorTestCases = []RegexErrorTestCase{
+	{"(a", "", "Unterminated group"},
+	{"*a", "", "Nothing to repeat"},
+	{"a", "gg", "Invalid flags"},
+	{"a", "uv", "Invalid flags"},
+	{"x{,2}", "u", "Incomplete quantifier"},
+	{"\\k<missing>(?<name>a)", "", "Invalid named capture referenced"},
+	{"[z-a]", "", "Range out of order in character class"},
+}
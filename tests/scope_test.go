@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"fmt"
+	"gojo/lexer"
+	. "gojo/parser"
+	"gojo/scope"
+	"os"
+	"testing"
+)
+
+type ScopeTestCase struct {
+	Name     string
+	Expected string // The scope tree, as printed by scope.Info.String
+	Module   bool   // Whether the input is parsed as an ES module rather than a script
+}
+
+func TestScope(t *testing.T) {
+	for _, test := range scopeTestCases {
+		t.Run(
+			test.Name,
+			func(t *testing.T) {
+				filePath := fmt.Sprintf("data/scope/%s.js", test.Name)
+				data, err := os.ReadFile(filePath)
+				if err != nil {
+					t.Fatalf("Could not read file: %q", filePath)
+				}
+				parser := New(lexer.New(string(data)))
+				var program *Program
+				var errors []*ParseError
+				if test.Module {
+					program, errors = parser.ParseModule()
+				} else {
+					program, errors = parser.ParseProgram()
+				}
+				if len(errors) != 0 {
+					t.Fatalf("Unexpected parser errors: %v", errors)
+				}
+
+				received := scope.Analyze(program).String()
+				if received != test.Expected {
+					t.Fatalf("\nExpected:\n%s\nReceived:\n%s\n", test.Expected, received)
+				}
+			},
+		)
+	}
+}
+
+var scopeTestCases = []ScopeTestCase{
+	{
+		// Closures, with statements and catch clauses
+		Name: "Test1",
+		Expected: "" +
+			"global 1:1\n" +
+			"  var a 1:5 captured refs 5:20?\n" +
+			"  function outer 2:10 refs 9:7\n" +
+			"  function 2:1 captures a\n" +
+			"    parameter p 2:16 captured refs 3:11 5:11\n" +
+			"    let b 3:7 captured refs 5:16=? 6:18\n" +
+			"    function 4:10 captures p b a\n" +
+			"      function inner 4:19 captured refs 6:22\n" +
+			"      block 5:14\n" +
+			"      function 6:12 captures b inner\n" +
+			"  block 9:5\n" +
+			"  catch 9:18\n" +
+			"    catch parameter e 9:25 refs 9:38\n" +
+			"    let f 9:34\n" +
+			"unresolved c 5:24?\n",
+	},
+	{
+		// Hoisting, block scopes and function expression names
+		Name: "Test2",
+		Expected: "" +
+			"module 1:1\n" +
+			"  import m 1:10 captured refs 5:7\n" +
+			"  function hoisted 4:10 refs 3:1\n" +
+			"  var later 11:5 refs 2:10\n" +
+			"  let value 12:5 refs 11:13 13:21\n" +
+			"  const f 19:7\n" +
+			"  const g 20:7\n" +
+			"  function 4:1 captures m\n" +
+			"    var x 6:9 captured refs 7:32\n" +
+			"    block 5:10\n" +
+			"      function nested 7:14\n" +
+			"      function 7:5 captures x\n" +
+			"  block 13:1\n" +
+			"    const item 13:12 refs 14:11 16:15\n" +
+			"    block 13:29\n" +
+			"      block 14:3\n" +
+			"        let y 16:11\n" +
+			"  function 19:11\n" +
+			"    parameter f 19:22 refs 19:34\n" +
+			"  function 20:11\n" +
+			"    function g 20:20 refs 20:33\n" +
+			"unresolved nested 9:10\n",
+		Module: true,
+	},
+	{
+		// Functions over vars and parameters, and sloppy mode functions of blocks that are also vars
+		Name: "Test3",
+		Expected: "" +
+			"global 1:1\n" +
+			"  function twice 1:5\n" +
+			"  function outer 3:10\n" +
+			"  function 2:1\n" +
+			"  function 3:1\n" +
+			"    function a 3:16 refs 5:7\n" +
+			"    let shadowed 8:7\n" +
+			"    block function inner 6:14\n" +
+			"    block function e 15:16\n" +
+			"    function 4:3\n" +
+			"    block 5:10\n" +
+			"      function inner 6:14\n" +
+			"      function 6:5\n" +
+			"    block 9:3\n" +
+			"      function shadowed 10:14\n" +
+			"      function 10:5\n" +
+			"    block 12:7\n" +
+			"    catch 13:5\n" +
+			"      catch parameter e 13:12\n" +
+			"      block 14:5\n" +
+			"        function e 15:16\n" +
+			"        function 15:7\n",
+	},
+}
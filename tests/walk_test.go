@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"gojo/astutil"
+	"gojo/lexer"
+	. "gojo/parser"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWalkCorpus checks that Inspect and Apply visit every node of the test programs, in the order of the fields.
+func TestWalkCorpus(t *testing.T) {
+	files, _ := filepath.Glob("data/*/*.js")
+	programs := map[string]*Program{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Could not read file: %q", file)
+		}
+		program, errors := New(lexer.New(string(data))).ParseProgram()
+		if len(errors) != 0 {
+			program, errors = New(lexer.New(string(data))).ParseModule()
+		}
+		if len(errors) == 0 {
+			programs[file] = program
+		}
+	}
+	// Nodes the test programs lack
+	programs["module"] = parseLocated(t, "import * as a from \"a\";\nexport * as b from \"b\";\n"+
+		"if (a) {} else if (b) {} else {}", true)
+
+	for file, program := range programs {
+		t.Run(file, func(t *testing.T) {
+			var expected []Node
+			walkNodes(program, nil, func(node Node, parent Node) {
+				expected = append(expected, node)
+			})
+
+			var inspected []Node
+			depth := 0
+			Inspect(program, func(node Node) bool {
+				if node == nil {
+					depth--
+					return false
+				}
+				depth++
+				inspected = append(inspected, node)
+				return true
+			})
+			if depth != 0 {
+				t.Fatalf("Inspect called f(nil) %d times less than f(node)", depth)
+			}
+
+			var applied []Node
+			astutil.Apply(program, func(cursor *astutil.Cursor) bool {
+				applied = append(applied, cursor.Node())
+				return true
+			}, nil)
+
+			for name, received := range map[string][]Node{"Inspect": inspected, "Apply": applied} {
+				if len(received) != len(expected) {
+					t.Fatalf("%s visited %d nodes, expected %d", name, len(received), len(expected))
+				}
+				for idx := range expected {
+					if received[idx] != expected[idx] {
+						t.Fatalf("%s visited %s, expected %s", name, received[idx], expected[idx])
+					}
+				}
+			}
+		})
+	}
+}
+
+type ApplyTestCase struct {
+	Name     string
+	Input    string
+	Pre      astutil.ApplyFunc
+	Post     astutil.ApplyFunc
+	Expected string
+}
+
+func TestApply(t *testing.T) {
+	for _, test := range applyTestCases {
+		t.Run(
+			test.Name,
+			func(t *testing.T) {
+				program := parseLocated(t, test.Input, false)
+				received := astutil.Apply(program, test.Pre, test.Post)
+				if received.String() != test.Expected {
+					t.Fatalf("\nExpected: %v\nReceived: %v\n", test.Expected, received.String())
+				}
+			},
+		)
+	}
+}
+
+var applyTestCases = []ApplyTestCase{
+	{
+		Name:  "Replace identifiers",
+		Input: "let a = 1; a = a + b;",
+		Pre: func(cursor *astutil.Cursor) bool {
+			if identifier, ok := cursor.Node().(*Identifier); ok && identifier.Value == "a" {
+				cursor.Replace(&Identifier{Value: "x"})
+			}
+			return true
+		},
+		Expected: `Program(VariableDeclaration(let Identifier(x) = IntegerLiteral(1))ExpressionStatement(AssignmentExpression(Identifier(x) = BinaryExpression(Identifier(x) + Identifier(b)))))`,
+	},
+	{
+		Name:  "Delete statements",
+		Input: "log(1); f(); log(2); log(3);",
+		Pre: func(cursor *astutil.Cursor) bool {
+			if isCallTo(cursor.Node(), "log") {
+				cursor.Delete()
+				return false
+			}
+			return true
+		},
+		Expected: `Program(ExpressionStatement(CallExpression(Identifier(f)(args=))))`,
+	},
+	{
+		Name:  "Insert statements",
+		Input: "function g() { f(); }",
+		Pre: func(cursor *astutil.Cursor) bool {
+			if isCallTo(cursor.Node(), "f") {
+				cursor.InsertBefore(callStatement("before"))
+				cursor.InsertAfter(callStatement("after"))
+			}
+			return true
+		},
+		Expected: `Program(FunctionDeclaration(Identifier(g)() {ExpressionStatement(CallExpression(Identifier(before)(args=)))ExpressionStatement(CallExpression(Identifier(f)(args=)))ExpressionStatement(CallExpression(Identifier(after)(args=)))}))`,
+	},
+	{
+		// Post-order sees the folded operands of the outer addition
+		Name:  "Fold constants",
+		Input: "x = 1 + 2 + 3 * y",
+		Post: func(cursor *astutil.Cursor) bool {
+			binary, ok := cursor.Node().(*BinaryExpression)
+			if !ok || binary.Operator != "+" {
+				return true
+			}
+			left, leftOk := binary.Left.(*IntegerLiteral)
+			right, rightOk := binary.Right.(*IntegerLiteral)
+			if leftOk && rightOk {
+				cursor.Replace(&IntegerLiteral{Loc: binary.Loc, Value: left.Value + right.Value})
+			}
+			return true
+		},
+		Expected: `Program(ExpressionStatement(AssignmentExpression(Identifier(x) = BinaryExpression(IntegerLiteral(3) + BinaryExpression(IntegerLiteral(3) * Identifier(y))))))`,
+	},
+	{
+		Name:  "Clear optional fields",
+		Input: "function f() { return g(); }",
+		Pre: func(cursor *astutil.Cursor) bool {
+			if cursor.Name() == "Value" {
+				if _, ok := cursor.Parent().(*ReturnStatement); ok {
+					cursor.Replace(nil)
+				}
+			}
+			return true
+		},
+		Expected: `Program(FunctionDeclaration(Identifier(f)() {ReturnStatement()}))`,
+	},
+	{
+		Name:  "Stop the traversal",
+		Input: "a; b; c;",
+		Post: func(cursor *astutil.Cursor) bool {
+			if identifier, ok := cursor.Node().(*Identifier); ok {
+				cursor.Replace(&Identifier{Value: identifier.Value + "2"})
+				return identifier.Value != "b"
+			}
+			return true
+		},
+		Expected: `Program(ExpressionStatement(Identifier(a2))ExpressionStatement(Identifier(b2))ExpressionStatement(Identifier(c)))`,
+	},
+	{
+		Name:  "Replace the root",
+		Input: "a;",
+		Pre: func(cursor *astutil.Cursor) bool {
+			if _, ok := cursor.Node().(*Program); ok {
+				cursor.Replace(&Program{Statements: []Statement{callStatement("b")}})
+			}
+			return true
+		},
+		Expected: `Program(ExpressionStatement(CallExpression(Identifier(b)(args=))))`,
+	},
+}
+
+// isCallTo tells whether a node is an expression statement calling a function by name.
+func isCallTo(node Node, name string) bool {
+	stmt, ok := node.(*ExpressionStatement)
+	if !ok {
+		return false
+	}
+	call, ok := stmt.Expression.(*CallExpression)
+	if !ok {
+		return false
+	}
+	function, ok := call.Function.(*Identifier)
+	return ok && function.Value == name
+}
+
+func callStatement(name string) *ExpressionStatement {
+	return &ExpressionStatement{Expression: &CallExpression{Function: &Identifier{Value: name}}}
+}